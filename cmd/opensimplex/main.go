@@ -0,0 +1,207 @@
+// Command opensimplex renders a 2D slice of OpenSimplex noise to an image or a
+// data file, so seeds can be previewed without writing Go.
+//
+// Usage:
+//
+//	opensimplex [flags]
+//
+// For example, to render six octaves of 3D noise sliced at z=3.8 to a PNG:
+//
+//	opensimplex -seed 42 -dims 3 -z 3.8 -octaves 6 -o noise.png
+//
+// Samples are normalized into [0, 1). The output format is taken from the
+// -format flag, or else from the extension of the -o file, and defaults to PNG.
+// Run with -h for the full list of flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.sdls.io/opensimplex/pkg/opensimplex"
+)
+
+// Set by the build, see the Makefile.
+var (
+	_serviceName = "opensimplex"
+	_version     = "dev"
+	_buildTime   = ""
+	_buildHash   = ""
+)
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", _serviceName, err)
+		os.Exit(1)
+	}
+}
+
+type config struct {
+	seed       int64
+	dims       int
+	x, y, z, w float64
+	scale      float64
+	width      int
+	height     int
+	fractal    string
+	octaves    int
+	lacunarity float64
+	gain       float64
+	format     string
+	out        string
+}
+
+func parseFlags(args []string, stdout, stderr io.Writer) (*config, error) {
+	c := &config{}
+	fs := flag.NewFlagSet(_serviceName, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	fs.Int64Var(&c.seed, "seed", 0, "noise seed")
+	fs.IntVar(&c.dims, "dims", 2, "noise dimensions: 2, 3 or 4")
+	fs.Float64Var(&c.x, "x", 0, "x coordinate of the top left sample")
+	fs.Float64Var(&c.y, "y", 0, "y coordinate of the top left sample")
+	fs.Float64Var(&c.z, "z", 0, "z coordinate the slice is pinned to, for 3 and 4 dimensions")
+	fs.Float64Var(&c.w, "w", 0, "w coordinate the slice is pinned to, for 4 dimensions")
+	fs.Float64Var(&c.scale, "scale", 1.0/24, "size of a sample in noise space")
+	fs.IntVar(&c.width, "width", 512, "number of samples along x")
+	fs.IntVar(&c.height, "height", 512, "number of samples along y")
+	fs.StringVar(&c.fractal, "fractal", "fbm", "fractal wrapper: fbm, billow or ridged")
+	fs.IntVar(&c.octaves, "octaves", 1, "number of fractal octaves")
+	fs.Float64Var(&c.lacunarity, "lacunarity", 2, "frequency multiplier between octaves")
+	fs.Float64Var(&c.gain, "gain", 0.5, "amplitude multiplier between octaves, ignored by ridged")
+	fs.StringVar(&c.format, "format", "", "output format: png, pgm, f32, csv or json (default from -o, else png)")
+	fs.StringVar(&c.out, "o", "-", "output file, - for stdout")
+	version := fs.Bool("version", false, "print the version and exit")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+	if *version {
+		fmt.Fprintf(stdout, "%s %s\n", _serviceName, _version)
+		if _buildHash != "" {
+			fmt.Fprintf(stdout, "build %s at %s\n", _buildHash, _buildTime)
+		}
+		return nil, flag.ErrHelp
+	}
+
+	if c.dims < 2 || c.dims > 4 {
+		return nil, fmt.Errorf("-dims must be 2, 3 or 4, not %d", c.dims)
+	}
+	if c.width < 1 || c.height < 1 {
+		return nil, fmt.Errorf("-width and -height must be positive")
+	}
+	if c.octaves < 1 {
+		return nil, fmt.Errorf("-octaves must be positive")
+	}
+	if c.format == "" {
+		c.format = strings.TrimPrefix(strings.ToLower(filepath.Ext(c.out)), ".")
+		if _, ok := encoders[c.format]; !ok {
+			c.format = "png"
+		}
+	}
+	if _, ok := encoders[c.format]; !ok {
+		return nil, fmt.Errorf("unknown -format %q", c.format)
+	}
+
+	return c, nil
+}
+
+// noise builds the normalized noise described by the flags.
+func (c *config) noise() (opensimplex.Noise, error) {
+	switch c.fractal {
+	case "fbm":
+		if c.octaves == 1 {
+			return opensimplex.NewNormalized(c.seed), nil
+		}
+		return opensimplex.NewFBM(opensimplex.NewNormalized(c.seed), c.octaves, c.lacunarity, c.gain), nil
+	case "billow":
+		return &unitRange{opensimplex.NewBillow(opensimplex.New(c.seed), c.octaves, c.lacunarity, c.gain)}, nil
+	case "ridged":
+		return &unitRange{opensimplex.NewRidged(opensimplex.New(c.seed), c.octaves, c.lacunarity)}, nil
+	default:
+		return nil, fmt.Errorf("unknown -fractal %q", c.fractal)
+	}
+}
+
+// sample fills a width*height slice with the noise, row by row.
+func (c *config) sample(n opensimplex.Noise) []float64 {
+	spec := opensimplex.GridSpec{
+		Dims:   c.dims,
+		Origin: [4]float64{c.x, c.y, c.z, c.w},
+		Step:   [3]float64{c.scale, c.scale, 0},
+		Size:   [3]int{c.width, c.height, 1},
+	}
+
+	dst := make([]float64, spec.Len())
+	opensimplex.FillGrid(n, dst, spec)
+	return dst
+}
+
+func run(args []string, stdout, stderr io.Writer) (err error) {
+	c, err := parseFlags(args, stdout, stderr)
+	if err != nil {
+		return err
+	}
+
+	n, err := c.noise()
+	if err != nil {
+		return err
+	}
+	samples := c.sample(n)
+
+	out := stdout
+	if c.out != "-" {
+		f, err := os.Create(c.out)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		out = f
+	}
+
+	return encoders[c.format](out, samples, c.width, c.height)
+}
+
+// unitRange maps the [-1, 1] output of a fractal wrapper into [0, 1).
+type unitRange struct {
+	base opensimplex.Noise
+}
+
+func (u *unitRange) Eval2(x, y float64) float64 {
+	return toUnit(u.base.Eval2(x, y))
+}
+
+func (u *unitRange) Eval3(x, y, z float64) float64 {
+	return toUnit(u.base.Eval3(x, y, z))
+}
+
+func (u *unitRange) Eval4(x, y, z, w float64) float64 {
+	return toUnit(u.base.Eval4(x, y, z, w))
+}
+
+func toUnit(v float64) float64 {
+	v = (v + 1) / 2
+	if v < 0 {
+		return 0
+	}
+	if v >= 1 {
+		return 0.9999999999999999
+	}
+	return v
+}
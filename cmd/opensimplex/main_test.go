@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.sdls.io/opensimplex/pkg/opensimplex"
+)
+
+func TestRunJSONMatchesNormalized(t *testing.T) {
+	var stdout bytes.Buffer
+	err := run([]string{"-seed", "7", "-dims", "3", "-z", "3.8", "-width", "4", "-height", "3", "-scale", "0.5", "-format", "json"}, &stdout, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Width, Height int
+		Samples       []float64
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Width != 4 || got.Height != 3 || len(got.Samples) != 12 {
+		t.Fatalf("got a %vx%v grid of %v samples, want 4x3", got.Width, got.Height, len(got.Samples))
+	}
+
+	n := opensimplex.NewNormalized(7)
+	for j := 0; j < 3; j++ {
+		for i := 0; i < 4; i++ {
+			if v, want := got.Samples[j*4+i], n.Eval3(float64(i)*0.5, float64(j)*0.5, 3.8); v != want {
+				t.Fatalf("sample %v, %v = %v, want %v", i, j, v, want)
+			}
+		}
+	}
+}
+
+func TestRunFormatFromExtension(t *testing.T) {
+	dir, err := os.MkdirTemp("", "opensimplex")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, c := range []struct {
+		name string
+		size int
+	}{
+		{"noise.f32", 16 * 8 * 4},
+		{"noise.pgm", len("P5\n16 8\n65535\n") + 16*8*2},
+		{"noise.csv", -1},
+		{"noise.png", -1},
+	} {
+		out := filepath.Join(dir, c.name)
+		if err := run([]string{"-width", "16", "-height", "8", "-octaves", "3", "-o", out}, io.Discard, io.Discard); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+
+		data, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.size >= 0 && len(data) != c.size {
+			t.Errorf("%s is %v bytes, want %v", c.name, len(data), c.size)
+		}
+
+		switch filepath.Ext(c.name) {
+		case ".csv":
+			if lines := strings.Count(string(data), "\n"); lines != 8 {
+				t.Errorf("%s has %v lines, want 8", c.name, lines)
+			}
+		case ".png":
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := img.Bounds(); b.Dx() != 16 || b.Dy() != 8 {
+				t.Errorf("%s has bounds %v, want 16x8", c.name, b)
+			}
+		}
+	}
+}
+
+func TestEncodeFloat32BelowOne(t *testing.T) {
+	var buf bytes.Buffer
+	if err := encodeFloat32(&buf, []float64{0, 0.5, 0.9999999999999999}, 3, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []float32{0, 0.5, math.Nextafter32(1, 0)} {
+		if got := math.Float32frombits(binary.LittleEndian.Uint32(buf.Bytes()[4*i:])); got != want {
+			t.Errorf("sample %d = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestRunRejectsBadFlags(t *testing.T) {
+	for _, args := range [][]string{
+		{"-dims", "5"},
+		{"-width", "0"},
+		{"-octaves", "0"},
+		{"-format", "gif"},
+		{"-fractal", "turbulence"},
+		{"extra"},
+	} {
+		if err := run(args, io.Discard, io.Discard); err == nil {
+			t.Errorf("run(%q) succeeded", args)
+		}
+	}
+}
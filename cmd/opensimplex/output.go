@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+)
+
+// An encoder writes width*height samples in [0, 1), stored row by row, to w.
+type encoder func(w io.Writer, samples []float64, width, height int) error
+
+var encoders = map[string]encoder{
+	"png":  encodePNG,
+	"pgm":  encodePGM,
+	"f32":  encodeFloat32,
+	"csv":  encodeCSV,
+	"json": encodeJSON,
+}
+
+// to16 scales a sample into the range of a 16-bit grey level.
+func to16(v float64) uint16 {
+	v *= 0x10000
+	if v < 0 {
+		return 0
+	}
+	if v > 0xFFFF {
+		return 0xFFFF
+	}
+	return uint16(v)
+}
+
+// encodePNG writes a 16-bit greyscale PNG.
+func encodePNG(w io.Writer, samples []float64, width, height int) error {
+	img := image.NewGray16(image.Rect(0, 0, width, height))
+	for i, v := range samples {
+		binary.BigEndian.PutUint16(img.Pix[2*i:], to16(v))
+	}
+	return png.Encode(w, img)
+}
+
+// encodePGM writes a binary 16-bit greyscale PGM (P5) image.
+func encodePGM(w io.Writer, samples []float64, width, height int) error {
+	bw := bufio.NewWriter(w)
+	if _, err := fmt.Fprintf(bw, "P5\n%d %d\n65535\n", width, height); err != nil {
+		return err
+	}
+
+	var buf [2]byte
+	for _, v := range samples {
+		binary.BigEndian.PutUint16(buf[:], to16(v))
+		if _, err := bw.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// maxFloat32 is the largest float32 below 1. Samples just below 1 round up to 1
+// in float32, so they are clamped to it to keep the output in [0, 1).
+var maxFloat32 = math.Nextafter32(1, 0)
+
+// encodeFloat32 writes the samples as raw little-endian float32 values, without
+// a header.
+func encodeFloat32(w io.Writer, samples []float64, _, _ int) error {
+	bw := bufio.NewWriter(w)
+
+	var buf [4]byte
+	for _, v := range samples {
+		f := float32(v)
+		if f > maxFloat32 {
+			f = maxFloat32
+		}
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(f))
+		if _, err := bw.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// encodeCSV writes one line of comma separated samples per row.
+func encodeCSV(w io.Writer, samples []float64, width, height int) error {
+	bw := bufio.NewWriter(w)
+
+	line := make([]byte, 0, width*20)
+	for j := 0; j < height; j++ {
+		line = line[:0]
+		for i, v := range samples[j*width : (j+1)*width] {
+			if i > 0 {
+				line = append(line, ',')
+			}
+			line = strconv.AppendFloat(line, v, 'g', -1, 64)
+		}
+		line = append(line, '\n')
+		if _, err := bw.Write(line); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// encodeJSON writes {"width": w, "height": h, "samples": [...]}.
+func encodeJSON(w io.Writer, samples []float64, width, height int) error {
+	return json.NewEncoder(w).Encode(struct {
+		Width   int       `json:"width"`
+		Height  int       `json:"height"`
+		Samples []float64 `json:"samples"`
+	}{width, height, samples})
+}
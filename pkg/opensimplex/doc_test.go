@@ -1,6 +1,12 @@
 package opensimplex
 
-import "math/rand"
+import (
+	"image"
+	"image/png"
+	"io"
+	"math/rand"
+	"strings"
+)
 
 func Example() {
 	// #nosec: G404
@@ -16,3 +22,34 @@ func Example() {
 		}
 	}
 }
+
+func ExampleFillGrid() {
+	noise := New(42)
+
+	w, h := 100, 100
+	heightmap := make([]float64, w*h)
+	FillGrid(noise, heightmap, GridSpec{
+		Dims: 2,
+		Step: [3]float64{1 / float64(w), 1 / float64(h)},
+		Size: [3]int{w, h},
+	})
+}
+
+func ExampleColorRamp() {
+	ramp, err := LoadColorRamp(strings.NewReader(`{
+		"interpolation": "smoothstep",
+		"stops": [
+			{"value": 0.40, "color": "#1d3b8a"},
+			{"value": 0.48, "color": "#e8d59a"},
+			{"value": 0.55, "color": "#4c8a2f"},
+			{"value": 0.70, "color": "#7a6e63"},
+			{"value": 0.80, "color": "#ffffff"}
+		]
+	}`))
+	if err != nil {
+		panic(err)
+	}
+
+	terrain := ramp.Image(New(42), image.Rect(0, 0, 256, 256), 1.0/64)
+	_ = png.Encode(io.Discard, terrain)
+}
@@ -0,0 +1,4 @@
+package opensimplex
+
+//go:generate go run ./internal/genlattice
+//go:generate go run ./internal/gensamples
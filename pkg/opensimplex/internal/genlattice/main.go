@@ -0,0 +1,333 @@
+// Command genlattice writes the generated copies of the noise functions:
+//
+//   - opensimplex_lattice.go, the copies that hash lattice vertices through
+//     noise.lattice instead of the 256-entry permutation tables. Keeping two
+//     copies leaves the default path free of any per-vertex dispatch.
+//   - opensimplex_gridcells.go, the copies used by the grid methods, which look
+//     lattice vertices up in a gridCells cache shared by neighbouring samples
+//     and compute no derivatives.
+//   - opensimplex_base32.go, the float32 implementation behind New32, which
+//     computes no derivatives either.
+//
+// It is run by go generate from the opensimplex package directory:
+//
+//	go generate go.sdls.io/opensimplex/pkg/opensimplex
+//
+// Each copied function is renamed with the prefix of its copy, its calls to the
+// other copied functions are renamed likewise, and the leading check that
+// dispatches to the lattice copy is dropped. The lattice copies call gradIndex1
+// to gradIndex4 through s.lattice. The grid copies are methods on *gridCells,
+// whose gradIndex methods do the caching, and lose their derivative parameter.
+// The float32 copies keep their names and are methods on *noise32: float64
+// becomes float32, including in the conversions that keep products from being
+// fused, and the constants and floorLattice become their float32 versions.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"strings"
+)
+
+// source lists the functions on *noise to copy from a file.
+type source struct {
+	file  string
+	funcs []string
+}
+
+// copies lists the generated files.
+var copies = []struct {
+	output  string
+	prefix  string
+	doc     string
+	sources []source
+	grid    bool
+	float32 bool
+}{
+	{
+		output: "opensimplex_lattice.go",
+		prefix: "lattice",
+		doc:    "with the lattice vertices hashed by s.lattice",
+		sources: []source{
+			{"opensimplex_eval1.go", []string{"Eval1", "contrib1"}},
+			{"opensimplex_base.go", []string{"eval2Cell", "eval3Cell", "eval4Cell"}},
+			{"opensimplex_internal.go", []string{"contrib2", "contrib3", "contrib4"}},
+		},
+	},
+	{
+		output: "opensimplex_gridcells.go",
+		prefix: "grid",
+		doc:    "with the vertices looked up in g, without derivatives",
+		sources: []source{
+			{"opensimplex_base.go", []string{"eval2Cell", "eval3Cell", "eval4Cell"}},
+			{"opensimplex_internal.go", []string{"contrib2", "contrib3", "contrib4"}},
+		},
+		grid: true,
+	},
+	{
+		output: "opensimplex_base32.go",
+		doc:    "in float32 arithmetic",
+		sources: []source{
+			{"opensimplex_eval1.go", []string{"Eval1", "contrib1"}},
+			{"opensimplex_base.go", []string{"Eval2", "Eval3", "Eval4", "eval2", "eval2Cell", "eval3", "eval3Cell", "eval4", "eval4Cell"}},
+			{"opensimplex_internal.go", []string{
+				"gradIndex1", "gradIndex2", "gradIndex3", "gradIndex4", "contrib2", "contrib3", "contrib4",
+			}},
+		},
+		float32: true,
+	},
+}
+
+var gradIndex = map[string]bool{"gradIndex1": true, "gradIndex2": true, "gradIndex3": true, "gradIndex4": true}
+
+// float32Names maps the identifiers used by the float64 functions to those the
+// float32 copies use instead.
+var float32Names = map[string]string{
+	"float64":           "float32",
+	"floorLattice":      "floor32",
+	"stretchConstant2D": "stretchConstant2D32",
+	"squishConstant2D":  "squishConstant2D32",
+	"stretchConstant3D": "stretchConstant3D32",
+	"squishConstant3D":  "squishConstant3D32",
+	"stretchConstant4D": "stretchConstant4D32",
+	"squishConstant4D":  "squishConstant4D32",
+}
+
+func main() {
+	for _, c := range copies {
+		renamed := make(map[string]string)
+		for _, src := range c.sources {
+			for _, name := range src.funcs {
+				if c.prefix == "" {
+					renamed[name] = name
+				} else {
+					renamed[name] = c.prefix + strings.ToUpper(name[:1]) + name[1:]
+				}
+			}
+		}
+
+		var buf bytes.Buffer
+		buf.WriteString("// Code generated by go run ./internal/genlattice; DO NOT EDIT.\n\npackage opensimplex\n")
+
+		fset := token.NewFileSet()
+		for _, src := range c.sources {
+			f, err := parser.ParseFile(fset, src.file, nil, parser.ParseComments)
+			if err != nil {
+				fail(err)
+			}
+
+			for _, name := range src.funcs {
+				decl := findMethod(f, name)
+				if decl == nil {
+					fail(fmt.Errorf("%s: no method %s on *noise", src.file, name))
+				}
+				rewrite(fset, decl, f.Comments, renamed, !c.grid && !c.float32)
+				switch {
+				case c.grid:
+					rewriteGrid(decl, renamed)
+				case c.float32:
+					rewriteFloat32(decl, renamed)
+				}
+
+				if c.prefix == "" {
+					name = "noise." + name
+				}
+				fmt.Fprintf(&buf, "\n// %s is %s %s.\n", decl.Name.Name, name, c.doc)
+				if err := printer.Fprint(&buf, fset, &printer.CommentedNode{Node: decl, Comments: f.Comments}); err != nil {
+					fail(err)
+				}
+				buf.WriteString("\n")
+			}
+		}
+
+		out, err := format.Source(buf.Bytes())
+		if err != nil {
+			fail(err)
+		}
+		if err := os.WriteFile(c.output, out, 0o644); err != nil {
+			fail(err)
+		}
+	}
+}
+
+// findMethod returns the declaration of the method name on *noise in f.
+func findMethod(f *ast.File, name string) *ast.FuncDecl {
+	for _, d := range f.Decls {
+		fn, ok := d.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != name || fn.Recv == nil || len(fn.Recv.List) != 1 {
+			continue
+		}
+		if star, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok {
+			if id, ok := star.X.(*ast.Ident); ok && id.Name == "noise" {
+				return fn
+			}
+		}
+	}
+	return nil
+}
+
+// rewrite renames decl, declared in a file with the given comments, and the
+// calls it makes to the other copied functions in place, and drops its lattice
+// check. If lattice is set, its gradIndex calls go through s.lattice.
+func rewrite(fset *token.FileSet, decl *ast.FuncDecl, comments []*ast.CommentGroup, renamed map[string]string, lattice bool) {
+	decl.Doc = nil
+	decl.Name.Name = renamed[decl.Name.Name]
+
+	if body := decl.Body.List; len(body) > 1 && isLatticeCheck(body[0]) {
+		// Move the brace to the end of the line before the next statement or
+		// its comment, so the printer drops the blank line after the check.
+		next := body[1].Pos()
+		for _, c := range comments {
+			if c.Pos() > body[0].End() && c.Pos() < next {
+				next = c.Pos()
+				break
+			}
+		}
+		f := fset.File(next)
+		decl.Body.Lbrace = f.LineStart(f.Line(next)) - 1
+		decl.Body.List = body[1:]
+	}
+
+	ast.Inspect(decl.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || !isIdent(sel.X, "s") {
+			return true
+		}
+
+		if name, ok := renamed[sel.Sel.Name]; ok {
+			sel.Sel.Name = name
+		} else if gradIndex[sel.Sel.Name] && lattice {
+			sel.X = &ast.SelectorExpr{X: sel.X, Sel: ast.NewIdent("lattice")}
+		}
+		return true
+	})
+}
+
+// rewriteGrid turns the renamed decl into its grid copy in place: the receiver
+// becomes g *gridCells, which provides the gradIndex methods, and the
+// derivative parameter d is dropped along with its uses.
+func rewriteGrid(decl *ast.FuncDecl, renamed map[string]string) {
+	decl.Recv.List[0].Type = &ast.StarExpr{X: ast.NewIdent("gridCells")}
+	dropDeriv(decl, renamed)
+
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Name == "s" {
+			id.Name = "g"
+		}
+		return true
+	})
+}
+
+// rewriteFloat32 turns decl into its float32 copy in place: the receiver
+// becomes s *noise32, the identifiers in float32Names are replaced and the
+// derivative parameter d is dropped along with its uses.
+func rewriteFloat32(decl *ast.FuncDecl, renamed map[string]string) {
+	decl.Recv.List[0].Type = &ast.StarExpr{X: ast.NewIdent("noise32")}
+	dropDeriv(decl, renamed)
+
+	ast.Inspect(decl, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok {
+			if name, ok := float32Names[id.Name]; ok {
+				id.Name = name
+			}
+		}
+		return true
+	})
+}
+
+// dropDeriv drops the derivative parameter d of decl, the "if d != nil"
+// blocks that update it, and the d or nil arguments passed for it to the
+// copied functions.
+func dropDeriv(decl *ast.FuncDecl, renamed map[string]string) {
+	var params []*ast.Field
+	for _, p := range decl.Type.Params.List {
+		if len(p.Names) != 1 || p.Names[0].Name != "d" {
+			params = append(params, p)
+		}
+	}
+	decl.Type.Params.List = params
+
+	ast.Inspect(decl, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			var list []ast.Stmt
+			for _, stmt := range n.List {
+				if !isDerivCheck(stmt) {
+					list = append(list, stmt)
+				}
+			}
+			n.List = list
+		case *ast.CallExpr:
+			if !isCopiedCall(n, renamed) {
+				return true
+			}
+			var args []ast.Expr
+			for _, arg := range n.Args {
+				if !isIdent(arg, "d") && !isIdent(arg, "nil") {
+					args = append(args, arg)
+				}
+			}
+			n.Args = args
+		}
+		return true
+	})
+}
+
+// isCopiedCall reports whether call is a method call to one of the copied
+// functions, after renaming.
+func isCopiedCall(call *ast.CallExpr, renamed map[string]string) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	for _, name := range renamed {
+		if sel.Sel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isLatticeCheck reports whether stmt is the dispatch "if s.lattice != nil".
+func isLatticeCheck(stmt ast.Stmt) bool {
+	return isNilCheck(stmt, func(e ast.Expr) bool {
+		sel, ok := e.(*ast.SelectorExpr)
+		return ok && isIdent(sel.X, "s") && sel.Sel.Name == "lattice"
+	})
+}
+
+// isDerivCheck reports whether stmt is the derivative update "if d != nil".
+func isDerivCheck(stmt ast.Stmt) bool {
+	return isNilCheck(stmt, func(e ast.Expr) bool { return isIdent(e, "d") })
+}
+
+// isNilCheck reports whether stmt is an if statement without an else whose
+// condition compares an expression accepted by x against nil.
+func isNilCheck(stmt ast.Stmt, x func(ast.Expr) bool) bool {
+	ifStmt, ok := stmt.(*ast.IfStmt)
+	if !ok || ifStmt.Init != nil || ifStmt.Else != nil {
+		return false
+	}
+	cond, ok := ifStmt.Cond.(*ast.BinaryExpr)
+	return ok && cond.Op == token.NEQ && isIdent(cond.Y, "nil") && x(cond.X)
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && id.Name == name
+}
+
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "genlattice: %v\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,189 @@
+// Command gensamples regenerates the golden sample files checked by
+// TestGoldenSamples. It is run by go generate from the opensimplex package
+// directory:
+//
+//	go generate go.sdls.io/opensimplex/pkg/opensimplex
+//
+// Every file holds one JSON array per line: the seed, the input coordinates and
+// the output value, so [seed, x, y, v] for Eval2, [seed, x, y, z, v] for Eval3
+// and [seed, x, y, z, w, v] for Eval4. Coordinates are rounded to float32, so
+// the same inputs can be fed to the 32-bit noise exactly. The deterministic
+// file additionally covers a few coordinates far outside the int32 lattice.
+//
+// The goldens record the current output of the package rather than that of
+// the Java reference. Only regenerate them when a change of output is
+// intended.
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.sdls.io/opensimplex/pkg/opensimplex"
+)
+
+// defaultSeeds covers small, negative and large seeds. They must be exactly
+// representable as float64, since they are stored in JSON numbers.
+const defaultSeeds = "0,1,-1,42,1337,24301,1099511627776,-4503599627370496"
+
+// maxSeed is the largest seed magnitude that survives the trip through float64.
+const maxSeed = 1 << 53
+
+// evaluator evaluates one output path of a noise instance in float64.
+type evaluator struct {
+	eval2 func(x, y float64) float64
+	eval3 func(x, y, z float64) float64
+	eval4 func(x, y, z, w float64) float64
+}
+
+func from64(n opensimplex.Noise) evaluator {
+	return evaluator{n.Eval2, n.Eval3, n.Eval4}
+}
+
+func from32(n opensimplex.Noise32) evaluator {
+	return evaluator{
+		eval2: func(x, y float64) float64 {
+			return float64(n.Eval2(float32(x), float32(y)))
+		},
+		eval3: func(x, y, z float64) float64 {
+			return float64(n.Eval3(float32(x), float32(y), float32(z)))
+		},
+		eval4: func(x, y, z, w float64) float64 {
+			return float64(n.Eval4(float32(x), float32(y), float32(z), float32(w)))
+		},
+	}
+}
+
+// paths maps each golden file to the constructor of the output path it covers.
+// Files with wide set also cover coordinates far outside the int32 lattice.
+var paths = []struct {
+	file    string
+	newEval func(seed int64) evaluator
+	wide    bool
+}{
+	{"opensimplex_golden_raw.json.gz", func(seed int64) evaluator { return from64(opensimplex.New(seed)) }, false},
+	{"opensimplex_golden_normalized.json.gz", func(seed int64) evaluator { return from64(opensimplex.NewNormalized(seed)) }, false},
+	{"opensimplex_golden_32.json.gz", func(seed int64) evaluator { return from32(opensimplex.New32(seed)) }, false},
+	{"opensimplex_golden_normalized32.json.gz", func(seed int64) evaluator { return from32(opensimplex.NewNormalized32(seed)) }, false},
+	{"opensimplex_golden_deterministic.json.gz", func(seed int64) evaluator { return from64(opensimplex.NewDeterministic(seed)) }, true},
+}
+
+func main() {
+	dir := flag.String("dir", ".", "directory to write the golden files to")
+	seedList := flag.String("seeds", defaultSeeds, "comma separated list of seeds")
+	count := flag.Int("n", 64, "number of samples per seed and dimension")
+	flag.Parse()
+
+	seeds, err := parseSeeds(*seedList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gensamples: %v\n", err)
+		os.Exit(2)
+	}
+
+	coords := coordinates(*count)
+	for _, p := range paths {
+		c := coords
+		if p.wide {
+			c = append(wideCoordinates(), coords...)
+		}
+		if err := write(filepath.Join(*dir, p.file), seeds, c, p.newEval); err != nil {
+			fmt.Fprintf(os.Stderr, "gensamples: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+func parseSeeds(list string) ([]int64, error) {
+	var seeds []int64
+	for _, f := range strings.Split(list, ",") {
+		seed, err := strconv.ParseInt(strings.TrimSpace(f), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		if seed > maxSeed || seed < -maxSeed {
+			return nil, fmt.Errorf("seed %d does not fit in a JSON number exactly", seed)
+		}
+		seeds = append(seeds, seed)
+	}
+	return seeds, nil
+}
+
+// coordinates returns count sample points per seed, with four float32
+// coordinates each. Half of them are near the origin and half spread over
+// [-1000, 1000), which exercises large lattice coordinates.
+func coordinates(count int) [][4]float64 {
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+
+	coords := make([][4]float64, count)
+	for i := range coords {
+		scale := 1000.0
+		if i%2 == 0 {
+			scale = 10
+		}
+		for j := range coords[i] {
+			coords[i][j] = float64(float32((r.Float64()*2 - 1) * scale))
+		}
+	}
+	return coords
+}
+
+// wideCoordinates returns sample points beyond the range of int32 lattice
+// coordinates, where only NewDeterministic promises a stable output.
+func wideCoordinates() [][4]float64 {
+	far := []float64{3e9, -3e9, 5e9 + 0.5, 1e15, -2.5e18, 1e19, -1e300}
+
+	coords := make([][4]float64, len(far))
+	for i := range coords {
+		for j := range coords[i] {
+			coords[i][j] = far[(i+j)%len(far)]
+		}
+	}
+	return coords
+}
+
+func write(name string, seeds []int64, coords [][4]float64, newEval func(int64) evaluator) (err error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	gz, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(gz)
+
+	for _, seed := range seeds {
+		n, s := newEval(seed), float64(seed)
+		for _, c := range coords {
+			if err := enc.Encode([]float64{s, c[0], c[1], n.eval2(c[0], c[1])}); err != nil {
+				return err
+			}
+		}
+		for _, c := range coords {
+			if err := enc.Encode([]float64{s, c[0], c[1], c[2], n.eval3(c[0], c[1], c[2])}); err != nil {
+				return err
+			}
+		}
+		for _, c := range coords {
+			if err := enc.Encode([]float64{s, c[0], c[1], c[2], c[3], n.eval4(c[0], c[1], c[2], c[3])}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return gz.Close()
+}
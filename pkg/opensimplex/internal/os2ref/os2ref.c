@@ -0,0 +1,945 @@
+/*
+ * os2ref renders reference samples for the OpenSimplex2 tests. It is a
+ * line-by-line C transcription of noise2, noise3_Fallback and noise4_Fallback
+ * from Kurt Spencer's (KdotJPG) Java reference, OpenSimplex2.java ("f") and
+ * OpenSimplex2S.java ("s"), written independently of the Go port in
+ * opensimplex2f.go and opensimplex2s.go.
+ *
+ * Java evaluates float and double expressions without fused multiply-adds and
+ * with wrapping long arithmetic. C matches it when compiled for SSE2 with
+ * contraction disabled and with the long arithmetic done in uint64_t:
+ *
+ *   cc -O2 -ffp-contract=off -o os2ref os2ref.c
+ *   zcat ../../opensimplex2f_test_samples.json.gz | ./os2ref f | gzip -9n > f.json.gz
+ *   zcat ../../opensimplex2s_test_samples.json.gz | ./os2ref s | gzip -9n > s.json.gz
+ *
+ * It reads sample lines [x, y, v], [x, y, z, v] or [x, y, z, w, v], keeps the
+ * coordinates as they are and replaces v with the value of the reference at
+ * seed 0.
+ *
+ * The Java OpenSimplex2S 4D noise walks a table listing, for each of the 256
+ * sub-cells of a skewed cell, the codes of the lattice vertices in range, in
+ * ascending order. Rather than copying that table, this walks every one of the
+ * 256 vertex codes in ascending order and lets the falloff test skip those out
+ * of range, which sums the same contributions in the same order.
+ */
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+#define PRIME_X ((int64_t)0x5205402B9270C86FLL)
+#define PRIME_Y ((int64_t)0x598CD327003817B5LL)
+#define PRIME_Z ((int64_t)0x5BCC226E9FA0BACBLL)
+#define PRIME_W ((int64_t)0x56CC5227E58F554BLL)
+#define HASH_MULTIPLIER ((int64_t)0x53A3F72DEEC546F5LL)
+#define SEED_FLIP_3D ((int64_t)-0x52D547B2E96ED629LL)
+#define SEED_OFFSET_4D ((int64_t)0xE83DC3E0DA7164DLL)
+
+/* Java long arithmetic wraps around. */
+#define ADD(a, b) ((int64_t)((uint64_t)(a) + (uint64_t)(b)))
+#define SUB(a, b) ((int64_t)((uint64_t)(a) - (uint64_t)(b)))
+#define MUL(a, b) ((int64_t)((uint64_t)(a) * (uint64_t)(b)))
+
+static const double SKEW_2D = 0.366025403784439;
+static const double UNSKEW_2D = -0.21132486540518713;
+static const double FALLBACK_ROTATE_3D = 2.0 / 3.0;
+
+#define N_GRADS_2D_EXPONENT 7
+#define N_GRADS_3D_EXPONENT 8
+#define N_GRADS_4D_EXPONENT 9
+#define N_GRADS_2D (1 << N_GRADS_2D_EXPONENT)
+#define N_GRADS_3D (1 << N_GRADS_3D_EXPONENT)
+#define N_GRADS_4D (1 << N_GRADS_4D_EXPONENT)
+
+/* OpenSimplex2 */
+static const float F_SKEW_4D = -0.138196601125011f;
+static const float F_UNSKEW_4D = 0.309016994374947f;
+static const float F_LATTICE_STEP_4D = 0.2f;
+static const double F_NORMALIZER_2D = 0.01001634121365712;
+static const double F_NORMALIZER_3D = 0.07969837668935331;
+static const double F_NORMALIZER_4D = 0.0220065933241897;
+static const float F_RSQUARED_2D = 0.5f;
+static const float F_RSQUARED_3D = 0.6f;
+static const float F_RSQUARED_4D = 0.6f;
+
+/* OpenSimplex2S */
+static const float S_SKEW_4D = 0.309016994374947f;
+static const float S_UNSKEW_4D = -0.138196601125011f;
+static const double S_NORMALIZER_2D = 0.05481866495625118;
+static const double S_NORMALIZER_3D = 0.2781926117527186;
+static const double S_NORMALIZER_4D = 0.11127401889945551;
+static const float S_RSQUARED_2D = 2.0f / 3.0f;
+static const float S_RSQUARED_3D = 3.0f / 4.0f;
+static const float S_RSQUARED_4D = 4.0f / 5.0f;
+
+static float GRADIENTS_2D[N_GRADS_2D * 2];
+static float GRADIENTS_3D[N_GRADS_3D * 4];
+static float GRADIENTS_4D[N_GRADS_4D * 4];
+
+static const float grad2[] = {
+	0.38268343236509f, 0.923879532511287f,
+	0.923879532511287f, 0.38268343236509f,
+	0.923879532511287f, -0.38268343236509f,
+	0.38268343236509f, -0.923879532511287f,
+	-0.38268343236509f, -0.923879532511287f,
+	-0.923879532511287f, -0.38268343236509f,
+	-0.923879532511287f, 0.38268343236509f,
+	-0.38268343236509f, 0.923879532511287f,
+	/*-------------------------------------*/
+	0.130526192220052f, 0.99144486137381f,
+	0.608761429008721f, 0.793353340291235f,
+	0.793353340291235f, 0.608761429008721f,
+	0.99144486137381f, 0.130526192220051f,
+	0.99144486137381f, -0.130526192220051f,
+	0.793353340291235f, -0.60876142900872f,
+	0.608761429008721f, -0.793353340291235f,
+	0.130526192220052f, -0.99144486137381f,
+	-0.130526192220052f, -0.99144486137381f,
+	-0.608761429008721f, -0.793353340291235f,
+	-0.793353340291235f, -0.608761429008721f,
+	-0.99144486137381f, -0.130526192220052f,
+	-0.99144486137381f, 0.130526192220051f,
+	-0.793353340291235f, 0.608761429008721f,
+	-0.608761429008721f, 0.793353340291235f,
+	-0.130526192220052f, 0.99144486137381f,
+};
+
+static const float grad3[] = {
+	2.22474487139f, 2.22474487139f, -1.0f, 0.0f,
+	2.22474487139f, 2.22474487139f, 1.0f, 0.0f,
+	3.0862664687972017f, 1.1721513422464978f, 0.0f, 0.0f,
+	1.1721513422464978f, 3.0862664687972017f, 0.0f, 0.0f,
+	-2.22474487139f, 2.22474487139f, -1.0f, 0.0f,
+	-2.22474487139f, 2.22474487139f, 1.0f, 0.0f,
+	-1.1721513422464978f, 3.0862664687972017f, 0.0f, 0.0f,
+	-3.0862664687972017f, 1.1721513422464978f, 0.0f, 0.0f,
+	-1.0f, -2.22474487139f, -2.22474487139f, 0.0f,
+	1.0f, -2.22474487139f, -2.22474487139f, 0.0f,
+	0.0f, -3.0862664687972017f, -1.1721513422464978f, 0.0f,
+	0.0f, -1.1721513422464978f, -3.0862664687972017f, 0.0f,
+	-1.0f, -2.22474487139f, 2.22474487139f, 0.0f,
+	1.0f, -2.22474487139f, 2.22474487139f, 0.0f,
+	0.0f, -1.1721513422464978f, 3.0862664687972017f, 0.0f,
+	0.0f, -3.0862664687972017f, 1.1721513422464978f, 0.0f,
+	/*--------------------------------------------------------------------*/
+	-2.22474487139f, -2.22474487139f, -1.0f, 0.0f,
+	-2.22474487139f, -2.22474487139f, 1.0f, 0.0f,
+	-3.0862664687972017f, -1.1721513422464978f, 0.0f, 0.0f,
+	-1.1721513422464978f, -3.0862664687972017f, 0.0f, 0.0f,
+	-2.22474487139f, -1.0f, -2.22474487139f, 0.0f,
+	-2.22474487139f, 1.0f, -2.22474487139f, 0.0f,
+	-1.1721513422464978f, 0.0f, -3.0862664687972017f, 0.0f,
+	-3.0862664687972017f, 0.0f, -1.1721513422464978f, 0.0f,
+	-2.22474487139f, -1.0f, 2.22474487139f, 0.0f,
+	-2.22474487139f, 1.0f, 2.22474487139f, 0.0f,
+	-3.0862664687972017f, 0.0f, 1.1721513422464978f, 0.0f,
+	-1.1721513422464978f, 0.0f, 3.0862664687972017f, 0.0f,
+	-1.0f, 2.22474487139f, -2.22474487139f, 0.0f,
+	1.0f, 2.22474487139f, -2.22474487139f, 0.0f,
+	0.0f, 1.1721513422464978f, -3.0862664687972017f, 0.0f,
+	0.0f, 3.0862664687972017f, -1.1721513422464978f, 0.0f,
+	-1.0f, 2.22474487139f, 2.22474487139f, 0.0f,
+	1.0f, 2.22474487139f, 2.22474487139f, 0.0f,
+	0.0f, 3.0862664687972017f, 1.1721513422464978f, 0.0f,
+	0.0f, 1.1721513422464978f, 3.0862664687972017f, 0.0f,
+	2.22474487139f, -2.22474487139f, -1.0f, 0.0f,
+	2.22474487139f, -2.22474487139f, 1.0f, 0.0f,
+	1.1721513422464978f, -3.0862664687972017f, 0.0f, 0.0f,
+	3.0862664687972017f, -1.1721513422464978f, 0.0f, 0.0f,
+	2.22474487139f, -1.0f, -2.22474487139f, 0.0f,
+	2.22474487139f, 1.0f, -2.22474487139f, 0.0f,
+	3.0862664687972017f, 0.0f, -1.1721513422464978f, 0.0f,
+	1.1721513422464978f, 0.0f, -3.0862664687972017f, 0.0f,
+	2.22474487139f, -1.0f, 2.22474487139f, 0.0f,
+	2.22474487139f, 1.0f, 2.22474487139f, 0.0f,
+	1.1721513422464978f, 0.0f, 3.0862664687972017f, 0.0f,
+	3.0862664687972017f, 0.0f, 1.1721513422464978f, 0.0f,
+};
+
+static const float grad4[] = {
+	-0.6740059517812944f, -0.3239847771997537f, -0.3239847771997537f, 0.5794684678643381f,
+	-0.7504883828755602f, -0.4004672082940195f, 0.15296486218853164f, 0.5029860367700724f,
+	-0.7504883828755602f, 0.15296486218853164f, -0.4004672082940195f, 0.5029860367700724f,
+	-0.8828161875373585f, 0.08164729285680945f, 0.08164729285680945f, 0.4553054119602712f,
+	-0.4553054119602712f, -0.08164729285680945f, -0.08164729285680945f, 0.8828161875373585f,
+	-0.5029860367700724f, -0.15296486218853164f, 0.4004672082940195f, 0.7504883828755602f,
+	-0.5029860367700724f, 0.4004672082940195f, -0.15296486218853164f, 0.7504883828755602f,
+	-0.5794684678643381f, 0.3239847771997537f, 0.3239847771997537f, 0.6740059517812944f,
+	-0.6740059517812944f, -0.3239847771997537f, 0.5794684678643381f, -0.3239847771997537f,
+	-0.7504883828755602f, -0.4004672082940195f, 0.5029860367700724f, 0.15296486218853164f,
+	-0.7504883828755602f, 0.15296486218853164f, 0.5029860367700724f, -0.4004672082940195f,
+	-0.8828161875373585f, 0.08164729285680945f, 0.4553054119602712f, 0.08164729285680945f,
+	-0.4553054119602712f, -0.08164729285680945f, 0.8828161875373585f, -0.08164729285680945f,
+	-0.5029860367700724f, -0.15296486218853164f, 0.7504883828755602f, 0.4004672082940195f,
+	-0.5029860367700724f, 0.4004672082940195f, 0.7504883828755602f, -0.15296486218853164f,
+	-0.5794684678643381f, 0.3239847771997537f, 0.6740059517812944f, 0.3239847771997537f,
+	-0.6740059517812944f, 0.5794684678643381f, -0.3239847771997537f, -0.3239847771997537f,
+	-0.7504883828755602f, 0.5029860367700724f, -0.4004672082940195f, 0.15296486218853164f,
+	-0.7504883828755602f, 0.5029860367700724f, 0.15296486218853164f, -0.4004672082940195f,
+	-0.8828161875373585f, 0.4553054119602712f, 0.08164729285680945f, 0.08164729285680945f,
+	-0.4553054119602712f, 0.8828161875373585f, -0.08164729285680945f, -0.08164729285680945f,
+	-0.5029860367700724f, 0.7504883828755602f, -0.15296486218853164f, 0.4004672082940195f,
+	-0.5029860367700724f, 0.7504883828755602f, 0.4004672082940195f, -0.15296486218853164f,
+	-0.5794684678643381f, 0.6740059517812944f, 0.3239847771997537f, 0.3239847771997537f,
+	-0.753341017856078f, -0.37968289875261624f, -0.37968289875261624f, -0.37968289875261624f,
+	-0.7821684431180708f, -0.4321472685365301f, -0.4321472685365301f, 0.12128480194602098f,
+	-0.7821684431180708f, -0.4321472685365301f, 0.12128480194602098f, -0.4321472685365301f,
+	-0.7821684431180708f, 0.12128480194602098f, -0.4321472685365301f, -0.4321472685365301f,
+	-0.8586508742123365f, -0.508629699630796f, 0.044802370851755174f, 0.044802370851755174f,
+	-0.8586508742123365f, 0.044802370851755174f, -0.508629699630796f, 0.044802370851755174f,
+	-0.8586508742123365f, 0.044802370851755174f, 0.044802370851755174f, -0.508629699630796f,
+	-0.9982828964265062f, -0.03381941603233842f, -0.03381941603233842f, -0.03381941603233842f,
+	-0.3239847771997537f, -0.6740059517812944f, -0.3239847771997537f, 0.5794684678643381f,
+	-0.4004672082940195f, -0.7504883828755602f, 0.15296486218853164f, 0.5029860367700724f,
+	0.15296486218853164f, -0.7504883828755602f, -0.4004672082940195f, 0.5029860367700724f,
+	0.08164729285680945f, -0.8828161875373585f, 0.08164729285680945f, 0.4553054119602712f,
+	-0.08164729285680945f, -0.4553054119602712f, -0.08164729285680945f, 0.8828161875373585f,
+	-0.15296486218853164f, -0.5029860367700724f, 0.4004672082940195f, 0.7504883828755602f,
+	0.4004672082940195f, -0.5029860367700724f, -0.15296486218853164f, 0.7504883828755602f,
+	0.3239847771997537f, -0.5794684678643381f, 0.3239847771997537f, 0.6740059517812944f,
+	-0.3239847771997537f, -0.6740059517812944f, 0.5794684678643381f, -0.3239847771997537f,
+	-0.4004672082940195f, -0.7504883828755602f, 0.5029860367700724f, 0.15296486218853164f,
+	0.15296486218853164f, -0.7504883828755602f, 0.5029860367700724f, -0.4004672082940195f,
+	0.08164729285680945f, -0.8828161875373585f, 0.4553054119602712f, 0.08164729285680945f,
+	-0.08164729285680945f, -0.4553054119602712f, 0.8828161875373585f, -0.08164729285680945f,
+	-0.15296486218853164f, -0.5029860367700724f, 0.7504883828755602f, 0.4004672082940195f,
+	0.4004672082940195f, -0.5029860367700724f, 0.7504883828755602f, -0.15296486218853164f,
+	0.3239847771997537f, -0.5794684678643381f, 0.6740059517812944f, 0.3239847771997537f,
+	0.5794684678643381f, -0.6740059517812944f, -0.3239847771997537f, -0.3239847771997537f,
+	0.5029860367700724f, -0.7504883828755602f, -0.4004672082940195f, 0.15296486218853164f,
+	0.5029860367700724f, -0.7504883828755602f, 0.15296486218853164f, -0.4004672082940195f,
+	0.4553054119602712f, -0.8828161875373585f, 0.08164729285680945f, 0.08164729285680945f,
+	0.8828161875373585f, -0.4553054119602712f, -0.08164729285680945f, -0.08164729285680945f,
+	0.7504883828755602f, -0.5029860367700724f, -0.15296486218853164f, 0.4004672082940195f,
+	0.7504883828755602f, -0.5029860367700724f, 0.4004672082940195f, -0.15296486218853164f,
+	0.6740059517812944f, -0.5794684678643381f, 0.3239847771997537f, 0.3239847771997537f,
+	-0.37968289875261624f, -0.753341017856078f, -0.37968289875261624f, -0.37968289875261624f,
+	-0.4321472685365301f, -0.7821684431180708f, -0.4321472685365301f, 0.12128480194602098f,
+	-0.4321472685365301f, -0.7821684431180708f, 0.12128480194602098f, -0.4321472685365301f,
+	0.12128480194602098f, -0.7821684431180708f, -0.4321472685365301f, -0.4321472685365301f,
+	-0.508629699630796f, -0.8586508742123365f, 0.044802370851755174f, 0.044802370851755174f,
+	0.044802370851755174f, -0.8586508742123365f, -0.508629699630796f, 0.044802370851755174f,
+	0.044802370851755174f, -0.8586508742123365f, 0.044802370851755174f, -0.508629699630796f,
+	-0.03381941603233842f, -0.9982828964265062f, -0.03381941603233842f, -0.03381941603233842f,
+	-0.3239847771997537f, -0.3239847771997537f, -0.6740059517812944f, 0.5794684678643381f,
+	-0.4004672082940195f, 0.15296486218853164f, -0.7504883828755602f, 0.5029860367700724f,
+	0.15296486218853164f, -0.4004672082940195f, -0.7504883828755602f, 0.5029860367700724f,
+	0.08164729285680945f, 0.08164729285680945f, -0.8828161875373585f, 0.4553054119602712f,
+	-0.08164729285680945f, -0.08164729285680945f, -0.4553054119602712f, 0.8828161875373585f,
+	-0.15296486218853164f, 0.4004672082940195f, -0.5029860367700724f, 0.7504883828755602f,
+	0.4004672082940195f, -0.15296486218853164f, -0.5029860367700724f, 0.7504883828755602f,
+	0.3239847771997537f, 0.3239847771997537f, -0.5794684678643381f, 0.6740059517812944f,
+	-0.3239847771997537f, 0.5794684678643381f, -0.6740059517812944f, -0.3239847771997537f,
+	-0.4004672082940195f, 0.5029860367700724f, -0.7504883828755602f, 0.15296486218853164f,
+	0.15296486218853164f, 0.5029860367700724f, -0.7504883828755602f, -0.4004672082940195f,
+	0.08164729285680945f, 0.4553054119602712f, -0.8828161875373585f, 0.08164729285680945f,
+	-0.08164729285680945f, 0.8828161875373585f, -0.4553054119602712f, -0.08164729285680945f,
+	-0.15296486218853164f, 0.7504883828755602f, -0.5029860367700724f, 0.4004672082940195f,
+	0.4004672082940195f, 0.7504883828755602f, -0.5029860367700724f, -0.15296486218853164f,
+	0.3239847771997537f, 0.6740059517812944f, -0.5794684678643381f, 0.3239847771997537f,
+	0.5794684678643381f, -0.3239847771997537f, -0.6740059517812944f, -0.3239847771997537f,
+	0.5029860367700724f, -0.4004672082940195f, -0.7504883828755602f, 0.15296486218853164f,
+	0.5029860367700724f, 0.15296486218853164f, -0.7504883828755602f, -0.4004672082940195f,
+	0.4553054119602712f, 0.08164729285680945f, -0.8828161875373585f, 0.08164729285680945f,
+	0.8828161875373585f, -0.08164729285680945f, -0.4553054119602712f, -0.08164729285680945f,
+	0.7504883828755602f, -0.15296486218853164f, -0.5029860367700724f, 0.4004672082940195f,
+	0.7504883828755602f, 0.4004672082940195f, -0.5029860367700724f, -0.15296486218853164f,
+	0.6740059517812944f, 0.3239847771997537f, -0.5794684678643381f, 0.3239847771997537f,
+	-0.37968289875261624f, -0.37968289875261624f, -0.753341017856078f, -0.37968289875261624f,
+	-0.4321472685365301f, -0.4321472685365301f, -0.7821684431180708f, 0.12128480194602098f,
+	-0.4321472685365301f, 0.12128480194602098f, -0.7821684431180708f, -0.4321472685365301f,
+	0.12128480194602098f, -0.4321472685365301f, -0.7821684431180708f, -0.4321472685365301f,
+	-0.508629699630796f, 0.044802370851755174f, -0.8586508742123365f, 0.044802370851755174f,
+	0.044802370851755174f, -0.508629699630796f, -0.8586508742123365f, 0.044802370851755174f,
+	0.044802370851755174f, 0.044802370851755174f, -0.8586508742123365f, -0.508629699630796f,
+	-0.03381941603233842f, -0.03381941603233842f, -0.9982828964265062f, -0.03381941603233842f,
+	-0.3239847771997537f, -0.3239847771997537f, 0.5794684678643381f, -0.6740059517812944f,
+	-0.4004672082940195f, 0.15296486218853164f, 0.5029860367700724f, -0.7504883828755602f,
+	0.15296486218853164f, -0.4004672082940195f, 0.5029860367700724f, -0.7504883828755602f,
+	0.08164729285680945f, 0.08164729285680945f, 0.4553054119602712f, -0.8828161875373585f,
+	-0.08164729285680945f, -0.08164729285680945f, 0.8828161875373585f, -0.4553054119602712f,
+	-0.15296486218853164f, 0.4004672082940195f, 0.7504883828755602f, -0.5029860367700724f,
+	0.4004672082940195f, -0.15296486218853164f, 0.7504883828755602f, -0.5029860367700724f,
+	0.3239847771997537f, 0.3239847771997537f, 0.6740059517812944f, -0.5794684678643381f,
+	-0.3239847771997537f, 0.5794684678643381f, -0.3239847771997537f, -0.6740059517812944f,
+	-0.4004672082940195f, 0.5029860367700724f, 0.15296486218853164f, -0.7504883828755602f,
+	0.15296486218853164f, 0.5029860367700724f, -0.4004672082940195f, -0.7504883828755602f,
+	0.08164729285680945f, 0.4553054119602712f, 0.08164729285680945f, -0.8828161875373585f,
+	-0.08164729285680945f, 0.8828161875373585f, -0.08164729285680945f, -0.4553054119602712f,
+	-0.15296486218853164f, 0.7504883828755602f, 0.4004672082940195f, -0.5029860367700724f,
+	0.4004672082940195f, 0.7504883828755602f, -0.15296486218853164f, -0.5029860367700724f,
+	0.3239847771997537f, 0.6740059517812944f, 0.3239847771997537f, -0.5794684678643381f,
+	0.5794684678643381f, -0.3239847771997537f, -0.3239847771997537f, -0.6740059517812944f,
+	0.5029860367700724f, -0.4004672082940195f, 0.15296486218853164f, -0.7504883828755602f,
+	0.5029860367700724f, 0.15296486218853164f, -0.4004672082940195f, -0.7504883828755602f,
+	0.4553054119602712f, 0.08164729285680945f, 0.08164729285680945f, -0.8828161875373585f,
+	0.8828161875373585f, -0.08164729285680945f, -0.08164729285680945f, -0.4553054119602712f,
+	0.7504883828755602f, -0.15296486218853164f, 0.4004672082940195f, -0.5029860367700724f,
+	0.7504883828755602f, 0.4004672082940195f, -0.15296486218853164f, -0.5029860367700724f,
+	0.6740059517812944f, 0.3239847771997537f, 0.3239847771997537f, -0.5794684678643381f,
+	-0.37968289875261624f, -0.37968289875261624f, -0.37968289875261624f, -0.753341017856078f,
+	-0.4321472685365301f, -0.4321472685365301f, 0.12128480194602098f, -0.7821684431180708f,
+	-0.4321472685365301f, 0.12128480194602098f, -0.4321472685365301f, -0.7821684431180708f,
+	0.12128480194602098f, -0.4321472685365301f, -0.4321472685365301f, -0.7821684431180708f,
+	-0.508629699630796f, 0.044802370851755174f, 0.044802370851755174f, -0.8586508742123365f,
+	0.044802370851755174f, -0.508629699630796f, 0.044802370851755174f, -0.8586508742123365f,
+	0.044802370851755174f, 0.044802370851755174f, -0.508629699630796f, -0.8586508742123365f,
+	-0.03381941603233842f, -0.03381941603233842f, -0.03381941603233842f, -0.9982828964265062f,
+	0.753341017856078f, 0.37968289875261624f, 0.37968289875261624f, 0.37968289875261624f,
+	0.7821684431180708f, 0.4321472685365301f, 0.4321472685365301f, -0.12128480194602098f,
+	0.7821684431180708f, 0.4321472685365301f, -0.12128480194602098f, 0.4321472685365301f,
+	0.7821684431180708f, -0.12128480194602098f, 0.4321472685365301f, 0.4321472685365301f,
+	0.8586508742123365f, 0.508629699630796f, -0.044802370851755174f, -0.044802370851755174f,
+	0.8586508742123365f, -0.044802370851755174f, 0.508629699630796f, -0.044802370851755174f,
+	0.8586508742123365f, -0.044802370851755174f, -0.044802370851755174f, 0.508629699630796f,
+	0.9982828964265062f, 0.03381941603233842f, 0.03381941603233842f, 0.03381941603233842f,
+	0.37968289875261624f, 0.753341017856078f, 0.37968289875261624f, 0.37968289875261624f,
+	0.4321472685365301f, 0.7821684431180708f, 0.4321472685365301f, -0.12128480194602098f,
+	0.4321472685365301f, 0.7821684431180708f, -0.12128480194602098f, 0.4321472685365301f,
+	-0.12128480194602098f, 0.7821684431180708f, 0.4321472685365301f, 0.4321472685365301f,
+	0.508629699630796f, 0.8586508742123365f, -0.044802370851755174f, -0.044802370851755174f,
+	-0.044802370851755174f, 0.8586508742123365f, 0.508629699630796f, -0.044802370851755174f,
+	-0.044802370851755174f, 0.8586508742123365f, -0.044802370851755174f, 0.508629699630796f,
+	0.03381941603233842f, 0.9982828964265062f, 0.03381941603233842f, 0.03381941603233842f,
+	0.37968289875261624f, 0.37968289875261624f, 0.753341017856078f, 0.37968289875261624f,
+	0.4321472685365301f, 0.4321472685365301f, 0.7821684431180708f, -0.12128480194602098f,
+	0.4321472685365301f, -0.12128480194602098f, 0.7821684431180708f, 0.4321472685365301f,
+	-0.12128480194602098f, 0.4321472685365301f, 0.7821684431180708f, 0.4321472685365301f,
+	0.508629699630796f, -0.044802370851755174f, 0.8586508742123365f, -0.044802370851755174f,
+	-0.044802370851755174f, 0.508629699630796f, 0.8586508742123365f, -0.044802370851755174f,
+	-0.044802370851755174f, -0.044802370851755174f, 0.8586508742123365f, 0.508629699630796f,
+	0.03381941603233842f, 0.03381941603233842f, 0.9982828964265062f, 0.03381941603233842f,
+	0.37968289875261624f, 0.37968289875261624f, 0.37968289875261624f, 0.753341017856078f,
+	0.4321472685365301f, 0.4321472685365301f, -0.12128480194602098f, 0.7821684431180708f,
+	0.4321472685365301f, -0.12128480194602098f, 0.4321472685365301f, 0.7821684431180708f,
+	-0.12128480194602098f, 0.4321472685365301f, 0.4321472685365301f, 0.7821684431180708f,
+	0.508629699630796f, -0.044802370851755174f, -0.044802370851755174f, 0.8586508742123365f,
+	-0.044802370851755174f, 0.508629699630796f, -0.044802370851755174f, 0.8586508742123365f,
+	-0.044802370851755174f, -0.044802370851755174f, 0.508629699630796f, 0.8586508742123365f,
+	0.03381941603233842f, 0.03381941603233842f, 0.03381941603233842f, 0.9982828964265062f,
+};
+
+/* The static initializer of the Java classes: normalize, then tile. */
+static void init_gradients(double n2, double n3, double n4)
+{
+	size_t i, j;
+	float g2[sizeof grad2 / sizeof grad2[0]];
+	float g3[sizeof grad3 / sizeof grad3[0]];
+	float g4[sizeof grad4 / sizeof grad4[0]];
+
+	for (i = 0; i < sizeof g2 / sizeof g2[0]; i++)
+		g2[i] = (float)(grad2[i] / n2);
+	for (i = 0, j = 0; i < N_GRADS_2D * 2; i++, j++) {
+		if (j == sizeof g2 / sizeof g2[0])
+			j = 0;
+		GRADIENTS_2D[i] = g2[j];
+	}
+
+	for (i = 0; i < sizeof g3 / sizeof g3[0]; i++)
+		g3[i] = (float)(grad3[i] / n3);
+	for (i = 0, j = 0; i < N_GRADS_3D * 4; i++, j++) {
+		if (j == sizeof g3 / sizeof g3[0])
+			j = 0;
+		GRADIENTS_3D[i] = g3[j];
+	}
+
+	for (i = 0; i < sizeof g4 / sizeof g4[0]; i++)
+		g4[i] = (float)(grad4[i] / n4);
+	for (i = 0, j = 0; i < N_GRADS_4D * 4; i++, j++) {
+		if (j == sizeof g4 / sizeof g4[0])
+			j = 0;
+		GRADIENTS_4D[i] = g4[j];
+	}
+}
+
+static float grad2d(int64_t seed, int64_t xsvp, int64_t ysvp, float dx, float dy)
+{
+	int64_t hash = seed ^ xsvp ^ ysvp;
+	hash = MUL(hash, HASH_MULTIPLIER);
+	hash ^= hash >> (64 - N_GRADS_2D_EXPONENT + 1);
+	int gi = (int32_t)(uint32_t)hash & ((N_GRADS_2D - 1) << 1);
+	return GRADIENTS_2D[gi | 0] * dx + GRADIENTS_2D[gi | 1] * dy;
+}
+
+static float grad3d(int64_t seed, int64_t xrvp, int64_t yrvp, int64_t zrvp, float dx, float dy, float dz)
+{
+	int64_t hash = (seed ^ xrvp) ^ (yrvp ^ zrvp);
+	hash = MUL(hash, HASH_MULTIPLIER);
+	hash ^= hash >> (64 - N_GRADS_3D_EXPONENT + 2);
+	int gi = (int32_t)(uint32_t)hash & ((N_GRADS_3D - 1) << 2);
+	return GRADIENTS_3D[gi | 0] * dx + GRADIENTS_3D[gi | 1] * dy + GRADIENTS_3D[gi | 2] * dz;
+}
+
+static float grad4d(int64_t seed, int64_t xsvp, int64_t ysvp, int64_t zsvp, int64_t wsvp,
+	float dx, float dy, float dz, float dw)
+{
+	int64_t hash = seed ^ (xsvp ^ ysvp) ^ (zsvp ^ wsvp);
+	hash = MUL(hash, HASH_MULTIPLIER);
+	hash ^= hash >> (64 - N_GRADS_4D_EXPONENT + 2);
+	int gi = (int32_t)(uint32_t)hash & ((N_GRADS_4D - 1) << 2);
+	return (GRADIENTS_4D[gi | 0] * dx + GRADIENTS_4D[gi | 1] * dy) + (GRADIENTS_4D[gi | 2] * dz + GRADIENTS_4D[gi | 3] * dw);
+}
+
+static int fastFloor(double x)
+{
+	int xi = (int)x;
+	return x < xi ? xi - 1 : xi;
+}
+
+static int fastRound(double x)
+{
+	return x < 0 ? (int)(x - 0.5) : (int)(x + 0.5);
+}
+
+/* OpenSimplex2.java */
+
+static float f_noise2(int64_t seed, double x, double y)
+{
+	double s = SKEW_2D * (x + y);
+	double xs = x + s, ys = y + s;
+
+	int xsb = fastFloor(xs), ysb = fastFloor(ys);
+	float xi = (float)(xs - xsb), yi = (float)(ys - ysb);
+
+	int64_t xsbp = MUL(xsb, PRIME_X), ysbp = MUL(ysb, PRIME_Y);
+
+	float t = (xi + yi) * (float)UNSKEW_2D;
+	float dx0 = xi + t, dy0 = yi + t;
+
+	float value = 0;
+	float a0 = F_RSQUARED_2D - dx0 * dx0 - dy0 * dy0;
+	if (a0 > 0) {
+		value = (a0 * a0) * (a0 * a0) * grad2d(seed, xsbp, ysbp, dx0, dy0);
+	}
+
+	float a1 = (float)(2 * (1 + 2 * UNSKEW_2D) * (1 / UNSKEW_2D + 2)) * t + ((float)(-2 * (1 + 2 * UNSKEW_2D) * (1 + 2 * UNSKEW_2D)) + a0);
+	if (a1 > 0) {
+		float dx1 = dx0 - (float)(1 + 2 * UNSKEW_2D);
+		float dy1 = dy0 - (float)(1 + 2 * UNSKEW_2D);
+		value += (a1 * a1) * (a1 * a1) * grad2d(seed, ADD(xsbp, PRIME_X), ADD(ysbp, PRIME_Y), dx1, dy1);
+	}
+
+	if (dy0 > dx0) {
+		float dx2 = dx0 - (float)UNSKEW_2D;
+		float dy2 = dy0 - (float)(UNSKEW_2D + 1);
+		float a2 = F_RSQUARED_2D - dx2 * dx2 - dy2 * dy2;
+		if (a2 > 0) {
+			value += (a2 * a2) * (a2 * a2) * grad2d(seed, xsbp, ADD(ysbp, PRIME_Y), dx2, dy2);
+		}
+	} else {
+		float dx2 = dx0 - (float)(UNSKEW_2D + 1);
+		float dy2 = dy0 - (float)UNSKEW_2D;
+		float a2 = F_RSQUARED_2D - dx2 * dx2 - dy2 * dy2;
+		if (a2 > 0) {
+			value += (a2 * a2) * (a2 * a2) * grad2d(seed, ADD(xsbp, PRIME_X), ysbp, dx2, dy2);
+		}
+	}
+
+	return value;
+}
+
+static float f_noise3(int64_t seed, double x, double y, double z)
+{
+	double r = FALLBACK_ROTATE_3D * (x + y + z);
+	double xr = r - x, yr = r - y, zr = r - z;
+
+	int xrb = fastRound(xr), yrb = fastRound(yr), zrb = fastRound(zr);
+	float xri = (float)(xr - xrb), yri = (float)(yr - yrb), zri = (float)(zr - zrb);
+
+	int xNSign = (int)(-1.0f - xri) | 1, yNSign = (int)(-1.0f - yri) | 1, zNSign = (int)(-1.0f - zri) | 1;
+
+	float ax0 = xNSign * -xri, ay0 = yNSign * -yri, az0 = zNSign * -zri;
+
+	int64_t xrbp = MUL(xrb, PRIME_X), yrbp = MUL(yrb, PRIME_Y), zrbp = MUL(zrb, PRIME_Z);
+
+	float value = 0;
+	float a = (F_RSQUARED_3D - xri * xri) - (yri * yri + zri * zri);
+	for (int l = 0;; l++) {
+		if (a > 0) {
+			value += (a * a) * (a * a) * grad3d(seed, xrbp, yrbp, zrbp, xri, yri, zri);
+		}
+
+		if (ax0 >= ay0 && ax0 >= az0) {
+			float b = a + ax0 + ax0;
+			if (b > 1) {
+				b -= 1;
+				value += (b * b) * (b * b) * grad3d(seed, SUB(xrbp, MUL(xNSign, PRIME_X)), yrbp, zrbp, xri + xNSign, yri, zri);
+			}
+		} else if (ay0 > ax0 && ay0 >= az0) {
+			float b = a + ay0 + ay0;
+			if (b > 1) {
+				b -= 1;
+				value += (b * b) * (b * b) * grad3d(seed, xrbp, SUB(yrbp, MUL(yNSign, PRIME_Y)), zrbp, xri, yri + yNSign, zri);
+			}
+		} else {
+			float b = a + az0 + az0;
+			if (b > 1) {
+				b -= 1;
+				value += (b * b) * (b * b) * grad3d(seed, xrbp, yrbp, SUB(zrbp, MUL(zNSign, PRIME_Z)), xri, yri, zri + zNSign);
+			}
+		}
+
+		if (l == 1)
+			break;
+
+		ax0 = 0.5f - ax0;
+		ay0 = 0.5f - ay0;
+		az0 = 0.5f - az0;
+
+		xri = xNSign * ax0;
+		yri = yNSign * ay0;
+		zri = zNSign * az0;
+
+		a += (0.75f - ax0) - (ay0 + az0);
+
+		xrbp = ADD(xrbp, (int64_t)(xNSign >> 1) & PRIME_X);
+		yrbp = ADD(yrbp, (int64_t)(yNSign >> 1) & PRIME_Y);
+		zrbp = ADD(zrbp, (int64_t)(zNSign >> 1) & PRIME_Z);
+
+		xNSign = -xNSign;
+		yNSign = -yNSign;
+		zNSign = -zNSign;
+
+		seed ^= SEED_FLIP_3D;
+	}
+
+	return value;
+}
+
+static float f_noise4(int64_t seed, double x, double y, double z, double w)
+{
+	double s = F_SKEW_4D * (x + y + z + w);
+	double xs = x + s, ys = y + s, zs = z + s, ws = w + s;
+
+	int xsb = fastFloor(xs), ysb = fastFloor(ys), zsb = fastFloor(zs), wsb = fastFloor(ws);
+	float xsi = (float)(xs - xsb), ysi = (float)(ys - ysb), zsi = (float)(zs - zsb), wsi = (float)(ws - wsb);
+
+	float siSum = (xsi + ysi) + (zsi + wsi);
+	int startingLattice = (int)(siSum * 1.25);
+
+	seed = ADD(seed, MUL(startingLattice, SEED_OFFSET_4D));
+
+	float startingLatticeOffset = startingLattice * -F_LATTICE_STEP_4D;
+	xsi += startingLatticeOffset;
+	ysi += startingLatticeOffset;
+	zsi += startingLatticeOffset;
+	wsi += startingLatticeOffset;
+
+	float ssi = (siSum + startingLatticeOffset * 4) * F_UNSKEW_4D;
+
+	int64_t xsvp = MUL(xsb, PRIME_X), ysvp = MUL(ysb, PRIME_Y), zsvp = MUL(zsb, PRIME_Z), wsvp = MUL(wsb, PRIME_W);
+
+	float value = 0;
+	for (int i = 0;; i++) {
+		double score0 = 1.0 + ssi * (-1.0 / F_UNSKEW_4D);
+		if (xsi >= ysi && xsi >= zsi && xsi >= wsi && xsi >= score0) {
+			xsvp = ADD(xsvp, PRIME_X);
+			xsi -= 1;
+			ssi -= F_UNSKEW_4D;
+		} else if (ysi > xsi && ysi >= zsi && ysi >= wsi && ysi >= score0) {
+			ysvp = ADD(ysvp, PRIME_Y);
+			ysi -= 1;
+			ssi -= F_UNSKEW_4D;
+		} else if (zsi > xsi && zsi > ysi && zsi >= wsi && zsi >= score0) {
+			zsvp = ADD(zsvp, PRIME_Z);
+			zsi -= 1;
+			ssi -= F_UNSKEW_4D;
+		} else if (wsi > xsi && wsi > ysi && wsi > zsi && wsi >= score0) {
+			wsvp = ADD(wsvp, PRIME_W);
+			wsi -= 1;
+			ssi -= F_UNSKEW_4D;
+		}
+
+		float dx = xsi + ssi, dy = ysi + ssi, dz = zsi + ssi, dw = wsi + ssi;
+		float a = (dx * dx + dy * dy) + (dz * dz + dw * dw);
+		if (a < F_RSQUARED_4D) {
+			a -= F_RSQUARED_4D;
+			a *= a;
+			value += a * a * grad4d(seed, xsvp, ysvp, zsvp, wsvp, dx, dy, dz, dw);
+		}
+
+		if (i == 4)
+			break;
+
+		xsi += F_LATTICE_STEP_4D;
+		ysi += F_LATTICE_STEP_4D;
+		zsi += F_LATTICE_STEP_4D;
+		wsi += F_LATTICE_STEP_4D;
+		ssi += F_LATTICE_STEP_4D * 4 * F_UNSKEW_4D;
+		seed = SUB(seed, SEED_OFFSET_4D);
+
+		if (i == startingLattice) {
+			xsvp = SUB(xsvp, PRIME_X);
+			ysvp = SUB(ysvp, PRIME_Y);
+			zsvp = SUB(zsvp, PRIME_Z);
+			wsvp = SUB(wsvp, PRIME_W);
+			seed = ADD(seed, MUL(SEED_OFFSET_4D, 5));
+		}
+	}
+
+	return value;
+}
+
+/* OpenSimplex2S.java */
+
+static float s_noise2(int64_t seed, double x, double y)
+{
+	double s = SKEW_2D * (x + y);
+	double xs = x + s, ys = y + s;
+
+	int xsb = fastFloor(xs), ysb = fastFloor(ys);
+	float xi = (float)(xs - xsb), yi = (float)(ys - ysb);
+
+	int64_t xsbp = MUL(xsb, PRIME_X), ysbp = MUL(ysb, PRIME_Y);
+
+	float t = (xi + yi) * (float)UNSKEW_2D;
+	float dx0 = xi + t, dy0 = yi + t;
+
+	float a0 = S_RSQUARED_2D - dx0 * dx0 - dy0 * dy0;
+	float value = (a0 * a0) * (a0 * a0) * grad2d(seed, xsbp, ysbp, dx0, dy0);
+
+	float a1 = (float)(2 * (1 + 2 * UNSKEW_2D) * (1 / UNSKEW_2D + 2)) * t + ((float)(-2 * (1 + 2 * UNSKEW_2D) * (1 + 2 * UNSKEW_2D)) + a0);
+	float dx1 = dx0 - (float)(1 + 2 * UNSKEW_2D);
+	float dy1 = dy0 - (float)(1 + 2 * UNSKEW_2D);
+	value += (a1 * a1) * (a1 * a1) * grad2d(seed, ADD(xsbp, PRIME_X), ADD(ysbp, PRIME_Y), dx1, dy1);
+
+	float xmyi = xi - yi;
+	if (t < UNSKEW_2D) {
+		if (xi + xmyi > 1) {
+			float dx2 = dx0 - (float)(3 * UNSKEW_2D + 2);
+			float dy2 = dy0 - (float)(3 * UNSKEW_2D + 1);
+			float a2 = S_RSQUARED_2D - dx2 * dx2 - dy2 * dy2;
+			if (a2 > 0) {
+				value += (a2 * a2) * (a2 * a2) * grad2d(seed, ADD(xsbp, MUL(PRIME_X, 2)), ADD(ysbp, PRIME_Y), dx2, dy2);
+			}
+		} else {
+			float dx2 = dx0 - (float)UNSKEW_2D;
+			float dy2 = dy0 - (float)(UNSKEW_2D + 1);
+			float a2 = S_RSQUARED_2D - dx2 * dx2 - dy2 * dy2;
+			if (a2 > 0) {
+				value += (a2 * a2) * (a2 * a2) * grad2d(seed, xsbp, ADD(ysbp, PRIME_Y), dx2, dy2);
+			}
+		}
+
+		if (yi - xmyi > 1) {
+			float dx3 = dx0 - (float)(3 * UNSKEW_2D + 1);
+			float dy3 = dy0 - (float)(3 * UNSKEW_2D + 2);
+			float a3 = S_RSQUARED_2D - dx3 * dx3 - dy3 * dy3;
+			if (a3 > 0) {
+				value += (a3 * a3) * (a3 * a3) * grad2d(seed, ADD(xsbp, PRIME_X), ADD(ysbp, MUL(PRIME_Y, 2)), dx3, dy3);
+			}
+		} else {
+			float dx3 = dx0 - (float)(UNSKEW_2D + 1);
+			float dy3 = dy0 - (float)UNSKEW_2D;
+			float a3 = S_RSQUARED_2D - dx3 * dx3 - dy3 * dy3;
+			if (a3 > 0) {
+				value += (a3 * a3) * (a3 * a3) * grad2d(seed, ADD(xsbp, PRIME_X), ysbp, dx3, dy3);
+			}
+		}
+	} else {
+		if (xi + xmyi < 0) {
+			float dx2 = dx0 + (float)(1 + UNSKEW_2D);
+			float dy2 = dy0 + (float)UNSKEW_2D;
+			float a2 = S_RSQUARED_2D - dx2 * dx2 - dy2 * dy2;
+			if (a2 > 0) {
+				value += (a2 * a2) * (a2 * a2) * grad2d(seed, SUB(xsbp, PRIME_X), ysbp, dx2, dy2);
+			}
+		} else {
+			float dx2 = dx0 - (float)(UNSKEW_2D + 1);
+			float dy2 = dy0 - (float)UNSKEW_2D;
+			float a2 = S_RSQUARED_2D - dx2 * dx2 - dy2 * dy2;
+			if (a2 > 0) {
+				value += (a2 * a2) * (a2 * a2) * grad2d(seed, ADD(xsbp, PRIME_X), ysbp, dx2, dy2);
+			}
+		}
+
+		if (yi < xmyi) {
+			float dx2 = dx0 + (float)UNSKEW_2D;
+			float dy2 = dy0 + (float)(UNSKEW_2D + 1);
+			float a2 = S_RSQUARED_2D - dx2 * dx2 - dy2 * dy2;
+			if (a2 > 0) {
+				value += (a2 * a2) * (a2 * a2) * grad2d(seed, xsbp, SUB(ysbp, PRIME_Y), dx2, dy2);
+			}
+		} else {
+			float dx2 = dx0 - (float)UNSKEW_2D;
+			float dy2 = dy0 - (float)(UNSKEW_2D + 1);
+			float a2 = S_RSQUARED_2D - dx2 * dx2 - dy2 * dy2;
+			if (a2 > 0) {
+				value += (a2 * a2) * (a2 * a2) * grad2d(seed, xsbp, ADD(ysbp, PRIME_Y), dx2, dy2);
+			}
+		}
+	}
+
+	return value;
+}
+
+static float s_noise3(int64_t seed, double x, double y, double z)
+{
+	double r = FALLBACK_ROTATE_3D * (x + y + z);
+	double xr = r - x, yr = r - y, zr = r - z;
+
+	int xrb = fastFloor(xr), yrb = fastFloor(yr), zrb = fastFloor(zr);
+	float xi = (float)(xr - xrb), yi = (float)(yr - yrb), zi = (float)(zr - zrb);
+
+	int64_t xrbp = MUL(xrb, PRIME_X), yrbp = MUL(yrb, PRIME_Y), zrbp = MUL(zrb, PRIME_Z);
+	int64_t seed2 = seed ^ SEED_FLIP_3D;
+
+	/* Java widens the int masks to long before and-ing them with the primes. */
+	int xNMask = (int)(-0.5f - xi), yNMask = (int)(-0.5f - yi), zNMask = (int)(-0.5f - zi);
+	int64_t xm = xNMask, ym = yNMask, zm = zNMask;
+
+	float x0 = xi + xNMask;
+	float y0 = yi + yNMask;
+	float z0 = zi + zNMask;
+	float a0 = S_RSQUARED_3D - x0 * x0 - y0 * y0 - z0 * z0;
+	float value = (a0 * a0) * (a0 * a0) * grad3d(seed,
+		ADD(xrbp, xm & PRIME_X), ADD(yrbp, ym & PRIME_Y), ADD(zrbp, zm & PRIME_Z), x0, y0, z0);
+
+	float x1 = xi - 0.5f;
+	float y1 = yi - 0.5f;
+	float z1 = zi - 0.5f;
+	float a1 = S_RSQUARED_3D - x1 * x1 - y1 * y1 - z1 * z1;
+	value += (a1 * a1) * (a1 * a1) * grad3d(seed2,
+		ADD(xrbp, PRIME_X), ADD(yrbp, PRIME_Y), ADD(zrbp, PRIME_Z), x1, y1, z1);
+
+	float xAFlipMask0 = ((xNMask | 1) << 1) * x1;
+	float yAFlipMask0 = ((yNMask | 1) << 1) * y1;
+	float zAFlipMask0 = ((zNMask | 1) << 1) * z1;
+	float xAFlipMask1 = (-2 - (xNMask * 4)) * x1 - 1.0f;
+	float yAFlipMask1 = (-2 - (yNMask * 4)) * y1 - 1.0f;
+	float zAFlipMask1 = (-2 - (zNMask * 4)) * z1 - 1.0f;
+
+	int skip5 = 0;
+	float a2 = xAFlipMask0 + a0;
+	if (a2 > 0) {
+		float x2 = x0 - (xNMask | 1);
+		float y2 = y0;
+		float z2 = z0;
+		value += (a2 * a2) * (a2 * a2) * grad3d(seed,
+			ADD(xrbp, ~xm & PRIME_X), ADD(yrbp, ym & PRIME_Y), ADD(zrbp, zm & PRIME_Z), x2, y2, z2);
+	} else {
+		float a3 = yAFlipMask0 + zAFlipMask0 + a0;
+		if (a3 > 0) {
+			float x3 = x0;
+			float y3 = y0 - (yNMask | 1);
+			float z3 = z0 - (zNMask | 1);
+			value += (a3 * a3) * (a3 * a3) * grad3d(seed,
+				ADD(xrbp, xm & PRIME_X), ADD(yrbp, ~ym & PRIME_Y), ADD(zrbp, ~zm & PRIME_Z), x3, y3, z3);
+		}
+
+		float a4 = xAFlipMask1 + a1;
+		if (a4 > 0) {
+			float x4 = (xNMask | 1) + x1;
+			float y4 = y1;
+			float z4 = z1;
+			value += (a4 * a4) * (a4 * a4) * grad3d(seed2,
+				ADD(xrbp, xm & MUL(PRIME_X, 2)), ADD(yrbp, PRIME_Y), ADD(zrbp, PRIME_Z), x4, y4, z4);
+			skip5 = 1;
+		}
+	}
+
+	int skip9 = 0;
+	float a6 = yAFlipMask0 + a0;
+	if (a6 > 0) {
+		float x6 = x0;
+		float y6 = y0 - (yNMask | 1);
+		float z6 = z0;
+		value += (a6 * a6) * (a6 * a6) * grad3d(seed,
+			ADD(xrbp, xm & PRIME_X), ADD(yrbp, ~ym & PRIME_Y), ADD(zrbp, zm & PRIME_Z), x6, y6, z6);
+	} else {
+		float a7 = xAFlipMask0 + zAFlipMask0 + a0;
+		if (a7 > 0) {
+			float x7 = x0 - (xNMask | 1);
+			float y7 = y0;
+			float z7 = z0 - (zNMask | 1);
+			value += (a7 * a7) * (a7 * a7) * grad3d(seed,
+				ADD(xrbp, ~xm & PRIME_X), ADD(yrbp, ym & PRIME_Y), ADD(zrbp, ~zm & PRIME_Z), x7, y7, z7);
+		}
+
+		float a8 = yAFlipMask1 + a1;
+		if (a8 > 0) {
+			float x8 = x1;
+			float y8 = (yNMask | 1) + y1;
+			float z8 = z1;
+			value += (a8 * a8) * (a8 * a8) * grad3d(seed2,
+				ADD(xrbp, PRIME_X), ADD(yrbp, ym & MUL(PRIME_Y, 2)), ADD(zrbp, PRIME_Z), x8, y8, z8);
+			skip9 = 1;
+		}
+	}
+
+	int skipD = 0;
+	float aA = zAFlipMask0 + a0;
+	if (aA > 0) {
+		float xA = x0;
+		float yA = y0;
+		float zA = z0 - (zNMask | 1);
+		value += (aA * aA) * (aA * aA) * grad3d(seed,
+			ADD(xrbp, xm & PRIME_X), ADD(yrbp, ym & PRIME_Y), ADD(zrbp, ~zm & PRIME_Z), xA, yA, zA);
+	} else {
+		float aB = xAFlipMask0 + yAFlipMask0 + a0;
+		if (aB > 0) {
+			float xB = x0 - (xNMask | 1);
+			float yB = y0 - (yNMask | 1);
+			float zB = z0;
+			value += (aB * aB) * (aB * aB) * grad3d(seed,
+				ADD(xrbp, ~xm & PRIME_X), ADD(yrbp, ~ym & PRIME_Y), ADD(zrbp, zm & PRIME_Z), xB, yB, zB);
+		}
+
+		float aC = zAFlipMask1 + a1;
+		if (aC > 0) {
+			float xC = x1;
+			float yC = y1;
+			float zC = (zNMask | 1) + z1;
+			value += (aC * aC) * (aC * aC) * grad3d(seed2,
+				ADD(xrbp, PRIME_X), ADD(yrbp, PRIME_Y), ADD(zrbp, zm & MUL(PRIME_Z, 2)), xC, yC, zC);
+			skipD = 1;
+		}
+	}
+
+	if (!skip5) {
+		float a5 = yAFlipMask1 + zAFlipMask1 + a1;
+		if (a5 > 0) {
+			float x5 = x1;
+			float y5 = (yNMask | 1) + y1;
+			float z5 = (zNMask | 1) + z1;
+			value += (a5 * a5) * (a5 * a5) * grad3d(seed2,
+				ADD(xrbp, PRIME_X), ADD(yrbp, ym & MUL(PRIME_Y, 2)), ADD(zrbp, zm & MUL(PRIME_Z, 2)), x5, y5, z5);
+		}
+	}
+
+	if (!skip9) {
+		float a9 = xAFlipMask1 + zAFlipMask1 + a1;
+		if (a9 > 0) {
+			float x9 = (xNMask | 1) + x1;
+			float y9 = y1;
+			float z9 = (zNMask | 1) + z1;
+			value += (a9 * a9) * (a9 * a9) * grad3d(seed2,
+				ADD(xrbp, xm & MUL(PRIME_X, 2)), ADD(yrbp, PRIME_Y), ADD(zrbp, zm & MUL(PRIME_Z, 2)), x9, y9, z9);
+		}
+	}
+
+	if (!skipD) {
+		float aD = xAFlipMask1 + yAFlipMask1 + a1;
+		if (aD > 0) {
+			float xD = (xNMask | 1) + x1;
+			float yD = (yNMask | 1) + y1;
+			float zD = z1;
+			value += (aD * aD) * (aD * aD) * grad3d(seed2,
+				ADD(xrbp, xm & MUL(PRIME_X, 2)), ADD(yrbp, ym & MUL(PRIME_Y, 2)), ADD(zrbp, PRIME_Z), xD, yD, zD);
+		}
+	}
+
+	return value;
+}
+
+/* LatticeVertex4D of OpenSimplex2S.java, indexed by vertex code. */
+static struct {
+	float dx, dy, dz, dw;
+	int64_t xsvp, ysvp, zsvp, wsvp;
+} vertices4D[256];
+
+static void init_vertices4D(void)
+{
+	for (int i = 0; i < 256; i++) {
+		int xsv = ((i >> 0) & 3) - 1;
+		int ysv = ((i >> 2) & 3) - 1;
+		int zsv = ((i >> 4) & 3) - 1;
+		int wsv = ((i >> 6) & 3) - 1;
+		vertices4D[i].xsvp = MUL(xsv, PRIME_X);
+		vertices4D[i].ysvp = MUL(ysv, PRIME_Y);
+		vertices4D[i].zsvp = MUL(zsv, PRIME_Z);
+		vertices4D[i].wsvp = MUL(wsv, PRIME_W);
+		float ssv = (xsv + ysv + zsv + wsv) * S_UNSKEW_4D;
+		vertices4D[i].dx = -xsv - ssv;
+		vertices4D[i].dy = -ysv - ssv;
+		vertices4D[i].dz = -zsv - ssv;
+		vertices4D[i].dw = -wsv - ssv;
+	}
+}
+
+static float s_noise4(int64_t seed, double x, double y, double z, double w)
+{
+	double s = S_SKEW_4D * (x + y + z + w);
+	double xs = x + s, ys = y + s, zs = z + s, ws = w + s;
+
+	int xsb = fastFloor(xs), ysb = fastFloor(ys), zsb = fastFloor(zs), wsb = fastFloor(ws);
+	float xsi = (float)(xs - xsb), ysi = (float)(ys - ysb), zsi = (float)(zs - zsb), wsi = (float)(ws - wsb);
+
+	float ssi = (xsi + ysi + zsi + wsi) * S_UNSKEW_4D;
+	float xi = xsi + ssi, yi = ysi + ssi, zi = zsi + ssi, wi = wsi + ssi;
+
+	int64_t xsvp = MUL(xsb, PRIME_X), ysvp = MUL(ysb, PRIME_Y), zsvp = MUL(zsb, PRIME_Z), wsvp = MUL(wsb, PRIME_W);
+
+	float value = 0;
+	for (int code = 0; code < 256; code++) {
+		float dx = xi + vertices4D[code].dx, dy = yi + vertices4D[code].dy;
+		float dz = zi + vertices4D[code].dz, dw = wi + vertices4D[code].dw;
+		float a = (dx * dx + dy * dy) + (dz * dz + dw * dw);
+		if (a < S_RSQUARED_4D) {
+			a -= S_RSQUARED_4D;
+			a *= a;
+			value += a * a * grad4d(seed,
+				ADD(xsvp, vertices4D[code].xsvp), ADD(ysvp, vertices4D[code].ysvp),
+				ADD(zsvp, vertices4D[code].zsvp), ADD(wsvp, vertices4D[code].wsvp),
+				dx, dy, dz, dw);
+		}
+	}
+	return value;
+}
+
+int main(int argc, char **argv)
+{
+	int smooth;
+	char line[512];
+
+	if (argc != 2 || (strcmp(argv[1], "f") != 0 && strcmp(argv[1], "s") != 0)) {
+		fprintf(stderr, "usage: os2ref f|s < samples > samples\n");
+		return 2;
+	}
+	smooth = argv[1][0] == 's';
+	if (smooth) {
+		init_gradients(S_NORMALIZER_2D, S_NORMALIZER_3D, S_NORMALIZER_4D);
+		init_vertices4D();
+	} else {
+		init_gradients(F_NORMALIZER_2D, F_NORMALIZER_3D, F_NORMALIZER_4D);
+	}
+
+	while (fgets(line, sizeof line, stdin)) {
+		double c[5];
+		int n = 0;
+		char *p = line + 1, *last = line;
+		float v;
+
+		if (line[0] != '[')
+			continue;
+		while (n < 5) {
+			char *end;
+			c[n++] = strtod(p, &end);
+			if (*end != ',')
+				break;
+			last = end;
+			p = end + 1;
+		}
+		n--; /* the old value */
+
+		switch (n) {
+		case 2:
+			v = smooth ? s_noise2(0, c[0], c[1]) : f_noise2(0, c[0], c[1]);
+			break;
+		case 3:
+			v = smooth ? s_noise3(0, c[0], c[1], c[2]) : f_noise3(0, c[0], c[1], c[2]);
+			break;
+		case 4:
+			v = smooth ? s_noise4(0, c[0], c[1], c[2], c[3]) : f_noise4(0, c[0], c[1], c[2], c[3]);
+			break;
+		default:
+			fprintf(stderr, "os2ref: bad sample %s", line);
+			return 1;
+		}
+
+		printf("%.*s,%.17g]\n", (int)(last - line), line, (double)v);
+	}
+
+	return 0;
+}
@@ -5,6 +5,9 @@
 // geometry, or other randomly-influenced applications that require a random
 // gradient.
 //
+// Noise instances only read their permutation tables after construction, so a
+// single instance is safe for concurrent use by multiple goroutines.
+//
 // For more information on OpenSimplex noise, read more from the creator of the
 // algorithm: http://uniblock.tumblr.com/post/97868843242/noise
 package opensimplex
@@ -31,9 +34,29 @@ type Noise32 interface {
 	Eval4(x, y, z, w float32) float32
 }
 
-// New constructs a Noise instance with a 64-bit seed.
+// FixedNoise is a seeded fixed-point noise instance, see Fixed
+type FixedNoise interface {
+	Eval2(x, y Fixed) Fixed
+	Eval3(x, y, z Fixed) Fixed
+}
+
+// NoiseWithDerivatives is a seeded 64-bit noise instance that can also report
+// the analytic partial derivatives of its output at the evaluated point.
+type NoiseWithDerivatives interface {
+	Noise
+	Eval2Deriv(x, y float64) (v, dx, dy float64)
+	Eval3Deriv(x, y, z float64) (v, dx, dy, dz float64)
+	Eval4Deriv(x, y, z, w float64) (v, dx, dy, dz, dw float64)
+}
+
+// New constructs a Noise instance with a 64-bit seed. The returned value also
+// implements NoiseWithDerivatives, NoiseAt and Noise1D.
 func New(seed int64) Noise {
-	s := &noise{}
+	return newNoise(seed)
+}
+
+func newNoise(seed int64) *noise {
+	s := &noise{seed: seed}
 
 	source := make([]int16, 256)
 	for i := range source {
@@ -60,19 +83,71 @@ func New(seed int64) Noise {
 	return s
 }
 
-// New32 constructs a Noise32 instance with a 64-bit seed.
+// New32 constructs a Noise32 instance with a 64-bit seed. It evaluates the noise
+// natively in float32, so results differ slightly from those of New. The
+// returned value also implements Noise1D32.
 func New32(seed int64) Noise32 {
-	return &cast32Noise{base: New(seed)}
+	s := newNoise(seed)
+	return &noise32{seed: seed, perm: s.perm, permGradIndex3D: s.permGradIndex3D}
+}
+
+// NewFixed constructs a FixedNoise instance with a 64-bit seed. It evaluates the
+// noise with integer arithmetic only, so its output is the same on every
+// platform. For coordinates of magnitude up to 2^28 its output stays within
+// 2e-6 of the output of New; larger coordinates overflow.
+func NewFixed(seed int64) FixedNoise {
+	s := newNoise(seed)
+	return &noiseFixed{seed: seed, perm: s.perm, permGradIndex3D: s.permGradIndex3D}
 }
 
 // NewNormalized constructs a normalized Noise instance with a 64-bit seed. Eval methods will
 // return values in [0, 1).
 func NewNormalized(seed int64) Noise {
-	return &normNoise{base: New(seed)}
+	return NewRanged(New(seed), 0, 1, RangeLinear)
 }
 
 // NewNormalized32 constructs a normalized Noise32 instance with a 64-bit seed. Eval methods will
 // return values in [0, 1).
 func NewNormalized32(seed int64) Noise32 {
-	return &normNoise32{base: New(seed)}
+	return NewRanged32(New(seed), 0, 1, RangeLinear)
+}
+
+// NewOpenSimplex2F constructs a Noise instance using the OpenSimplex2 "Fast"
+// algorithm with a 64-bit seed. It has better isotropy than New, at the cost of
+// output that is unrelated to the original OpenSimplex algorithm.
+func NewOpenSimplex2F(seed int64) Noise {
+	return &openSimplex2F{seed: seed}
+}
+
+// NewOpenSimplex2F32 constructs a Noise32 instance using the OpenSimplex2
+// "Fast" algorithm with a 64-bit seed.
+func NewOpenSimplex2F32(seed int64) Noise32 {
+	return &openSimplex2F32{openSimplex2F{seed: seed}}
+}
+
+// NewOpenSimplex2S constructs a Noise instance using the OpenSimplex2 "Smooth"
+// (SuperSimplex) algorithm with a 64-bit seed. It is slower than
+// NewOpenSimplex2F, but its larger kernels give smoother output with fewer
+// directional artifacts, particularly in 3D.
+func NewOpenSimplex2S(seed int64) Noise {
+	return &openSimplex2S{seed: seed}
+}
+
+// NewOpenSimplex2S32 constructs a Noise32 instance using the OpenSimplex2
+// "Smooth" (SuperSimplex) algorithm with a 64-bit seed.
+func NewOpenSimplex2S32(seed int64) Noise32 {
+	return &openSimplex2S32{openSimplex2S{seed: seed}}
+}
+
+// NewDeterministic constructs a Noise instance with a 64-bit seed whose Eval2,
+// Eval3 and Eval4 return bit-identical results on every platform, for uses such
+// as lockstep multiplayer games where all clients must agree. The returned value
+// also implements NoiseWithDerivatives and GridNoise.
+//
+// The evaluation never fuses multiplications and additions into FMA
+// instructions, and coordinates beyond the int32 lattice are handled the same
+// way everywhere. New currently shares this implementation, but only
+// NewDeterministic guarantees to keep it.
+func NewDeterministic(seed int64) Noise {
+	return newNoise(seed)
 }
@@ -0,0 +1,95 @@
+/**
+ * Tests for the OpenSimplex2 noise variants.
+ *
+ * The opensimplex2*_test_samples.json.gz files use the format of
+ * opensimplex_test_samples.json.gz and hold seed 0 at 1024 random coordinates
+ * in [-1000, 1000) per dimension. The values are the output of noise2,
+ * noise3_Fallback and noise4_Fallback of the reference OpenSimplex2 and
+ * OpenSimplex2S, as transcribed line by line to C in internal/os2ref, which
+ * documents how to render them again.
+ *
+ * The per-vertex math is done in float32 without FMA barriers, so the samples
+ * are matched within float32Tolerance times the coordinate magnitude rather
+ * than exactly: architectures with fused multiply-add instructions may round
+ * differently.
+ */
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestOpenSimplex2FSamplesMatch(t *testing.T) {
+	matchSamples(t, "opensimplex2f_test_samples.json.gz", NewOpenSimplex2F(0), float32Tolerance)
+}
+
+func TestOpenSimplex2GradientSet4D(t *testing.T) {
+	if len(os2GradientSet4D) != 160*4 {
+		t.Fatalf("expected 160 4D gradients, got %d", len(os2GradientSet4D)/4)
+	}
+
+	sum := [4]float64{}
+	for i := 0; i < len(os2GradientSet4D); i += 4 {
+		g := os2GradientSet4D[i : i+4]
+		if l := math.Sqrt(g[0]*g[0] + g[1]*g[1] + g[2]*g[2] + g[3]*g[3]); math.Abs(l-1) > 1e-12 {
+			t.Errorf("4D gradient %d has length %v", i/4, l)
+		}
+		for j := range sum {
+			sum[j] += g[j]
+		}
+	}
+
+	for j, s := range sum {
+		if math.Abs(s) > 1e-12 {
+			t.Errorf("4D gradients are biased along axis %d by %v", j, s)
+		}
+	}
+}
+
+func TestOpenSimplex2FRange(t *testing.T) {
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	n := NewOpenSimplex2F(7)
+	n32 := NewOpenSimplex2F32(7)
+
+	for i := 0; i < 100000; i++ {
+		x, y, z, w := r.Float64()*200-100, r.Float64()*200-100, r.Float64()*200-100, r.Float64()*200-100
+		for _, v := range []float64{n.Eval2(x, y), n.Eval3(x, y, z), n.Eval4(x, y, z, w)} {
+			if math.Abs(v) > 1.001 {
+				t.Fatalf("OpenSimplex2F value %v out of [-1, 1]", v)
+			}
+		}
+
+		fx, fy := float32(x), float32(y)
+		if a, b := n32.Eval2(fx, fy), float32(n.Eval2(float64(fx), float64(fy))); a != b {
+			t.Fatalf("OpenSimplex2F32 value %v differs from OpenSimplex2F value %v", a, b)
+		}
+	}
+}
+
+func TestOpenSimplex2SSamplesMatch(t *testing.T) {
+	matchSamples(t, "opensimplex2s_test_samples.json.gz", NewOpenSimplex2S(0), float32Tolerance)
+}
+
+func TestOpenSimplex2SRange(t *testing.T) {
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	n := NewOpenSimplex2S(7)
+	n32 := NewOpenSimplex2S32(7)
+
+	for i := 0; i < 100000; i++ {
+		x, y, z, w := r.Float64()*200-100, r.Float64()*200-100, r.Float64()*200-100, r.Float64()*200-100
+		for _, v := range []float64{n.Eval2(x, y), n.Eval3(x, y, z), n.Eval4(x, y, z, w)} {
+			if math.Abs(v) > 1.001 {
+				t.Fatalf("OpenSimplex2S value %v out of [-1, 1]", v)
+			}
+		}
+
+		fx, fy, fz := float32(x), float32(y), float32(z)
+		if a, b := n32.Eval3(fx, fy, fz), float32(n.Eval3(float64(fx), float64(fy), float64(fz))); a != b {
+			t.Fatalf("OpenSimplex2S32 value %v differs from OpenSimplex2S value %v", a, b)
+		}
+	}
+}
@@ -0,0 +1,643 @@
+package opensimplex
+
+// OpenSimplex2 "Fast" implementation, following the structure of Kurt Spencer's
+// (KdotJPG) OpenSimplex2 Java reference: lattice points are hashed with prime
+// multiplication instead of a permutation table, and the gradients are aligned
+// with the lattice. Like the reference, the per-vertex math is done in float32
+// after the input has been skewed in float64.
+
+const (
+	os2PrimeX         int64 = 0x5205402B9270C86F
+	os2PrimeY         int64 = 0x598CD327003817B5
+	os2PrimeZ         int64 = 0x5BCC226E9FA0BACB
+	os2PrimeW         int64 = 0x56CC5227E58F554B
+	os2HashMultiplier int64 = 0x53A3F72DEEC546F5
+	os2SeedFlip3D     int64 = -0x52D547B2E96ED629
+	os2SeedOffset4D   int64 = 0xE83DC3E0DA7164D
+
+	os2Skew2D   = 0.366025403784439
+	os2Unskew2D = -0.21132486540518713
+
+	os2FallbackRotate3D = 2.0 / 3.0
+
+	os2Skew4D        float32 = -0.138196601125011
+	os2Unskew4D      float32 = 0.309016994374947
+	os2LatticeStep4D float32 = 0.2
+
+	os2GradsExponent2D = 7
+	os2GradsExponent3D = 8
+	os2GradsExponent4D = 9
+	os2Grads2D         = 1 << os2GradsExponent2D
+	os2Grads3D         = 1 << os2GradsExponent3D
+	os2Grads4D         = 1 << os2GradsExponent4D
+
+	os2fNormalizer2D = 0.01001634121365712
+	os2fNormalizer3D = 0.07969837668935331
+	os2fNormalizer4D = 0.0220065933241897
+
+	os2fRSquared2D float32 = 0.5
+	os2fRSquared3D float32 = 0.6
+	os2fRSquared4D float32 = 0.6
+)
+
+// The reference evaluates the constant expressions of the 2D noise in float64
+// and narrows the result to float32. Go evaluates constant expressions exactly
+// and rounds once, which can differ in the last place, so they are computed
+// from a variable instead.
+var (
+	os2Unskew2D64 float64 = os2Unskew2D
+
+	os2Unskew2D32   = float32(os2Unskew2D64)
+	os2Unskew2DP1   = float32(os2Unskew2D64 + 1)
+	os2Unskew2DX2P1 = float32(1 + 2*os2Unskew2D64)
+	os2Unskew2DX3P1 = float32(3*os2Unskew2D64 + 1)
+	os2Unskew2DX3P2 = float32(3*os2Unskew2D64 + 2)
+	os2A1Slope2D    = float32(2 * (1 + 2*os2Unskew2D64) * (1/os2Unskew2D64 + 2))
+	os2A1Offset2D   = float32(-2 * (1 + 2*os2Unskew2D64) * (1 + 2*os2Unskew2D64))
+)
+
+type openSimplex2F struct {
+	seed int64
+}
+
+// Eval2 returns a random noise value in two dimensions.
+func (n *openSimplex2F) Eval2(x, y float64) float64 {
+	return float64(n.noise2(x, y))
+}
+
+// Eval3 returns a random noise value in three dimensions.
+func (n *openSimplex2F) Eval3(x, y, z float64) float64 {
+	return float64(n.noise3(x, y, z))
+}
+
+// Eval4 returns a random noise value in four dimensions.
+func (n *openSimplex2F) Eval4(x, y, z, w float64) float64 {
+	return float64(n.noise4(x, y, z, w))
+}
+
+type openSimplex2F32 struct {
+	openSimplex2F
+}
+
+// Eval2 returns a random noise value in two dimensions.
+func (n *openSimplex2F32) Eval2(x, y float32) float32 {
+	return n.noise2(float64(x), float64(y))
+}
+
+// Eval3 returns a random noise value in three dimensions.
+func (n *openSimplex2F32) Eval3(x, y, z float32) float32 {
+	return n.noise3(float64(x), float64(y), float64(z))
+}
+
+// Eval4 returns a random noise value in four dimensions.
+func (n *openSimplex2F32) Eval4(x, y, z, w float32) float32 {
+	return n.noise4(float64(x), float64(y), float64(z), float64(w))
+}
+
+func (n *openSimplex2F) noise2(x, y float64) float32 {
+	// Get points for A2* lattice.
+	s := os2Skew2D * (x + y)
+	xs, ys := x+s, y+s
+
+	// Get base points and offsets.
+	xsb, ysb := os2FastFloor(xs), os2FastFloor(ys)
+	xi, yi := float32(xs-float64(xsb)), float32(ys-float64(ysb))
+
+	// Prime pre-multiplication for hash.
+	xsbp, ysbp := int64(xsb)*os2PrimeX, int64(ysb)*os2PrimeY
+
+	// Unskew.
+	t := (xi + yi) * os2Unskew2D32
+	dx0, dy0 := xi+t, yi+t
+
+	// First vertex.
+	var value float32
+	a0 := os2fRSquared2D - dx0*dx0 - dy0*dy0
+	if a0 > 0 {
+		value = (a0 * a0) * (a0 * a0) * os2Grad2(os2fGradients2D, n.seed, xsbp, ysbp, dx0, dy0)
+	}
+
+	// Second vertex.
+	a1 := os2A1Slope2D*t + (os2A1Offset2D + a0)
+	if a1 > 0 {
+		dx1 := dx0 - os2Unskew2DX2P1
+		dy1 := dy0 - os2Unskew2DX2P1
+		value += (a1 * a1) * (a1 * a1) * os2Grad2(os2fGradients2D, n.seed, xsbp+os2PrimeX, ysbp+os2PrimeY, dx1, dy1)
+	}
+
+	// Third vertex.
+	if dy0 > dx0 {
+		dx2 := dx0 - os2Unskew2D32
+		dy2 := dy0 - os2Unskew2DP1
+		a2 := os2fRSquared2D - dx2*dx2 - dy2*dy2
+		if a2 > 0 {
+			value += (a2 * a2) * (a2 * a2) * os2Grad2(os2fGradients2D, n.seed, xsbp, ysbp+os2PrimeY, dx2, dy2)
+		}
+	} else {
+		dx2 := dx0 - os2Unskew2DP1
+		dy2 := dy0 - os2Unskew2D32
+		a2 := os2fRSquared2D - dx2*dx2 - dy2*dy2
+		if a2 > 0 {
+			value += (a2 * a2) * (a2 * a2) * os2Grad2(os2fGradients2D, n.seed, xsbp+os2PrimeX, ysbp, dx2, dy2)
+		}
+	}
+
+	return value
+}
+
+func (n *openSimplex2F) noise3(x, y, z float64) float32 {
+	// Re-orient the cubic lattices via rotation, to produce a familiar look.
+	// Orthonormal rotation. Not a skew transform.
+	r := os2FallbackRotate3D * (x + y + z)
+	xr, yr, zr := r-x, r-y, r-z
+
+	// Get base points and offsets.
+	xrb, yrb, zrb := os2FastRound(xr), os2FastRound(yr), os2FastRound(zr)
+	xri, yri, zri := float32(xr-float64(xrb)), float32(yr-float64(yrb)), float32(zr-float64(zrb))
+
+	// -1 if positive, 1 if negative.
+	xNSign := int32(-1.0-xri) | 1
+	yNSign := int32(-1.0-yri) | 1
+	zNSign := int32(-1.0-zri) | 1
+
+	// Compute absolute values, using the above as a shortcut.
+	ax0 := float32(xNSign) * -xri
+	ay0 := float32(yNSign) * -yri
+	az0 := float32(zNSign) * -zri
+
+	// Prime pre-multiplication for hash.
+	xrbp, yrbp, zrbp := int64(xrb)*os2PrimeX, int64(yrb)*os2PrimeY, int64(zrb)*os2PrimeZ
+
+	// Loop: Pick an edge on each lattice copy.
+	seed := n.seed
+	var value float32
+	a := (os2fRSquared3D - xri*xri) - (yri*yri + zri*zri)
+	for l := 0; ; l++ {
+		// Closest point on cube.
+		if a > 0 {
+			value += (a * a) * (a * a) * os2Grad3(os2fGradients3D, seed, xrbp, yrbp, zrbp, xri, yri, zri)
+		}
+
+		// Second-closest point.
+		if ax0 >= ay0 && ax0 >= az0 {
+			b := a + ax0 + ax0
+			if b > 1 {
+				b--
+				value += (b * b) * (b * b) * os2Grad3(os2fGradients3D, seed, xrbp-int64(xNSign)*os2PrimeX, yrbp, zrbp, xri+float32(xNSign), yri, zri)
+			}
+		} else if ay0 > ax0 && ay0 >= az0 {
+			b := a + ay0 + ay0
+			if b > 1 {
+				b--
+				value += (b * b) * (b * b) * os2Grad3(os2fGradients3D, seed, xrbp, yrbp-int64(yNSign)*os2PrimeY, zrbp, xri, yri+float32(yNSign), zri)
+			}
+		} else {
+			b := a + az0 + az0
+			if b > 1 {
+				b--
+				value += (b * b) * (b * b) * os2Grad3(os2fGradients3D, seed, xrbp, yrbp, zrbp-int64(zNSign)*os2PrimeZ, xri, yri, zri+float32(zNSign))
+			}
+		}
+
+		// Break from loop if we're done, skipping updates below.
+		if l == 1 {
+			break
+		}
+
+		// Update absolute value.
+		ax0 = 0.5 - ax0
+		ay0 = 0.5 - ay0
+		az0 = 0.5 - az0
+
+		// Update relative coordinate.
+		xri = float32(xNSign) * ax0
+		yri = float32(yNSign) * ay0
+		zri = float32(zNSign) * az0
+
+		// Update falloff.
+		a += (0.75 - ax0) - (ay0 + az0)
+
+		// Update prime for hash.
+		xrbp += int64(xNSign>>1) & os2PrimeX
+		yrbp += int64(yNSign>>1) & os2PrimeY
+		zrbp += int64(zNSign>>1) & os2PrimeZ
+
+		// Update the reverse sign indicators.
+		xNSign = -xNSign
+		yNSign = -yNSign
+		zNSign = -zNSign
+
+		// And finally update the seed for the other lattice copy.
+		seed ^= os2SeedFlip3D
+	}
+
+	return value
+}
+
+func (n *openSimplex2F) noise4(x, y, z, w float64) float32 {
+	// Get points for A4 lattice.
+	s := float64(os2Skew4D) * (x + y + z + w)
+	xs, ys, zs, ws := x+s, y+s, z+s, w+s
+
+	// Get base points and offsets.
+	xsb, ysb, zsb, wsb := os2FastFloor(xs), os2FastFloor(ys), os2FastFloor(zs), os2FastFloor(ws)
+	xsi := float32(xs - float64(xsb))
+	ysi := float32(ys - float64(ysb))
+	zsi := float32(zs - float64(zsb))
+	wsi := float32(ws - float64(wsb))
+
+	// Determine which lattice we can be confident has a contributing point its
+	// corresponding cell's base simplex. We only look at the spaces between the
+	// diagonal planes.
+	siSum := (xsi + ysi) + (zsi + wsi)
+	startingLattice := int32(float64(siSum) * 1.25)
+
+	// Offset for seed based on first lattice copy.
+	seed := n.seed + int64(startingLattice)*os2SeedOffset4D
+
+	// Offset for lattice point relative positions (skewed).
+	startingLatticeOffset := float32(startingLattice) * -os2LatticeStep4D
+	xsi += startingLatticeOffset
+	ysi += startingLatticeOffset
+	zsi += startingLatticeOffset
+	wsi += startingLatticeOffset
+
+	// Prep for vertex contributions.
+	ssi := (siSum + startingLatticeOffset*4) * os2Unskew4D
+
+	// Prime pre-multiplication for hash.
+	xsvp, ysvp := int64(xsb)*os2PrimeX, int64(ysb)*os2PrimeY
+	zsvp, wsvp := int64(zsb)*os2PrimeZ, int64(wsb)*os2PrimeW
+
+	// Five points to add, total, from five copies of the A4 lattice.
+	var value float32
+	for i := int32(0); ; i++ {
+		// Next point is the closest vertex on the 4-simplex whose base vertex is
+		// the aforementioned vertex.
+		score0 := 1.0 + float64(float64(ssi)*(-1.0/float64(os2Unskew4D)))
+		if xsi >= ysi && xsi >= zsi && xsi >= wsi && float64(xsi) >= score0 {
+			xsvp += os2PrimeX
+			xsi--
+			ssi -= os2Unskew4D
+		} else if ysi > xsi && ysi >= zsi && ysi >= wsi && float64(ysi) >= score0 {
+			ysvp += os2PrimeY
+			ysi--
+			ssi -= os2Unskew4D
+		} else if zsi > xsi && zsi > ysi && zsi >= wsi && float64(zsi) >= score0 {
+			zsvp += os2PrimeZ
+			zsi--
+			ssi -= os2Unskew4D
+		} else if wsi > xsi && wsi > ysi && wsi > zsi && float64(wsi) >= score0 {
+			wsvp += os2PrimeW
+			wsi--
+			ssi -= os2Unskew4D
+		}
+
+		// Gradient contribution with falloff.
+		dx, dy, dz, dw := xsi+ssi, ysi+ssi, zsi+ssi, wsi+ssi
+		a := (dx*dx + dy*dy) + (dz*dz + dw*dw)
+		if a < os2fRSquared4D {
+			a -= os2fRSquared4D
+			a *= a
+			value += a * a * os2Grad4(os2fGradients4D, seed, xsvp, ysvp, zsvp, wsvp, dx, dy, dz, dw)
+		}
+
+		// Break from loop if we're done, skipping updates below.
+		if i == 4 {
+			break
+		}
+
+		// Update for next lattice copy shifted down by <-0.2, -0.2, -0.2, -0.2>.
+		xsi += os2LatticeStep4D
+		ysi += os2LatticeStep4D
+		zsi += os2LatticeStep4D
+		wsi += os2LatticeStep4D
+		ssi += os2LatticeStep4D * 4 * os2Unskew4D
+		seed -= os2SeedOffset4D
+
+		// Because we don't always start on the same lattice copy, there's a
+		// special reset case.
+		if i == startingLattice {
+			xsvp -= os2PrimeX
+			ysvp -= os2PrimeY
+			zsvp -= os2PrimeZ
+			wsvp -= os2PrimeW
+			seed += os2SeedOffset4D * 5
+		}
+	}
+
+	return value
+}
+
+func os2FastFloor(x float64) int32 {
+	xi := int32(x)
+	if x < float64(xi) {
+		return xi - 1
+	}
+	return xi
+}
+
+func os2FastRound(x float64) int32 {
+	if x < 0 {
+		return int32(x - 0.5)
+	}
+	return int32(x + 0.5)
+}
+
+// The os2Grad functions hash a lattice vertex, pre-multiplied by the primes,
+// into an index of the gradient table grads and return the dot product of that
+// gradient with the offset from the vertex.
+
+func os2Grad2(grads []float32, seed, xsvp, ysvp int64, dx, dy float32) float32 {
+	hash := seed ^ xsvp ^ ysvp
+	hash *= os2HashMultiplier
+	hash ^= hash >> (64 - os2GradsExponent2D + 1)
+	gi := int32(hash) & ((os2Grads2D - 1) << 1)
+	return grads[gi|0]*dx + grads[gi|1]*dy
+}
+
+func os2Grad3(grads []float32, seed, xrvp, yrvp, zrvp int64, dx, dy, dz float32) float32 {
+	hash := (seed ^ xrvp) ^ (yrvp ^ zrvp)
+	hash *= os2HashMultiplier
+	hash ^= hash >> (64 - os2GradsExponent3D + 2)
+	gi := int32(hash) & ((os2Grads3D - 1) << 2)
+	return grads[gi|0]*dx + grads[gi|1]*dy + grads[gi|2]*dz
+}
+
+func os2Grad4(grads []float32, seed, xsvp, ysvp, zsvp, wsvp int64, dx, dy, dz, dw float32) float32 {
+	hash := seed ^ (xsvp ^ ysvp) ^ (zsvp ^ wsvp)
+	hash *= os2HashMultiplier
+	hash ^= hash >> (64 - os2GradsExponent4D + 2)
+	gi := int32(hash) & ((os2Grads4D - 1) << 2)
+	return (grads[gi|0]*dx + grads[gi|1]*dy) + (grads[gi|2]*dz + grads[gi|3]*dw)
+}
+
+// os2Tile repeats the unnormalized gradient set src into a table of size
+// elements, dividing every component by normalizer. The reference declares the
+// gradient sets as float literals, so each component is rounded to float32
+// before the division.
+func os2Tile(src []float64, size int, normalizer float64) []float32 {
+	dst := make([]float32, size)
+	for i := range dst {
+		dst[i] = float32(float64(float32(src[i%len(src)])) / normalizer)
+	}
+
+	return dst
+}
+
+var (
+	os2fGradients2D = os2Tile(os2GradientSet2D, os2Grads2D*2, os2fNormalizer2D)
+	os2fGradients3D = os2Tile(os2GradientSet3D, os2Grads3D*4, os2fNormalizer3D)
+	os2fGradients4D = os2Tile(os2GradientSet4D, os2Grads4D*4, os2fNormalizer4D)
+)
+
+// Gradients for 2D. 24 unit vectors, 8 of them 45 degrees apart and the
+// remaining 16 filling the gaps in between.
+var os2GradientSet2D = []float64{
+	0.38268343236509, 0.923879532511287,
+	0.923879532511287, 0.38268343236509,
+	0.923879532511287, -0.38268343236509,
+	0.38268343236509, -0.923879532511287,
+	-0.38268343236509, -0.923879532511287,
+	-0.923879532511287, -0.38268343236509,
+	-0.923879532511287, 0.38268343236509,
+	-0.38268343236509, 0.923879532511287,
+	//-------------------------------------//
+	0.130526192220052, 0.99144486137381,
+	0.608761429008721, 0.793353340291235,
+	0.793353340291235, 0.608761429008721,
+	0.99144486137381, 0.130526192220051,
+	0.99144486137381, -0.130526192220051,
+	0.793353340291235, -0.60876142900872,
+	0.608761429008721, -0.793353340291235,
+	0.130526192220052, -0.99144486137381,
+	-0.130526192220052, -0.99144486137381,
+	-0.608761429008721, -0.793353340291235,
+	-0.793353340291235, -0.608761429008721,
+	-0.99144486137381, -0.130526192220052,
+	-0.99144486137381, 0.130526192220051,
+	-0.793353340291235, 0.608761429008721,
+	-0.608761429008721, 0.793353340291235,
+	-0.130526192220052, 0.99144486137381,
+}
+
+// Gradients for 3D, padded to four components. 48 vectors of equal length,
+// the permutations of (±2.2247, ±2.2247, ±1) and (±3.0863, ±1.1722, 0).
+var os2GradientSet3D = []float64{
+	2.22474487139, 2.22474487139, -1.0, 0.0,
+	2.22474487139, 2.22474487139, 1.0, 0.0,
+	3.0862664687972017, 1.1721513422464978, 0.0, 0.0,
+	1.1721513422464978, 3.0862664687972017, 0.0, 0.0,
+	-2.22474487139, 2.22474487139, -1.0, 0.0,
+	-2.22474487139, 2.22474487139, 1.0, 0.0,
+	-1.1721513422464978, 3.0862664687972017, 0.0, 0.0,
+	-3.0862664687972017, 1.1721513422464978, 0.0, 0.0,
+	-1.0, -2.22474487139, -2.22474487139, 0.0,
+	1.0, -2.22474487139, -2.22474487139, 0.0,
+	0.0, -3.0862664687972017, -1.1721513422464978, 0.0,
+	0.0, -1.1721513422464978, -3.0862664687972017, 0.0,
+	-1.0, -2.22474487139, 2.22474487139, 0.0,
+	1.0, -2.22474487139, 2.22474487139, 0.0,
+	0.0, -1.1721513422464978, 3.0862664687972017, 0.0,
+	0.0, -3.0862664687972017, 1.1721513422464978, 0.0,
+	//--------------------------------------------------------------------//
+	-2.22474487139, -2.22474487139, -1.0, 0.0,
+	-2.22474487139, -2.22474487139, 1.0, 0.0,
+	-3.0862664687972017, -1.1721513422464978, 0.0, 0.0,
+	-1.1721513422464978, -3.0862664687972017, 0.0, 0.0,
+	-2.22474487139, -1.0, -2.22474487139, 0.0,
+	-2.22474487139, 1.0, -2.22474487139, 0.0,
+	-1.1721513422464978, 0.0, -3.0862664687972017, 0.0,
+	-3.0862664687972017, 0.0, -1.1721513422464978, 0.0,
+	-2.22474487139, -1.0, 2.22474487139, 0.0,
+	-2.22474487139, 1.0, 2.22474487139, 0.0,
+	-3.0862664687972017, 0.0, 1.1721513422464978, 0.0,
+	-1.1721513422464978, 0.0, 3.0862664687972017, 0.0,
+	-1.0, 2.22474487139, -2.22474487139, 0.0,
+	1.0, 2.22474487139, -2.22474487139, 0.0,
+	0.0, 1.1721513422464978, -3.0862664687972017, 0.0,
+	0.0, 3.0862664687972017, -1.1721513422464978, 0.0,
+	-1.0, 2.22474487139, 2.22474487139, 0.0,
+	1.0, 2.22474487139, 2.22474487139, 0.0,
+	0.0, 3.0862664687972017, 1.1721513422464978, 0.0,
+	0.0, 1.1721513422464978, 3.0862664687972017, 0.0,
+	2.22474487139, -2.22474487139, -1.0, 0.0,
+	2.22474487139, -2.22474487139, 1.0, 0.0,
+	1.1721513422464978, -3.0862664687972017, 0.0, 0.0,
+	3.0862664687972017, -1.1721513422464978, 0.0, 0.0,
+	2.22474487139, -1.0, -2.22474487139, 0.0,
+	2.22474487139, 1.0, -2.22474487139, 0.0,
+	3.0862664687972017, 0.0, -1.1721513422464978, 0.0,
+	1.1721513422464978, 0.0, -3.0862664687972017, 0.0,
+	2.22474487139, -1.0, 2.22474487139, 0.0,
+	2.22474487139, 1.0, 2.22474487139, 0.0,
+	1.1721513422464978, 0.0, 3.0862664687972017, 0.0,
+	3.0862664687972017, 0.0, 1.1721513422464978, 0.0,
+}
+
+// Gradients for 4D, ported from the Java reference. 160 unit vectors: for each
+// axis, 24 vectors pointing away from it towards one of the other axes and 8
+// vectors close to its negative direction, followed by the 32 vectors close to
+// the positive directions of the axes.
+var os2GradientSet4D = []float64{
+	-0.6740059517812944, -0.3239847771997537, -0.3239847771997537, 0.5794684678643381,
+	-0.7504883828755602, -0.4004672082940195, 0.15296486218853164, 0.5029860367700724,
+	-0.7504883828755602, 0.15296486218853164, -0.4004672082940195, 0.5029860367700724,
+	-0.8828161875373585, 0.08164729285680945, 0.08164729285680945, 0.4553054119602712,
+	-0.4553054119602712, -0.08164729285680945, -0.08164729285680945, 0.8828161875373585,
+	-0.5029860367700724, -0.15296486218853164, 0.4004672082940195, 0.7504883828755602,
+	-0.5029860367700724, 0.4004672082940195, -0.15296486218853164, 0.7504883828755602,
+	-0.5794684678643381, 0.3239847771997537, 0.3239847771997537, 0.6740059517812944,
+	-0.6740059517812944, -0.3239847771997537, 0.5794684678643381, -0.3239847771997537,
+	-0.7504883828755602, -0.4004672082940195, 0.5029860367700724, 0.15296486218853164,
+	-0.7504883828755602, 0.15296486218853164, 0.5029860367700724, -0.4004672082940195,
+	-0.8828161875373585, 0.08164729285680945, 0.4553054119602712, 0.08164729285680945,
+	-0.4553054119602712, -0.08164729285680945, 0.8828161875373585, -0.08164729285680945,
+	-0.5029860367700724, -0.15296486218853164, 0.7504883828755602, 0.4004672082940195,
+	-0.5029860367700724, 0.4004672082940195, 0.7504883828755602, -0.15296486218853164,
+	-0.5794684678643381, 0.3239847771997537, 0.6740059517812944, 0.3239847771997537,
+	-0.6740059517812944, 0.5794684678643381, -0.3239847771997537, -0.3239847771997537,
+	-0.7504883828755602, 0.5029860367700724, -0.4004672082940195, 0.15296486218853164,
+	-0.7504883828755602, 0.5029860367700724, 0.15296486218853164, -0.4004672082940195,
+	-0.8828161875373585, 0.4553054119602712, 0.08164729285680945, 0.08164729285680945,
+	-0.4553054119602712, 0.8828161875373585, -0.08164729285680945, -0.08164729285680945,
+	-0.5029860367700724, 0.7504883828755602, -0.15296486218853164, 0.4004672082940195,
+	-0.5029860367700724, 0.7504883828755602, 0.4004672082940195, -0.15296486218853164,
+	-0.5794684678643381, 0.6740059517812944, 0.3239847771997537, 0.3239847771997537,
+	-0.753341017856078, -0.37968289875261624, -0.37968289875261624, -0.37968289875261624,
+	-0.7821684431180708, -0.4321472685365301, -0.4321472685365301, 0.12128480194602098,
+	-0.7821684431180708, -0.4321472685365301, 0.12128480194602098, -0.4321472685365301,
+	-0.7821684431180708, 0.12128480194602098, -0.4321472685365301, -0.4321472685365301,
+	-0.8586508742123365, -0.508629699630796, 0.044802370851755174, 0.044802370851755174,
+	-0.8586508742123365, 0.044802370851755174, -0.508629699630796, 0.044802370851755174,
+	-0.8586508742123365, 0.044802370851755174, 0.044802370851755174, -0.508629699630796,
+	-0.9982828964265062, -0.03381941603233842, -0.03381941603233842, -0.03381941603233842,
+	-0.3239847771997537, -0.6740059517812944, -0.3239847771997537, 0.5794684678643381,
+	-0.4004672082940195, -0.7504883828755602, 0.15296486218853164, 0.5029860367700724,
+	0.15296486218853164, -0.7504883828755602, -0.4004672082940195, 0.5029860367700724,
+	0.08164729285680945, -0.8828161875373585, 0.08164729285680945, 0.4553054119602712,
+	-0.08164729285680945, -0.4553054119602712, -0.08164729285680945, 0.8828161875373585,
+	-0.15296486218853164, -0.5029860367700724, 0.4004672082940195, 0.7504883828755602,
+	0.4004672082940195, -0.5029860367700724, -0.15296486218853164, 0.7504883828755602,
+	0.3239847771997537, -0.5794684678643381, 0.3239847771997537, 0.6740059517812944,
+	-0.3239847771997537, -0.6740059517812944, 0.5794684678643381, -0.3239847771997537,
+	-0.4004672082940195, -0.7504883828755602, 0.5029860367700724, 0.15296486218853164,
+	0.15296486218853164, -0.7504883828755602, 0.5029860367700724, -0.4004672082940195,
+	0.08164729285680945, -0.8828161875373585, 0.4553054119602712, 0.08164729285680945,
+	-0.08164729285680945, -0.4553054119602712, 0.8828161875373585, -0.08164729285680945,
+	-0.15296486218853164, -0.5029860367700724, 0.7504883828755602, 0.4004672082940195,
+	0.4004672082940195, -0.5029860367700724, 0.7504883828755602, -0.15296486218853164,
+	0.3239847771997537, -0.5794684678643381, 0.6740059517812944, 0.3239847771997537,
+	0.5794684678643381, -0.6740059517812944, -0.3239847771997537, -0.3239847771997537,
+	0.5029860367700724, -0.7504883828755602, -0.4004672082940195, 0.15296486218853164,
+	0.5029860367700724, -0.7504883828755602, 0.15296486218853164, -0.4004672082940195,
+	0.4553054119602712, -0.8828161875373585, 0.08164729285680945, 0.08164729285680945,
+	0.8828161875373585, -0.4553054119602712, -0.08164729285680945, -0.08164729285680945,
+	0.7504883828755602, -0.5029860367700724, -0.15296486218853164, 0.4004672082940195,
+	0.7504883828755602, -0.5029860367700724, 0.4004672082940195, -0.15296486218853164,
+	0.6740059517812944, -0.5794684678643381, 0.3239847771997537, 0.3239847771997537,
+	-0.37968289875261624, -0.753341017856078, -0.37968289875261624, -0.37968289875261624,
+	-0.4321472685365301, -0.7821684431180708, -0.4321472685365301, 0.12128480194602098,
+	-0.4321472685365301, -0.7821684431180708, 0.12128480194602098, -0.4321472685365301,
+	0.12128480194602098, -0.7821684431180708, -0.4321472685365301, -0.4321472685365301,
+	-0.508629699630796, -0.8586508742123365, 0.044802370851755174, 0.044802370851755174,
+	0.044802370851755174, -0.8586508742123365, -0.508629699630796, 0.044802370851755174,
+	0.044802370851755174, -0.8586508742123365, 0.044802370851755174, -0.508629699630796,
+	-0.03381941603233842, -0.9982828964265062, -0.03381941603233842, -0.03381941603233842,
+	-0.3239847771997537, -0.3239847771997537, -0.6740059517812944, 0.5794684678643381,
+	-0.4004672082940195, 0.15296486218853164, -0.7504883828755602, 0.5029860367700724,
+	0.15296486218853164, -0.4004672082940195, -0.7504883828755602, 0.5029860367700724,
+	0.08164729285680945, 0.08164729285680945, -0.8828161875373585, 0.4553054119602712,
+	-0.08164729285680945, -0.08164729285680945, -0.4553054119602712, 0.8828161875373585,
+	-0.15296486218853164, 0.4004672082940195, -0.5029860367700724, 0.7504883828755602,
+	0.4004672082940195, -0.15296486218853164, -0.5029860367700724, 0.7504883828755602,
+	0.3239847771997537, 0.3239847771997537, -0.5794684678643381, 0.6740059517812944,
+	-0.3239847771997537, 0.5794684678643381, -0.6740059517812944, -0.3239847771997537,
+	-0.4004672082940195, 0.5029860367700724, -0.7504883828755602, 0.15296486218853164,
+	0.15296486218853164, 0.5029860367700724, -0.7504883828755602, -0.4004672082940195,
+	0.08164729285680945, 0.4553054119602712, -0.8828161875373585, 0.08164729285680945,
+	-0.08164729285680945, 0.8828161875373585, -0.4553054119602712, -0.08164729285680945,
+	-0.15296486218853164, 0.7504883828755602, -0.5029860367700724, 0.4004672082940195,
+	0.4004672082940195, 0.7504883828755602, -0.5029860367700724, -0.15296486218853164,
+	0.3239847771997537, 0.6740059517812944, -0.5794684678643381, 0.3239847771997537,
+	0.5794684678643381, -0.3239847771997537, -0.6740059517812944, -0.3239847771997537,
+	0.5029860367700724, -0.4004672082940195, -0.7504883828755602, 0.15296486218853164,
+	0.5029860367700724, 0.15296486218853164, -0.7504883828755602, -0.4004672082940195,
+	0.4553054119602712, 0.08164729285680945, -0.8828161875373585, 0.08164729285680945,
+	0.8828161875373585, -0.08164729285680945, -0.4553054119602712, -0.08164729285680945,
+	0.7504883828755602, -0.15296486218853164, -0.5029860367700724, 0.4004672082940195,
+	0.7504883828755602, 0.4004672082940195, -0.5029860367700724, -0.15296486218853164,
+	0.6740059517812944, 0.3239847771997537, -0.5794684678643381, 0.3239847771997537,
+	-0.37968289875261624, -0.37968289875261624, -0.753341017856078, -0.37968289875261624,
+	-0.4321472685365301, -0.4321472685365301, -0.7821684431180708, 0.12128480194602098,
+	-0.4321472685365301, 0.12128480194602098, -0.7821684431180708, -0.4321472685365301,
+	0.12128480194602098, -0.4321472685365301, -0.7821684431180708, -0.4321472685365301,
+	-0.508629699630796, 0.044802370851755174, -0.8586508742123365, 0.044802370851755174,
+	0.044802370851755174, -0.508629699630796, -0.8586508742123365, 0.044802370851755174,
+	0.044802370851755174, 0.044802370851755174, -0.8586508742123365, -0.508629699630796,
+	-0.03381941603233842, -0.03381941603233842, -0.9982828964265062, -0.03381941603233842,
+	-0.3239847771997537, -0.3239847771997537, 0.5794684678643381, -0.6740059517812944,
+	-0.4004672082940195, 0.15296486218853164, 0.5029860367700724, -0.7504883828755602,
+	0.15296486218853164, -0.4004672082940195, 0.5029860367700724, -0.7504883828755602,
+	0.08164729285680945, 0.08164729285680945, 0.4553054119602712, -0.8828161875373585,
+	-0.08164729285680945, -0.08164729285680945, 0.8828161875373585, -0.4553054119602712,
+	-0.15296486218853164, 0.4004672082940195, 0.7504883828755602, -0.5029860367700724,
+	0.4004672082940195, -0.15296486218853164, 0.7504883828755602, -0.5029860367700724,
+	0.3239847771997537, 0.3239847771997537, 0.6740059517812944, -0.5794684678643381,
+	-0.3239847771997537, 0.5794684678643381, -0.3239847771997537, -0.6740059517812944,
+	-0.4004672082940195, 0.5029860367700724, 0.15296486218853164, -0.7504883828755602,
+	0.15296486218853164, 0.5029860367700724, -0.4004672082940195, -0.7504883828755602,
+	0.08164729285680945, 0.4553054119602712, 0.08164729285680945, -0.8828161875373585,
+	-0.08164729285680945, 0.8828161875373585, -0.08164729285680945, -0.4553054119602712,
+	-0.15296486218853164, 0.7504883828755602, 0.4004672082940195, -0.5029860367700724,
+	0.4004672082940195, 0.7504883828755602, -0.15296486218853164, -0.5029860367700724,
+	0.3239847771997537, 0.6740059517812944, 0.3239847771997537, -0.5794684678643381,
+	0.5794684678643381, -0.3239847771997537, -0.3239847771997537, -0.6740059517812944,
+	0.5029860367700724, -0.4004672082940195, 0.15296486218853164, -0.7504883828755602,
+	0.5029860367700724, 0.15296486218853164, -0.4004672082940195, -0.7504883828755602,
+	0.4553054119602712, 0.08164729285680945, 0.08164729285680945, -0.8828161875373585,
+	0.8828161875373585, -0.08164729285680945, -0.08164729285680945, -0.4553054119602712,
+	0.7504883828755602, -0.15296486218853164, 0.4004672082940195, -0.5029860367700724,
+	0.7504883828755602, 0.4004672082940195, -0.15296486218853164, -0.5029860367700724,
+	0.6740059517812944, 0.3239847771997537, 0.3239847771997537, -0.5794684678643381,
+	-0.37968289875261624, -0.37968289875261624, -0.37968289875261624, -0.753341017856078,
+	-0.4321472685365301, -0.4321472685365301, 0.12128480194602098, -0.7821684431180708,
+	-0.4321472685365301, 0.12128480194602098, -0.4321472685365301, -0.7821684431180708,
+	0.12128480194602098, -0.4321472685365301, -0.4321472685365301, -0.7821684431180708,
+	-0.508629699630796, 0.044802370851755174, 0.044802370851755174, -0.8586508742123365,
+	0.044802370851755174, -0.508629699630796, 0.044802370851755174, -0.8586508742123365,
+	0.044802370851755174, 0.044802370851755174, -0.508629699630796, -0.8586508742123365,
+	-0.03381941603233842, -0.03381941603233842, -0.03381941603233842, -0.9982828964265062,
+	0.753341017856078, 0.37968289875261624, 0.37968289875261624, 0.37968289875261624,
+	0.7821684431180708, 0.4321472685365301, 0.4321472685365301, -0.12128480194602098,
+	0.7821684431180708, 0.4321472685365301, -0.12128480194602098, 0.4321472685365301,
+	0.7821684431180708, -0.12128480194602098, 0.4321472685365301, 0.4321472685365301,
+	0.8586508742123365, 0.508629699630796, -0.044802370851755174, -0.044802370851755174,
+	0.8586508742123365, -0.044802370851755174, 0.508629699630796, -0.044802370851755174,
+	0.8586508742123365, -0.044802370851755174, -0.044802370851755174, 0.508629699630796,
+	0.9982828964265062, 0.03381941603233842, 0.03381941603233842, 0.03381941603233842,
+	0.37968289875261624, 0.753341017856078, 0.37968289875261624, 0.37968289875261624,
+	0.4321472685365301, 0.7821684431180708, 0.4321472685365301, -0.12128480194602098,
+	0.4321472685365301, 0.7821684431180708, -0.12128480194602098, 0.4321472685365301,
+	-0.12128480194602098, 0.7821684431180708, 0.4321472685365301, 0.4321472685365301,
+	0.508629699630796, 0.8586508742123365, -0.044802370851755174, -0.044802370851755174,
+	-0.044802370851755174, 0.8586508742123365, 0.508629699630796, -0.044802370851755174,
+	-0.044802370851755174, 0.8586508742123365, -0.044802370851755174, 0.508629699630796,
+	0.03381941603233842, 0.9982828964265062, 0.03381941603233842, 0.03381941603233842,
+	0.37968289875261624, 0.37968289875261624, 0.753341017856078, 0.37968289875261624,
+	0.4321472685365301, 0.4321472685365301, 0.7821684431180708, -0.12128480194602098,
+	0.4321472685365301, -0.12128480194602098, 0.7821684431180708, 0.4321472685365301,
+	-0.12128480194602098, 0.4321472685365301, 0.7821684431180708, 0.4321472685365301,
+	0.508629699630796, -0.044802370851755174, 0.8586508742123365, -0.044802370851755174,
+	-0.044802370851755174, 0.508629699630796, 0.8586508742123365, -0.044802370851755174,
+	-0.044802370851755174, -0.044802370851755174, 0.8586508742123365, 0.508629699630796,
+	0.03381941603233842, 0.03381941603233842, 0.9982828964265062, 0.03381941603233842,
+	0.37968289875261624, 0.37968289875261624, 0.37968289875261624, 0.753341017856078,
+	0.4321472685365301, 0.4321472685365301, -0.12128480194602098, 0.7821684431180708,
+	0.4321472685365301, -0.12128480194602098, 0.4321472685365301, 0.7821684431180708,
+	-0.12128480194602098, 0.4321472685365301, 0.4321472685365301, 0.7821684431180708,
+	0.508629699630796, -0.044802370851755174, -0.044802370851755174, 0.8586508742123365,
+	-0.044802370851755174, 0.508629699630796, -0.044802370851755174, 0.8586508742123365,
+	-0.044802370851755174, -0.044802370851755174, 0.508629699630796, 0.8586508742123365,
+	0.03381941603233842, 0.03381941603233842, 0.03381941603233842, 0.9982828964265062,
+}
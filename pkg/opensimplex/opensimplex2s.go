@@ -0,0 +1,432 @@
+package opensimplex
+
+// OpenSimplex2S "SuperSimplex" implementation. It shares the hashing and the
+// gradient sets of OpenSimplex2F (opensimplex2f.go), but uses a larger kernel
+// radius and sums the contributions of more lattice vertices, which gives
+// smoother output with fewer directional artifacts.
+
+const (
+	// The primes doubled, wrapping around like the Java reference's long
+	// arithmetic does.
+	os2PrimeX2 int64 = -0x5BF57FA8DB1E6F22 // 0x5205402B9270C86F * 2
+	os2PrimeY2 int64 = -0x4CE659B1FF8FD096 // 0x598CD327003817B5 * 2
+	os2PrimeZ2 int64 = -0x4867BB22C0BE8A6A // 0x5BCC226E9FA0BACB * 2
+
+	os2sNormalizer2D = 0.05481866495625118
+	os2sNormalizer3D = 0.2781926117527186
+	os2sNormalizer4D = 0.11127401889945551
+
+	os2sRSquared2D float32 = 2.0 / 3.0
+	os2sRSquared3D float32 = 3.0 / 4.0
+	os2sRSquared4D float32 = 4.0 / 5.0
+
+	// The 4D variant runs on the A4* lattice, skewed in the opposite direction
+	// of the A4 lattice of OpenSimplex2F.
+	os2sSkew4D   float32 = 0.309016994374947
+	os2sUnskew4D float32 = -0.138196601125011
+)
+
+type openSimplex2S struct {
+	seed int64
+}
+
+// Eval2 returns a random noise value in two dimensions.
+func (n *openSimplex2S) Eval2(x, y float64) float64 {
+	return float64(n.noise2(x, y))
+}
+
+// Eval3 returns a random noise value in three dimensions.
+func (n *openSimplex2S) Eval3(x, y, z float64) float64 {
+	return float64(n.noise3(x, y, z))
+}
+
+// Eval4 returns a random noise value in four dimensions.
+func (n *openSimplex2S) Eval4(x, y, z, w float64) float64 {
+	return float64(n.noise4(x, y, z, w))
+}
+
+type openSimplex2S32 struct {
+	openSimplex2S
+}
+
+// Eval2 returns a random noise value in two dimensions.
+func (n *openSimplex2S32) Eval2(x, y float32) float32 {
+	return n.noise2(float64(x), float64(y))
+}
+
+// Eval3 returns a random noise value in three dimensions.
+func (n *openSimplex2S32) Eval3(x, y, z float32) float32 {
+	return n.noise3(float64(x), float64(y), float64(z))
+}
+
+// Eval4 returns a random noise value in four dimensions.
+func (n *openSimplex2S32) Eval4(x, y, z, w float32) float32 {
+	return n.noise4(float64(x), float64(y), float64(z), float64(w))
+}
+
+//gocyclo:ignore
+func (n *openSimplex2S) noise2(x, y float64) float32 {
+	// Get points for A2* lattice.
+	s := os2Skew2D * (x + y)
+	xs, ys := x+s, y+s
+
+	// Get base points and offsets.
+	xsb, ysb := os2FastFloor(xs), os2FastFloor(ys)
+	xi, yi := float32(xs-float64(xsb)), float32(ys-float64(ysb))
+
+	// Prime pre-multiplication for hash.
+	xsbp, ysbp := int64(xsb)*os2PrimeX, int64(ysb)*os2PrimeY
+
+	// Unskew.
+	t := (xi + yi) * os2Unskew2D32
+	dx0, dy0 := xi+t, yi+t
+
+	// First vertex.
+	a0 := os2sRSquared2D - dx0*dx0 - dy0*dy0
+	value := (a0 * a0) * (a0 * a0) * os2Grad2(os2sGradients2D, n.seed, xsbp, ysbp, dx0, dy0)
+
+	// Second vertex.
+	a1 := os2A1Slope2D*t + (os2A1Offset2D + a0)
+	dx1 := dx0 - os2Unskew2DX2P1
+	dy1 := dy0 - os2Unskew2DX2P1
+	value += (a1 * a1) * (a1 * a1) * os2Grad2(os2sGradients2D, n.seed, xsbp+os2PrimeX, ysbp+os2PrimeY, dx1, dy1)
+
+	// Third and fourth vertices.
+	xmyi := xi - yi
+	if float64(t) < os2Unskew2D {
+		if xi+xmyi > 1 {
+			dx2 := dx0 - os2Unskew2DX3P2
+			dy2 := dy0 - os2Unskew2DX3P1
+			a2 := os2sRSquared2D - dx2*dx2 - dy2*dy2
+			if a2 > 0 {
+				value += (a2 * a2) * (a2 * a2) * os2Grad2(os2sGradients2D, n.seed, xsbp+os2PrimeX2, ysbp+os2PrimeY, dx2, dy2)
+			}
+		} else {
+			dx2 := dx0 - os2Unskew2D32
+			dy2 := dy0 - os2Unskew2DP1
+			a2 := os2sRSquared2D - dx2*dx2 - dy2*dy2
+			if a2 > 0 {
+				value += (a2 * a2) * (a2 * a2) * os2Grad2(os2sGradients2D, n.seed, xsbp, ysbp+os2PrimeY, dx2, dy2)
+			}
+		}
+
+		if yi-xmyi > 1 {
+			dx3 := dx0 - os2Unskew2DX3P1
+			dy3 := dy0 - os2Unskew2DX3P2
+			a3 := os2sRSquared2D - dx3*dx3 - dy3*dy3
+			if a3 > 0 {
+				value += (a3 * a3) * (a3 * a3) * os2Grad2(os2sGradients2D, n.seed, xsbp+os2PrimeX, ysbp+os2PrimeY2, dx3, dy3)
+			}
+		} else {
+			dx3 := dx0 - os2Unskew2DP1
+			dy3 := dy0 - os2Unskew2D32
+			a3 := os2sRSquared2D - dx3*dx3 - dy3*dy3
+			if a3 > 0 {
+				value += (a3 * a3) * (a3 * a3) * os2Grad2(os2sGradients2D, n.seed, xsbp+os2PrimeX, ysbp, dx3, dy3)
+			}
+		}
+	} else {
+		if xi+xmyi < 0 {
+			dx2 := dx0 + os2Unskew2DP1
+			dy2 := dy0 + os2Unskew2D32
+			a2 := os2sRSquared2D - dx2*dx2 - dy2*dy2
+			if a2 > 0 {
+				value += (a2 * a2) * (a2 * a2) * os2Grad2(os2sGradients2D, n.seed, xsbp-os2PrimeX, ysbp, dx2, dy2)
+			}
+		} else {
+			dx2 := dx0 - os2Unskew2DP1
+			dy2 := dy0 - os2Unskew2D32
+			a2 := os2sRSquared2D - dx2*dx2 - dy2*dy2
+			if a2 > 0 {
+				value += (a2 * a2) * (a2 * a2) * os2Grad2(os2sGradients2D, n.seed, xsbp+os2PrimeX, ysbp, dx2, dy2)
+			}
+		}
+
+		if yi < xmyi {
+			dx2 := dx0 + os2Unskew2D32
+			dy2 := dy0 + os2Unskew2DP1
+			a2 := os2sRSquared2D - dx2*dx2 - dy2*dy2
+			if a2 > 0 {
+				value += (a2 * a2) * (a2 * a2) * os2Grad2(os2sGradients2D, n.seed, xsbp, ysbp-os2PrimeY, dx2, dy2)
+			}
+		} else {
+			dx2 := dx0 - os2Unskew2D32
+			dy2 := dy0 - os2Unskew2DP1
+			a2 := os2sRSquared2D - dx2*dx2 - dy2*dy2
+			if a2 > 0 {
+				value += (a2 * a2) * (a2 * a2) * os2Grad2(os2sGradients2D, n.seed, xsbp, ysbp+os2PrimeY, dx2, dy2)
+			}
+		}
+	}
+
+	return value
+}
+
+//gocyclo:ignore
+func (n *openSimplex2S) noise3(x, y, z float64) float32 {
+	// Re-orient the cubic lattices via rotation, to produce a familiar look.
+	// Orthonormal rotation. Not a skew transform.
+	r := os2FallbackRotate3D * (x + y + z)
+	xr, yr, zr := r-x, r-y, r-z
+
+	// Get base points and offsets.
+	xrb, yrb, zrb := os2FastFloor(xr), os2FastFloor(yr), os2FastFloor(zr)
+	xi, yi, zi := float32(xr-float64(xrb)), float32(yr-float64(yrb)), float32(zr-float64(zrb))
+
+	// Prime pre-multiplication for hash. Also flip seed for second lattice copy.
+	xrbp, yrbp, zrbp := int64(xrb)*os2PrimeX, int64(yrb)*os2PrimeY, int64(zrb)*os2PrimeZ
+	seed := n.seed
+	seed2 := seed ^ os2SeedFlip3D
+
+	// -1 if positive, 0 if negative.
+	xNMask, yNMask, zNMask := int32(-0.5-xi), int32(-0.5-yi), int32(-0.5-zi)
+	xNMask64, yNMask64, zNMask64 := int64(xNMask), int64(yNMask), int64(zNMask)
+
+	// First vertex.
+	x0 := xi + float32(xNMask)
+	y0 := yi + float32(yNMask)
+	z0 := zi + float32(zNMask)
+	a0 := os2sRSquared3D - x0*x0 - y0*y0 - z0*z0
+	value := (a0 * a0) * (a0 * a0) * os2Grad3(os2sGradients3D, seed,
+		xrbp+(xNMask64&os2PrimeX), yrbp+(yNMask64&os2PrimeY), zrbp+(zNMask64&os2PrimeZ), x0, y0, z0)
+
+	// Second vertex.
+	x1 := xi - 0.5
+	y1 := yi - 0.5
+	z1 := zi - 0.5
+	a1 := os2sRSquared3D - x1*x1 - y1*y1 - z1*z1
+	value += (a1 * a1) * (a1 * a1) * os2Grad3(os2sGradients3D, seed2,
+		xrbp+os2PrimeX, yrbp+os2PrimeY, zrbp+os2PrimeZ, x1, y1, z1)
+
+	// Shortcuts for building the remaining falloffs.
+	// Derived by subtracting the polynomials with the offsets plugged in.
+	xAFlipMask0 := float32((xNMask|1)<<1) * x1
+	yAFlipMask0 := float32((yNMask|1)<<1) * y1
+	zAFlipMask0 := float32((zNMask|1)<<1) * z1
+	xAFlipMask1 := float32(-2-(xNMask<<2))*x1 - 1.0
+	yAFlipMask1 := float32(-2-(yNMask<<2))*y1 - 1.0
+	zAFlipMask1 := float32(-2-(zNMask<<2))*z1 - 1.0
+
+	skip5 := false
+	a2 := xAFlipMask0 + a0
+	if a2 > 0 {
+		x2 := x0 - float32(xNMask|1)
+		y2 := y0
+		z2 := z0
+		value += (a2 * a2) * (a2 * a2) * os2Grad3(os2sGradients3D, seed,
+			xrbp+(^xNMask64&os2PrimeX), yrbp+(yNMask64&os2PrimeY), zrbp+(zNMask64&os2PrimeZ), x2, y2, z2)
+	} else {
+		a3 := yAFlipMask0 + zAFlipMask0 + a0
+		if a3 > 0 {
+			x3 := x0
+			y3 := y0 - float32(yNMask|1)
+			z3 := z0 - float32(zNMask|1)
+			value += (a3 * a3) * (a3 * a3) * os2Grad3(os2sGradients3D, seed,
+				xrbp+(xNMask64&os2PrimeX), yrbp+(^yNMask64&os2PrimeY), zrbp+(^zNMask64&os2PrimeZ), x3, y3, z3)
+		}
+
+		a4 := xAFlipMask1 + a1
+		if a4 > 0 {
+			x4 := float32(xNMask|1) + x1
+			y4 := y1
+			z4 := z1
+			value += (a4 * a4) * (a4 * a4) * os2Grad3(os2sGradients3D, seed2,
+				xrbp+(xNMask64&os2PrimeX2), yrbp+os2PrimeY, zrbp+os2PrimeZ, x4, y4, z4)
+			skip5 = true
+		}
+	}
+
+	skip9 := false
+	a6 := yAFlipMask0 + a0
+	if a6 > 0 {
+		x6 := x0
+		y6 := y0 - float32(yNMask|1)
+		z6 := z0
+		value += (a6 * a6) * (a6 * a6) * os2Grad3(os2sGradients3D, seed,
+			xrbp+(xNMask64&os2PrimeX), yrbp+(^yNMask64&os2PrimeY), zrbp+(zNMask64&os2PrimeZ), x6, y6, z6)
+	} else {
+		a7 := xAFlipMask0 + zAFlipMask0 + a0
+		if a7 > 0 {
+			x7 := x0 - float32(xNMask|1)
+			y7 := y0
+			z7 := z0 - float32(zNMask|1)
+			value += (a7 * a7) * (a7 * a7) * os2Grad3(os2sGradients3D, seed,
+				xrbp+(^xNMask64&os2PrimeX), yrbp+(yNMask64&os2PrimeY), zrbp+(^zNMask64&os2PrimeZ), x7, y7, z7)
+		}
+
+		a8 := yAFlipMask1 + a1
+		if a8 > 0 {
+			x8 := x1
+			y8 := float32(yNMask|1) + y1
+			z8 := z1
+			value += (a8 * a8) * (a8 * a8) * os2Grad3(os2sGradients3D, seed2,
+				xrbp+os2PrimeX, yrbp+(yNMask64&os2PrimeY2), zrbp+os2PrimeZ, x8, y8, z8)
+			skip9 = true
+		}
+	}
+
+	skipD := false
+	aA := zAFlipMask0 + a0
+	if aA > 0 {
+		xA := x0
+		yA := y0
+		zA := z0 - float32(zNMask|1)
+		value += (aA * aA) * (aA * aA) * os2Grad3(os2sGradients3D, seed,
+			xrbp+(xNMask64&os2PrimeX), yrbp+(yNMask64&os2PrimeY), zrbp+(^zNMask64&os2PrimeZ), xA, yA, zA)
+	} else {
+		aB := xAFlipMask0 + yAFlipMask0 + a0
+		if aB > 0 {
+			xB := x0 - float32(xNMask|1)
+			yB := y0 - float32(yNMask|1)
+			zB := z0
+			value += (aB * aB) * (aB * aB) * os2Grad3(os2sGradients3D, seed,
+				xrbp+(^xNMask64&os2PrimeX), yrbp+(^yNMask64&os2PrimeY), zrbp+(zNMask64&os2PrimeZ), xB, yB, zB)
+		}
+
+		aC := zAFlipMask1 + a1
+		if aC > 0 {
+			xC := x1
+			yC := y1
+			zC := float32(zNMask|1) + z1
+			value += (aC * aC) * (aC * aC) * os2Grad3(os2sGradients3D, seed2,
+				xrbp+os2PrimeX, yrbp+os2PrimeY, zrbp+(zNMask64&os2PrimeZ2), xC, yC, zC)
+			skipD = true
+		}
+	}
+
+	if !skip5 {
+		a5 := yAFlipMask1 + zAFlipMask1 + a1
+		if a5 > 0 {
+			x5 := x1
+			y5 := float32(yNMask|1) + y1
+			z5 := float32(zNMask|1) + z1
+			value += (a5 * a5) * (a5 * a5) * os2Grad3(os2sGradients3D, seed2,
+				xrbp+os2PrimeX, yrbp+(yNMask64&os2PrimeY2), zrbp+(zNMask64&os2PrimeZ2), x5, y5, z5)
+		}
+	}
+
+	if !skip9 {
+		a9 := xAFlipMask1 + zAFlipMask1 + a1
+		if a9 > 0 {
+			x9 := float32(xNMask|1) + x1
+			y9 := y1
+			z9 := float32(zNMask|1) + z1
+			value += (a9 * a9) * (a9 * a9) * os2Grad3(os2sGradients3D, seed2,
+				xrbp+(xNMask64&os2PrimeX2), yrbp+os2PrimeY, zrbp+(zNMask64&os2PrimeZ2), x9, y9, z9)
+		}
+	}
+
+	if !skipD {
+		aD := xAFlipMask1 + yAFlipMask1 + a1
+		if aD > 0 {
+			xD := float32(xNMask|1) + x1
+			yD := float32(yNMask|1) + y1
+			zD := z1
+			value += (aD * aD) * (aD * aD) * os2Grad3(os2sGradients3D, seed2,
+				xrbp+(xNMask64&os2PrimeX2), yrbp+(yNMask64&os2PrimeY2), zrbp+os2PrimeZ, xD, yD, zD)
+		}
+	}
+
+	return value
+}
+
+func (n *openSimplex2S) noise4(x, y, z, w float64) float32 {
+	// Get points for A4* lattice.
+	s := float64(os2sSkew4D) * (x + y + z + w)
+	xs, ys, zs, ws := x+s, y+s, z+s, w+s
+
+	// Get base points and offsets.
+	xsb, ysb, zsb, wsb := os2FastFloor(xs), os2FastFloor(ys), os2FastFloor(zs), os2FastFloor(ws)
+	xsi := float32(xs - float64(xsb))
+	ysi := float32(ys - float64(ysb))
+	zsi := float32(zs - float64(zsb))
+	wsi := float32(ws - float64(wsb))
+
+	// Unskewed offsets.
+	ssi := (xsi + ysi + zsi + wsi) * os2sUnskew4D
+	xi, yi, zi, wi := xsi+ssi, ysi+ssi, zsi+ssi, wsi+ssi
+
+	// Prime pre-multiplication for hash.
+	xsvp, ysvp := int64(xsb)*os2PrimeX, int64(ysb)*os2PrimeY
+	zsvp, wsvp := int64(zsb)*os2PrimeZ, int64(wsb)*os2PrimeW
+
+	// Index into the lookup table by the quarter of the cell we're in on each axis.
+	index := (os2FastFloor(xs*4) & 3) |
+		((os2FastFloor(ys*4) & 3) << 2) |
+		((os2FastFloor(zs*4) & 3) << 4) |
+		((os2FastFloor(ws*4) & 3) << 6)
+
+	// Point contributions.
+	var value float32
+	for _, c := range os2sLookup4D[index] {
+		dx, dy, dz, dw := xi+c.dx, yi+c.dy, zi+c.dz, wi+c.dw
+		a := (dx*dx + dy*dy) + (dz*dz + dw*dw)
+		if a < os2sRSquared4D {
+			a -= os2sRSquared4D
+			a *= a
+			value += a * a * os2Grad4(os2sGradients4D, n.seed,
+				xsvp+c.xsvp, ysvp+c.ysvp, zsvp+c.zsvp, wsvp+c.wsvp, dx, dy, dz, dw)
+		}
+	}
+
+	return value
+}
+
+// os2sVertex4D is a lattice vertex relative to the base of a cell, given as its
+// pre-multiplied hash primes and its negated unskewed position.
+type os2sVertex4D struct {
+	xsvp, ysvp, zsvp, wsvp int64
+	dx, dy, dz, dw         float32
+}
+
+// os2sLookup4D lists, for each of the 4^4 sub-cells of a skewed lattice cell,
+// every vertex that can lie within the kernel radius of a point in it. Like the
+// reference, it lists them in the order of their vertex codes, which hold the
+// offset of the vertex from the base of the cell, -1 to 2, in two bits per axis
+// starting with x in the lowest bits, so the contributions are summed in the
+// same order.
+var os2sLookup4D = func() (lookup [256][]os2sVertex4D) {
+	const (
+		// The unskew transform never stretches distances, so a vertex further
+		// than the kernel radius plus half the sub-cell diagonal from the
+		// centre of a sub-cell cannot reach any point in it.
+		reach = 0.894427190999916 + 0.25 // sqrt(4/5) + sqrt(4*0.125^2)
+	)
+
+	for index := range lookup {
+		var centre [4]float64
+		for axis := range centre {
+			centre[axis] = float64((index>>(2*axis))&3)/4 + 0.125
+		}
+		csc := (centre[0] + centre[1] + centre[2] + centre[3]) * float64(os2sUnskew4D)
+
+		for code := 0; code < 256; code++ {
+			xsv, ysv := int64(code&3)-1, int64((code>>2)&3)-1
+			zsv, wsv := int64((code>>4)&3)-1, int64((code>>6)&3)-1
+
+			ssv := float64(xsv+ysv+zsv+wsv) * float64(os2sUnskew4D)
+			dx, dy := centre[0]+csc-float64(xsv)-ssv, centre[1]+csc-float64(ysv)-ssv
+			dz, dw := centre[2]+csc-float64(zsv)-ssv, centre[3]+csc-float64(wsv)-ssv
+			if dx*dx+dy*dy+dz*dz+dw*dw >= reach*reach {
+				continue
+			}
+
+			// The reference computes the offsets in float32.
+			ssv32 := float32(xsv+ysv+zsv+wsv) * os2sUnskew4D
+			lookup[index] = append(lookup[index], os2sVertex4D{
+				xsvp: xsv * os2PrimeX, ysvp: ysv * os2PrimeY,
+				zsvp: zsv * os2PrimeZ, wsvp: wsv * os2PrimeW,
+				dx: float32(-xsv) - ssv32, dy: float32(-ysv) - ssv32,
+				dz: float32(-zsv) - ssv32, dw: float32(-wsv) - ssv32,
+			})
+		}
+	}
+
+	return lookup
+}()
+
+var (
+	os2sGradients2D = os2Tile(os2GradientSet2D, os2Grads2D*2, os2sNormalizer2D)
+	os2sGradients3D = os2Tile(os2GradientSet3D, os2Grads3D*4, os2sNormalizer3D)
+	os2sGradients4D = os2Tile(os2GradientSet4D, os2Grads4D*4, os2sNormalizer4D)
+)
@@ -1,31 +1,85 @@
 package opensimplex
 
-import "math"
-
 // Vanilla opensimplex implementation, matching Kurt Spencer's Java
 // reference implementation as exactly as possible.
+//
+// Products that feed into a sum are wrapped in float64() conversions. They do
+// not change the value, but they stop the compiler from fusing the product and
+// the sum into an FMA instruction on the architectures that have one, which
+// would skip a rounding step and change the output. See NewDeterministic.
 
 // A seeded Noise instance. Reusing a Noise instance (rather than recreating it
 // from a known seed) will save some calculation time.
 type noise struct {
+	seed int64
+
 	perm            [256]int16
 	permGradIndex3D [256]int16
+
+	// hash selects how lattice vertices are hashed, see NewWithHash.
+	hash Hash
+
+	// lattice replaces the lookups in the tables above for the other hashes
+	// and for NewPeriodic. It is nil for HashPerm256, which keeps the
+	// lookups inline; otherwise the eval functions dispatch to their copies
+	// in opensimplex_lattice.go.
+	lattice lattice
 }
 
 // Eval2 returns a random noise value in two dimensions. Repeated calls with the same
 // x/y inputs will have the same output.
 func (s *noise) Eval2(x, y float64) float64 {
+	return s.eval2(x, y, nil)
+}
+
+// Eval2Deriv returns a random noise value in two dimensions along with its
+// partial derivatives with respect to x and y.
+func (s *noise) Eval2Deriv(x, y float64) (v, dx, dy float64) {
+	var d [2]float64
+	v = s.eval2(x, y, &d)
+	return v, d[0] / normConstant2D, d[1] / normConstant2D
+}
+
+// Eval3 returns a random noise value in three dimensions.
+func (s *noise) Eval3(x, y, z float64) float64 {
+	return s.eval3(x, y, z, nil)
+}
+
+// Eval3Deriv returns a random noise value in three dimensions along with its
+// partial derivatives with respect to x, y and z.
+func (s *noise) Eval3Deriv(x, y, z float64) (v, dx, dy, dz float64) {
+	var d [3]float64
+	v = s.eval3(x, y, z, &d)
+	return v, d[0] / normConstant3D, d[1] / normConstant3D, d[2] / normConstant3D
+}
+
+// Eval4 returns a random noise value in four dimensions.
+func (s *noise) Eval4(x, y, z, w float64) float64 {
+	return s.eval4(x, y, z, w, nil)
+}
+
+// Eval4Deriv returns a random noise value in four dimensions along with its
+// partial derivatives with respect to x, y, z and w.
+func (s *noise) Eval4Deriv(x, y, z, w float64) (v, dx, dy, dz, dw float64) {
+	var d [4]float64
+	v = s.eval4(x, y, z, w, &d)
+	return v, d[0] / normConstant4D, d[1] / normConstant4D, d[2] / normConstant4D, d[3] / normConstant4D
+}
+
+// eval2 computes the 2D noise value. If d is not nil, the unnormalized partial
+// derivatives are accumulated into it.
+func (s *noise) eval2(x, y float64, d *[2]float64) float64 {
 	// Place input coordinates onto grid.
-	stretchOffset := (x + y) * stretchConstant2D
+	stretchOffset := float64((x + y) * stretchConstant2D)
 	xs := x + stretchOffset
 	ys := y + stretchOffset
 
 	// Floor to get grid coordinates of rhombus (stretched square) super-cell origin.
-	xsb := int32(math.Floor(xs))
-	ysb := int32(math.Floor(ys))
+	xsb := floorLattice(xs)
+	ysb := floorLattice(ys)
 
 	// Skew out to get actual coordinates of rhombus origin. We'll need these later.
-	squishOffset := float64(xsb+ysb) * squishConstant2D
+	squishOffset := float64(float64(xsb+ysb) * squishConstant2D)
 	xb := float64(xsb) + squishOffset
 	yb := float64(ysb) + squishOffset
 
@@ -33,13 +87,24 @@ func (s *noise) Eval2(x, y float64) float64 {
 	xins := xs - float64(xsb)
 	yins := ys - float64(ysb)
 
-	// Sum those together to get a value that determines which region we're in.
-	inSum := xins + yins
-
 	// Positions relative to origin point.
 	dx0 := x - xb
 	dy0 := y - yb
 
+	return s.eval2Cell(xsb, ysb, xins, yins, dx0, dy0, d)
+}
+
+// eval2Cell computes the 2D noise value of a point from the lattice coordinates
+// of its super-cell origin, its coordinates relative to that origin on the
+// lattice (xins, yins) and in input space (dx0, dy0).
+func (s *noise) eval2Cell(xsb, ysb int32, xins, yins, dx0, dy0 float64, d *[2]float64) float64 {
+	if s.lattice != nil {
+		return s.latticeEval2Cell(xsb, ysb, xins, yins, dx0, dy0, d)
+	}
+
+	// Sum those together to get a value that determines which region we're in.
+	inSum := xins + yins
+
 	// We'll be defining these inside the next block and using them afterwards.
 	var dxExt, dyExt float64
 	var xsvExt, ysvExt int32
@@ -49,19 +114,17 @@ func (s *noise) Eval2(x, y float64) float64 {
 	// Contribution (1,0)
 	dx1 := dx0 - 1 - squishConstant2D
 	dy1 := dy0 - 0 - squishConstant2D
-	attn1 := 2 - dx1*dx1 - dy1*dy1
+	attn1 := 2 - float64(dx1*dx1) - float64(dy1*dy1)
 	if attn1 > 0 {
-		attn1 *= attn1
-		value += attn1 * attn1 * s.extrapolate2(xsb+1, ysb+0, dx1, dy1)
+		value += s.contrib2(d, attn1, xsb+1, ysb+0, dx1, dy1)
 	}
 
 	// Contribution (0,1)
 	dx2 := dx0 - 0 - squishConstant2D
 	dy2 := dy0 - 1 - squishConstant2D
-	attn2 := 2 - dx2*dx2 - dy2*dy2
+	attn2 := 2 - float64(dx2*dx2) - float64(dy2*dy2)
 	if attn2 > 0 {
-		attn2 *= attn2
-		value += attn2 * attn2 * s.extrapolate2(xsb+0, ysb+1, dx2, dy2)
+		value += s.contrib2(d, attn2, xsb+0, ysb+1, dx2, dy2)
 	}
 
 	if inSum <= 1 { // We're inside the triangle (2-Simplex) at (0,0)
@@ -111,38 +174,36 @@ func (s *noise) Eval2(x, y float64) float64 {
 	}
 
 	// Contribution (0,0) or (1,1)
-	attn0 := 2 - dx0*dx0 - dy0*dy0
+	attn0 := 2 - float64(dx0*dx0) - float64(dy0*dy0)
 	if attn0 > 0 {
-		attn0 *= attn0
-		value += attn0 * attn0 * s.extrapolate2(xsb, ysb, dx0, dy0)
+		value += s.contrib2(d, attn0, xsb, ysb, dx0, dy0)
 	}
 
 	// Extra Vertex
-	attnExt := 2 - dxExt*dxExt - dyExt*dyExt
+	attnExt := 2 - float64(dxExt*dxExt) - float64(dyExt*dyExt)
 	if attnExt > 0 {
-		attnExt *= attnExt
-		value += attnExt * attnExt * s.extrapolate2(xsvExt, ysvExt, dxExt, dyExt)
+		value += s.contrib2(d, attnExt, xsvExt, ysvExt, dxExt, dyExt)
 	}
 
 	return value / normConstant2D
 }
 
-// Eval3 returns a random noise value in three dimensions.
-//gocyclo:ignore
-func (s *noise) Eval3(x, y, z float64) float64 {
+// eval3 computes the 3D noise value. If d is not nil, the unnormalized partial
+// derivatives are accumulated into it.
+func (s *noise) eval3(x, y, z float64, d *[3]float64) float64 {
 	// Place input coordinates on simplectic honeycomb.
-	stretchOffset := (x + y + z) * stretchConstant3D
+	stretchOffset := float64((x + y + z) * stretchConstant3D)
 	xs := x + stretchOffset
 	ys := y + stretchOffset
 	zs := z + stretchOffset
 
 	// Floor to get simplectic honeycomb coordinates of rhombohedron (stretched cube) super-cell origin.
-	xsb := int32(math.Floor(xs))
-	ysb := int32(math.Floor(ys))
-	zsb := int32(math.Floor(zs))
+	xsb := floorLattice(xs)
+	ysb := floorLattice(ys)
+	zsb := floorLattice(zs)
 
 	// Skew out to get actual coordinates of rhombohedron origin. We'll need these later.
-	squishOffset := float64(xsb+ysb+zsb) * squishConstant3D
+	squishOffset := float64(float64(xsb+ysb+zsb) * squishConstant3D)
 	xb := float64(xsb) + squishOffset
 	yb := float64(ysb) + squishOffset
 	zb := float64(zsb) + squishOffset
@@ -152,14 +213,27 @@ func (s *noise) Eval3(x, y, z float64) float64 {
 	yins := ys - float64(ysb)
 	zins := zs - float64(zsb)
 
-	// Sum those together to get a value that determines which region we're in.
-	inSum := xins + yins + zins
-
 	// Positions relative to origin point.
 	dx0 := x - xb
 	dy0 := y - yb
 	dz0 := z - zb
 
+	return s.eval3Cell(xsb, ysb, zsb, xins, yins, zins, dx0, dy0, dz0, d)
+}
+
+// eval3Cell computes the 3D noise value of a point from the lattice coordinates
+// of its super-cell origin, its coordinates relative to that origin on the
+// lattice (xins, yins, zins) and in input space (dx0, dy0, dz0).
+//
+//gocyclo:ignore
+func (s *noise) eval3Cell(xsb, ysb, zsb int32, xins, yins, zins, dx0, dy0, dz0 float64, d *[3]float64) float64 {
+	if s.lattice != nil {
+		return s.latticeEval3Cell(xsb, ysb, zsb, xins, yins, zins, dx0, dy0, dz0, d)
+	}
+
+	// Sum those together to get a value that determines which region we're in.
+	inSum := xins + yins + zins
+
 	// We'll be defining these inside the next block and using them afterwards.
 	var dxExt0, dyExt0, dzExt0 float64
 	var dxExt1, dyExt1, dzExt1 float64
@@ -276,40 +350,36 @@ func (s *noise) Eval3(x, y, z float64) float64 {
 		}
 
 		// Contribution (0,0,0)
-		attn0 := 2 - dx0*dx0 - dy0*dy0 - dz0*dz0
+		attn0 := 2 - float64(dx0*dx0) - float64(dy0*dy0) - float64(dz0*dz0)
 		if attn0 > 0 {
-			attn0 *= attn0
-			value += attn0 * attn0 * s.extrapolate3(xsb+0, ysb+0, zsb+0, dx0, dy0, dz0)
+			value += s.contrib3(d, attn0, xsb+0, ysb+0, zsb+0, dx0, dy0, dz0)
 		}
 
 		// Contribution (1,0,0)
 		dx1 := dx0 - 1 - squishConstant3D
 		dy1 := dy0 - 0 - squishConstant3D
 		dz1 := dz0 - 0 - squishConstant3D
-		attn1 := 2 - dx1*dx1 - dy1*dy1 - dz1*dz1
+		attn1 := 2 - float64(dx1*dx1) - float64(dy1*dy1) - float64(dz1*dz1)
 		if attn1 > 0 {
-			attn1 *= attn1
-			value += attn1 * attn1 * s.extrapolate3(xsb+1, ysb+0, zsb+0, dx1, dy1, dz1)
+			value += s.contrib3(d, attn1, xsb+1, ysb+0, zsb+0, dx1, dy1, dz1)
 		}
 
 		// Contribution (0,1,0)
 		dx2 := dx0 - 0 - squishConstant3D
 		dy2 := dy0 - 1 - squishConstant3D
 		dz2 := dz1
-		attn2 := 2 - dx2*dx2 - dy2*dy2 - dz2*dz2
+		attn2 := 2 - float64(dx2*dx2) - float64(dy2*dy2) - float64(dz2*dz2)
 		if attn2 > 0 {
-			attn2 *= attn2
-			value += attn2 * attn2 * s.extrapolate3(xsb+0, ysb+1, zsb+0, dx2, dy2, dz2)
+			value += s.contrib3(d, attn2, xsb+0, ysb+1, zsb+0, dx2, dy2, dz2)
 		}
 
 		// Contribution (0,0,1)
 		dx3 := dx2
 		dy3 := dy1
 		dz3 := dz0 - 1 - squishConstant3D
-		attn3 := 2 - dx3*dx3 - dy3*dy3 - dz3*dz3
+		attn3 := 2 - float64(dx3*dx3) - float64(dy3*dy3) - float64(dz3*dz3)
 		if attn3 > 0 {
-			attn3 *= attn3
-			value += attn3 * attn3 * s.extrapolate3(xsb+0, ysb+0, zsb+1, dx3, dy3, dz3)
+			value += s.contrib3(d, attn3, xsb+0, ysb+0, zsb+1, dx3, dy3, dz3)
 		}
 	} else if inSum >= 2 { // We're inside the tetrahedron (3-Simplex) at (1,1,1)
 
@@ -423,40 +493,36 @@ func (s *noise) Eval3(x, y, z float64) float64 {
 		dx3 := dx0 - 1 - 2*squishConstant3D
 		dy3 := dy0 - 1 - 2*squishConstant3D
 		dz3 := dz0 - 0 - 2*squishConstant3D
-		attn3 := 2 - dx3*dx3 - dy3*dy3 - dz3*dz3
+		attn3 := 2 - float64(dx3*dx3) - float64(dy3*dy3) - float64(dz3*dz3)
 		if attn3 > 0 {
-			attn3 *= attn3
-			value += attn3 * attn3 * s.extrapolate3(xsb+1, ysb+1, zsb+0, dx3, dy3, dz3)
+			value += s.contrib3(d, attn3, xsb+1, ysb+1, zsb+0, dx3, dy3, dz3)
 		}
 
 		// Contribution (1,0,1)
 		dx2 := dx3
 		dy2 := dy0 - 0 - 2*squishConstant3D
 		dz2 := dz0 - 1 - 2*squishConstant3D
-		attn2 := 2 - dx2*dx2 - dy2*dy2 - dz2*dz2
+		attn2 := 2 - float64(dx2*dx2) - float64(dy2*dy2) - float64(dz2*dz2)
 		if attn2 > 0 {
-			attn2 *= attn2
-			value += attn2 * attn2 * s.extrapolate3(xsb+1, ysb+0, zsb+1, dx2, dy2, dz2)
+			value += s.contrib3(d, attn2, xsb+1, ysb+0, zsb+1, dx2, dy2, dz2)
 		}
 
 		// Contribution (0,1,1)
 		dx1 := dx0 - 0 - 2*squishConstant3D
 		dy1 := dy3
 		dz1 := dz2
-		attn1 := 2 - dx1*dx1 - dy1*dy1 - dz1*dz1
+		attn1 := 2 - float64(dx1*dx1) - float64(dy1*dy1) - float64(dz1*dz1)
 		if attn1 > 0 {
-			attn1 *= attn1
-			value += attn1 * attn1 * s.extrapolate3(xsb+0, ysb+1, zsb+1, dx1, dy1, dz1)
+			value += s.contrib3(d, attn1, xsb+0, ysb+1, zsb+1, dx1, dy1, dz1)
 		}
 
 		// Contribution (1,1,1)
 		dx0 = dx0 - 1 - 3*squishConstant3D
 		dy0 = dy0 - 1 - 3*squishConstant3D
 		dz0 = dz0 - 1 - 3*squishConstant3D
-		attn0 := 2 - dx0*dx0 - dy0*dy0 - dz0*dz0
+		attn0 := 2 - float64(dx0*dx0) - float64(dy0*dy0) - float64(dz0*dz0)
 		if attn0 > 0 {
-			attn0 *= attn0
-			value += attn0 * attn0 * s.extrapolate3(xsb+1, ysb+1, zsb+1, dx0, dy0, dz0)
+			value += s.contrib3(d, attn0, xsb+1, ysb+1, zsb+1, dx0, dy0, dz0)
 		}
 	} else { // We're inside the octahedron (Rectified 3-Simplex) in between.
 		var aScore, bScore float64
@@ -637,98 +703,90 @@ func (s *noise) Eval3(x, y, z float64) float64 {
 		dx1 := dx0 - 1 - squishConstant3D
 		dy1 := dy0 - 0 - squishConstant3D
 		dz1 := dz0 - 0 - squishConstant3D
-		attn1 := 2 - dx1*dx1 - dy1*dy1 - dz1*dz1
+		attn1 := 2 - float64(dx1*dx1) - float64(dy1*dy1) - float64(dz1*dz1)
 		if attn1 > 0 {
-			attn1 *= attn1
-			value += attn1 * attn1 * s.extrapolate3(xsb+1, ysb+0, zsb+0, dx1, dy1, dz1)
+			value += s.contrib3(d, attn1, xsb+1, ysb+0, zsb+0, dx1, dy1, dz1)
 		}
 
 		// Contribution (0,1,0)
 		dx2 := dx0 - 0 - squishConstant3D
 		dy2 := dy0 - 1 - squishConstant3D
 		dz2 := dz1
-		attn2 := 2 - dx2*dx2 - dy2*dy2 - dz2*dz2
+		attn2 := 2 - float64(dx2*dx2) - float64(dy2*dy2) - float64(dz2*dz2)
 		if attn2 > 0 {
-			attn2 *= attn2
-			value += attn2 * attn2 * s.extrapolate3(xsb+0, ysb+1, zsb+0, dx2, dy2, dz2)
+			value += s.contrib3(d, attn2, xsb+0, ysb+1, zsb+0, dx2, dy2, dz2)
 		}
 
 		// Contribution (0,0,1)
 		dx3 := dx2
 		dy3 := dy1
 		dz3 := dz0 - 1 - squishConstant3D
-		attn3 := 2 - dx3*dx3 - dy3*dy3 - dz3*dz3
+		attn3 := 2 - float64(dx3*dx3) - float64(dy3*dy3) - float64(dz3*dz3)
 		if attn3 > 0 {
-			attn3 *= attn3
-			value += attn3 * attn3 * s.extrapolate3(xsb+0, ysb+0, zsb+1, dx3, dy3, dz3)
+			value += s.contrib3(d, attn3, xsb+0, ysb+0, zsb+1, dx3, dy3, dz3)
 		}
 
 		// Contribution (1,1,0)
 		dx4 := dx0 - 1 - 2*squishConstant3D
 		dy4 := dy0 - 1 - 2*squishConstant3D
 		dz4 := dz0 - 0 - 2*squishConstant3D
-		attn4 := 2 - dx4*dx4 - dy4*dy4 - dz4*dz4
+		attn4 := 2 - float64(dx4*dx4) - float64(dy4*dy4) - float64(dz4*dz4)
 		if attn4 > 0 {
-			attn4 *= attn4
-			value += attn4 * attn4 * s.extrapolate3(xsb+1, ysb+1, zsb+0, dx4, dy4, dz4)
+			value += s.contrib3(d, attn4, xsb+1, ysb+1, zsb+0, dx4, dy4, dz4)
 		}
 
 		// Contribution (1,0,1)
 		dx5 := dx4
 		dy5 := dy0 - 0 - 2*squishConstant3D
 		dz5 := dz0 - 1 - 2*squishConstant3D
-		attn5 := 2 - dx5*dx5 - dy5*dy5 - dz5*dz5
+		attn5 := 2 - float64(dx5*dx5) - float64(dy5*dy5) - float64(dz5*dz5)
 		if attn5 > 0 {
-			attn5 *= attn5
-			value += attn5 * attn5 * s.extrapolate3(xsb+1, ysb+0, zsb+1, dx5, dy5, dz5)
+			value += s.contrib3(d, attn5, xsb+1, ysb+0, zsb+1, dx5, dy5, dz5)
 		}
 
 		// Contribution (0,1,1)
 		dx6 := dx0 - 0 - 2*squishConstant3D
 		dy6 := dy4
 		dz6 := dz5
-		attn6 := 2 - dx6*dx6 - dy6*dy6 - dz6*dz6
+		attn6 := 2 - float64(dx6*dx6) - float64(dy6*dy6) - float64(dz6*dz6)
 		if attn6 > 0 {
-			attn6 *= attn6
-			value += attn6 * attn6 * s.extrapolate3(xsb+0, ysb+1, zsb+1, dx6, dy6, dz6)
+			value += s.contrib3(d, attn6, xsb+0, ysb+1, zsb+1, dx6, dy6, dz6)
 		}
 	}
 
 	// First extra vertex
-	attnExt0 := 2 - dxExt0*dxExt0 - dyExt0*dyExt0 - dzExt0*dzExt0
+	attnExt0 := 2 - float64(dxExt0*dxExt0) - float64(dyExt0*dyExt0) - float64(dzExt0*dzExt0)
 	if attnExt0 > 0 {
-		attnExt0 *= attnExt0
-		value += attnExt0 * attnExt0 * s.extrapolate3(xsvExt0, ysvExt0, zsvExt0, dxExt0, dyExt0, dzExt0)
+		value += s.contrib3(d, attnExt0, xsvExt0, ysvExt0, zsvExt0, dxExt0, dyExt0, dzExt0)
 	}
 
 	// Second extra vertex
-	attnExt1 := 2 - dxExt1*dxExt1 - dyExt1*dyExt1 - dzExt1*dzExt1
+	attnExt1 := 2 - float64(dxExt1*dxExt1) - float64(dyExt1*dyExt1) - float64(dzExt1*dzExt1)
 	if attnExt1 > 0 {
-		attnExt1 *= attnExt1
-		value += attnExt1 * attnExt1 * s.extrapolate3(xsvExt1, ysvExt1, zsvExt1, dxExt1, dyExt1, dzExt1)
+		value += s.contrib3(d, attnExt1, xsvExt1, ysvExt1, zsvExt1, dxExt1, dyExt1, dzExt1)
 	}
 
 	return value / normConstant3D
 }
 
-// Eval4 returns a random noise value in four dimensions.
-//gocyclo:ignore
-func (s *noise) Eval4(x, y, z, w float64) float64 {
+// eval4 computes the 4D noise value. If d is not nil, the unnormalized partial
+// derivatives are accumulated into it.
+func (s *noise) eval4(x, y, z, w float64, d *[4]float64) float64 {
 	// Place input coordinates on simplectic honeycomb.
-	stretchOffset := (x + y + z + w) * stretchConstant4D
+	stretchOffset := float64((x + y + z + w) * stretchConstant4D)
 	xs := x + stretchOffset
 	ys := y + stretchOffset
 	zs := z + stretchOffset
 	ws := w + stretchOffset
 
 	// Floor to get simplectic honeycomb coordinates of rhombo-hypercube super-cell origin.
-	xsb := int32(math.Floor(xs))
-	ysb := int32(math.Floor(ys))
-	zsb := int32(math.Floor(zs))
-	wsb := int32(math.Floor(ws))
+	xsb := floorLattice(xs)
+	ysb := floorLattice(ys)
+	zsb := floorLattice(zs)
+	wsb := floorLattice(ws)
 
 	// Skew out to get actual coordinates of stretched rhombo-hypercube origin. We'll need these later.
-	squishOffset := float64(xsb+ysb+zsb+wsb) * squishConstant4D
+	squishOffset := float64(float64(xsb+ysb+zsb+wsb) * squishConstant4D)
 	xb := float64(xsb) + squishOffset
 	yb := float64(ysb) + squishOffset
 	zb := float64(zsb) + squishOffset
@@ -740,15 +798,28 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 	zins := zs - float64(zsb)
 	wins := ws - float64(wsb)
 
-	// Sum those together to get a value that determines which region we're in.
-	inSum := xins + yins + zins + wins
-
 	// Positions relative to origin point.
 	dx0 := x - xb
 	dy0 := y - yb
 	dz0 := z - zb
 	dw0 := w - wb
 
+	return s.eval4Cell(xsb, ysb, zsb, wsb, xins, yins, zins, wins, dx0, dy0, dz0, dw0, d)
+}
+
+// eval4Cell computes the 4D noise value of a point from the lattice coordinates
+// of its super-cell origin, its coordinates relative to that origin on the
+// lattice (xins, yins, zins, wins) and in input space (dx0, dy0, dz0, dw0).
+//
+//gocyclo:ignore
+func (s *noise) eval4Cell(xsb, ysb, zsb, wsb int32, xins, yins, zins, wins, dx0, dy0, dz0, dw0 float64, d *[4]float64) float64 {
+	if s.lattice != nil {
+		return s.latticeEval4Cell(xsb, ysb, zsb, wsb, xins, yins, zins, wins, dx0, dy0, dz0, dw0, d)
+	}
+
+	// Sum those together to get a value that determines which region we're in.
+	inSum := xins + yins + zins + wins
+
 	// We'll be defining these inside the next block and using them afterwards.
 	var dxExt0, dyExt0, dzExt0, dwExt0 float64
 	var dxExt1, dyExt1, dzExt1, dwExt1 float64
@@ -955,10 +1026,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		}
 
 		// Contribution (0,0,0,0)
-		attn0 := 2 - dx0*dx0 - dy0*dy0 - dz0*dz0 - dw0*dw0
+		attn0 := 2 - float64(dx0*dx0) - float64(dy0*dy0) - float64(dz0*dz0) - float64(dw0*dw0)
 		if attn0 > 0 {
-			attn0 *= attn0
-			value += attn0 * attn0 * s.extrapolate4(xsb+0, ysb+0, zsb+0, wsb+0, dx0, dy0, dz0, dw0)
+			value += s.contrib4(d, attn0, xsb+0, ysb+0, zsb+0, wsb+0, dx0, dy0, dz0, dw0)
 		}
 
 		// Contribution (1,0,0,0)
@@ -966,10 +1036,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy1 := dy0 - 0 - squishConstant4D
 		dz1 := dz0 - 0 - squishConstant4D
 		dw1 := dw0 - 0 - squishConstant4D
-		attn1 := 2 - dx1*dx1 - dy1*dy1 - dz1*dz1 - dw1*dw1
+		attn1 := 2 - float64(dx1*dx1) - float64(dy1*dy1) - float64(dz1*dz1) - float64(dw1*dw1)
 		if attn1 > 0 {
-			attn1 *= attn1
-			value += attn1 * attn1 * s.extrapolate4(xsb+1, ysb+0, zsb+0, wsb+0, dx1, dy1, dz1, dw1)
+			value += s.contrib4(d, attn1, xsb+1, ysb+0, zsb+0, wsb+0, dx1, dy1, dz1, dw1)
 		}
 
 		// Contribution (0,1,0,0)
@@ -977,10 +1046,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy2 := dy0 - 1 - squishConstant4D
 		dz2 := dz1
 		dw2 := dw1
-		attn2 := 2 - dx2*dx2 - dy2*dy2 - dz2*dz2 - dw2*dw2
+		attn2 := 2 - float64(dx2*dx2) - float64(dy2*dy2) - float64(dz2*dz2) - float64(dw2*dw2)
 		if attn2 > 0 {
-			attn2 *= attn2
-			value += attn2 * attn2 * s.extrapolate4(xsb+0, ysb+1, zsb+0, wsb+0, dx2, dy2, dz2, dw2)
+			value += s.contrib4(d, attn2, xsb+0, ysb+1, zsb+0, wsb+0, dx2, dy2, dz2, dw2)
 		}
 
 		// Contribution (0,0,1,0)
@@ -988,10 +1056,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy3 := dy1
 		dz3 := dz0 - 1 - squishConstant4D
 		dw3 := dw1
-		attn3 := 2 - dx3*dx3 - dy3*dy3 - dz3*dz3 - dw3*dw3
+		attn3 := 2 - float64(dx3*dx3) - float64(dy3*dy3) - float64(dz3*dz3) - float64(dw3*dw3)
 		if attn3 > 0 {
-			attn3 *= attn3
-			value += attn3 * attn3 * s.extrapolate4(xsb+0, ysb+0, zsb+1, wsb+0, dx3, dy3, dz3, dw3)
+			value += s.contrib4(d, attn3, xsb+0, ysb+0, zsb+1, wsb+0, dx3, dy3, dz3, dw3)
 		}
 
 		// Contribution (0,0,0,1)
@@ -999,10 +1066,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy4 := dy1
 		dz4 := dz1
 		dw4 := dw0 - 1 - squishConstant4D
-		attn4 := 2 - dx4*dx4 - dy4*dy4 - dz4*dz4 - dw4*dw4
+		attn4 := 2 - float64(dx4*dx4) - float64(dy4*dy4) - float64(dz4*dz4) - float64(dw4*dw4)
 		if attn4 > 0 {
-			attn4 *= attn4
-			value += attn4 * attn4 * s.extrapolate4(xsb+0, ysb+0, zsb+0, wsb+1, dx4, dy4, dz4, dw4)
+			value += s.contrib4(d, attn4, xsb+0, ysb+0, zsb+0, wsb+1, dx4, dy4, dz4, dw4)
 		}
 	} else if inSum >= 3 { // We're inside the pentachoron (4-Simplex) at (1,1,1,1)
 		// Determine which two of (1,1,1,0), (1,1,0,1), (1,0,1,1), (0,1,1,1) are closest.
@@ -1206,10 +1272,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy4 := dy0 - 1 - 3*squishConstant4D
 		dz4 := dz0 - 1 - 3*squishConstant4D
 		dw4 := dw0 - 3*squishConstant4D
-		attn4 := 2 - dx4*dx4 - dy4*dy4 - dz4*dz4 - dw4*dw4
+		attn4 := 2 - float64(dx4*dx4) - float64(dy4*dy4) - float64(dz4*dz4) - float64(dw4*dw4)
 		if attn4 > 0 {
-			attn4 *= attn4
-			value += attn4 * attn4 * s.extrapolate4(xsb+1, ysb+1, zsb+1, wsb+0, dx4, dy4, dz4, dw4)
+			value += s.contrib4(d, attn4, xsb+1, ysb+1, zsb+1, wsb+0, dx4, dy4, dz4, dw4)
 		}
 
 		// Contribution (1,1,0,1)
@@ -1217,10 +1282,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy3 := dy4
 		dz3 := dz0 - 3*squishConstant4D
 		dw3 := dw0 - 1 - 3*squishConstant4D
-		attn3 := 2 - dx3*dx3 - dy3*dy3 - dz3*dz3 - dw3*dw3
+		attn3 := 2 - float64(dx3*dx3) - float64(dy3*dy3) - float64(dz3*dz3) - float64(dw3*dw3)
 		if attn3 > 0 {
-			attn3 *= attn3
-			value += attn3 * attn3 * s.extrapolate4(xsb+1, ysb+1, zsb+0, wsb+1, dx3, dy3, dz3, dw3)
+			value += s.contrib4(d, attn3, xsb+1, ysb+1, zsb+0, wsb+1, dx3, dy3, dz3, dw3)
 		}
 
 		// Contribution (1,0,1,1)
@@ -1228,10 +1292,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy2 := dy0 - 3*squishConstant4D
 		dz2 := dz4
 		dw2 := dw3
-		attn2 := 2 - dx2*dx2 - dy2*dy2 - dz2*dz2 - dw2*dw2
+		attn2 := 2 - float64(dx2*dx2) - float64(dy2*dy2) - float64(dz2*dz2) - float64(dw2*dw2)
 		if attn2 > 0 {
-			attn2 *= attn2
-			value += attn2 * attn2 * s.extrapolate4(xsb+1, ysb+0, zsb+1, wsb+1, dx2, dy2, dz2, dw2)
+			value += s.contrib4(d, attn2, xsb+1, ysb+0, zsb+1, wsb+1, dx2, dy2, dz2, dw2)
 		}
 
 		// Contribution (0,1,1,1)
@@ -1239,10 +1302,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dz1 := dz4
 		dy1 := dy4
 		dw1 := dw3
-		attn1 := 2 - dx1*dx1 - dy1*dy1 - dz1*dz1 - dw1*dw1
+		attn1 := 2 - float64(dx1*dx1) - float64(dy1*dy1) - float64(dz1*dz1) - float64(dw1*dw1)
 		if attn1 > 0 {
-			attn1 *= attn1
-			value += attn1 * attn1 * s.extrapolate4(xsb+0, ysb+1, zsb+1, wsb+1, dx1, dy1, dz1, dw1)
+			value += s.contrib4(d, attn1, xsb+0, ysb+1, zsb+1, wsb+1, dx1, dy1, dz1, dw1)
 		}
 
 		// Contribution (1,1,1,1)
@@ -1250,10 +1312,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy0 = dy0 - 1 - 4*squishConstant4D
 		dz0 = dz0 - 1 - 4*squishConstant4D
 		dw0 = dw0 - 1 - 4*squishConstant4D
-		attn0 := 2 - dx0*dx0 - dy0*dy0 - dz0*dz0 - dw0*dw0
+		attn0 := 2 - float64(dx0*dx0) - float64(dy0*dy0) - float64(dz0*dz0) - float64(dw0*dw0)
 		if attn0 > 0 {
-			attn0 *= attn0
-			value += attn0 * attn0 * s.extrapolate4(xsb+1, ysb+1, zsb+1, wsb+1, dx0, dy0, dz0, dw0)
+			value += s.contrib4(d, attn0, xsb+1, ysb+1, zsb+1, wsb+1, dx0, dy0, dz0, dw0)
 		}
 	} else if inSum <= 2 { // We're inside the first dispentachoron (Rectified 4-Simplex)
 		var aScore, bScore float64
@@ -1602,10 +1663,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy1 := dy0 - 0 - squishConstant4D
 		dz1 := dz0 - 0 - squishConstant4D
 		dw1 := dw0 - 0 - squishConstant4D
-		attn1 := 2 - dx1*dx1 - dy1*dy1 - dz1*dz1 - dw1*dw1
+		attn1 := 2 - float64(dx1*dx1) - float64(dy1*dy1) - float64(dz1*dz1) - float64(dw1*dw1)
 		if attn1 > 0 {
-			attn1 *= attn1
-			value += attn1 * attn1 * s.extrapolate4(xsb+1, ysb+0, zsb+0, wsb+0, dx1, dy1, dz1, dw1)
+			value += s.contrib4(d, attn1, xsb+1, ysb+0, zsb+0, wsb+0, dx1, dy1, dz1, dw1)
 		}
 
 		// Contribution (0,1,0,0)
@@ -1613,10 +1673,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy2 := dy0 - 1 - squishConstant4D
 		dz2 := dz1
 		dw2 := dw1
-		attn2 := 2 - dx2*dx2 - dy2*dy2 - dz2*dz2 - dw2*dw2
+		attn2 := 2 - float64(dx2*dx2) - float64(dy2*dy2) - float64(dz2*dz2) - float64(dw2*dw2)
 		if attn2 > 0 {
-			attn2 *= attn2
-			value += attn2 * attn2 * s.extrapolate4(xsb+0, ysb+1, zsb+0, wsb+0, dx2, dy2, dz2, dw2)
+			value += s.contrib4(d, attn2, xsb+0, ysb+1, zsb+0, wsb+0, dx2, dy2, dz2, dw2)
 		}
 
 		// Contribution (0,0,1,0)
@@ -1624,10 +1683,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy3 := dy1
 		dz3 := dz0 - 1 - squishConstant4D
 		dw3 := dw1
-		attn3 := 2 - dx3*dx3 - dy3*dy3 - dz3*dz3 - dw3*dw3
+		attn3 := 2 - float64(dx3*dx3) - float64(dy3*dy3) - float64(dz3*dz3) - float64(dw3*dw3)
 		if attn3 > 0 {
-			attn3 *= attn3
-			value += attn3 * attn3 * s.extrapolate4(xsb+0, ysb+0, zsb+1, wsb+0, dx3, dy3, dz3, dw3)
+			value += s.contrib4(d, attn3, xsb+0, ysb+0, zsb+1, wsb+0, dx3, dy3, dz3, dw3)
 		}
 
 		// Contribution (0,0,0,1)
@@ -1635,10 +1693,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy4 := dy1
 		dz4 := dz1
 		dw4 := dw0 - 1 - squishConstant4D
-		attn4 := 2 - dx4*dx4 - dy4*dy4 - dz4*dz4 - dw4*dw4
+		attn4 := 2 - float64(dx4*dx4) - float64(dy4*dy4) - float64(dz4*dz4) - float64(dw4*dw4)
 		if attn4 > 0 {
-			attn4 *= attn4
-			value += attn4 * attn4 * s.extrapolate4(xsb+0, ysb+0, zsb+0, wsb+1, dx4, dy4, dz4, dw4)
+			value += s.contrib4(d, attn4, xsb+0, ysb+0, zsb+0, wsb+1, dx4, dy4, dz4, dw4)
 		}
 
 		// Contribution (1,1,0,0)
@@ -1646,10 +1703,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy5 := dy0 - 1 - 2*squishConstant4D
 		dz5 := dz0 - 0 - 2*squishConstant4D
 		dw5 := dw0 - 0 - 2*squishConstant4D
-		attn5 := 2 - dx5*dx5 - dy5*dy5 - dz5*dz5 - dw5*dw5
+		attn5 := 2 - float64(dx5*dx5) - float64(dy5*dy5) - float64(dz5*dz5) - float64(dw5*dw5)
 		if attn5 > 0 {
-			attn5 *= attn5
-			value += attn5 * attn5 * s.extrapolate4(xsb+1, ysb+1, zsb+0, wsb+0, dx5, dy5, dz5, dw5)
+			value += s.contrib4(d, attn5, xsb+1, ysb+1, zsb+0, wsb+0, dx5, dy5, dz5, dw5)
 		}
 
 		// Contribution (1,0,1,0)
@@ -1657,10 +1713,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy6 := dy0 - 0 - 2*squishConstant4D
 		dz6 := dz0 - 1 - 2*squishConstant4D
 		dw6 := dw0 - 0 - 2*squishConstant4D
-		attn6 := 2 - dx6*dx6 - dy6*dy6 - dz6*dz6 - dw6*dw6
+		attn6 := 2 - float64(dx6*dx6) - float64(dy6*dy6) - float64(dz6*dz6) - float64(dw6*dw6)
 		if attn6 > 0 {
-			attn6 *= attn6
-			value += attn6 * attn6 * s.extrapolate4(xsb+1, ysb+0, zsb+1, wsb+0, dx6, dy6, dz6, dw6)
+			value += s.contrib4(d, attn6, xsb+1, ysb+0, zsb+1, wsb+0, dx6, dy6, dz6, dw6)
 		}
 
 		// Contribution (1,0,0,1)
@@ -1668,10 +1723,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy7 := dy0 - 0 - 2*squishConstant4D
 		dz7 := dz0 - 0 - 2*squishConstant4D
 		dw7 := dw0 - 1 - 2*squishConstant4D
-		attn7 := 2 - dx7*dx7 - dy7*dy7 - dz7*dz7 - dw7*dw7
+		attn7 := 2 - float64(dx7*dx7) - float64(dy7*dy7) - float64(dz7*dz7) - float64(dw7*dw7)
 		if attn7 > 0 {
-			attn7 *= attn7
-			value += attn7 * attn7 * s.extrapolate4(xsb+1, ysb+0, zsb+0, wsb+1, dx7, dy7, dz7, dw7)
+			value += s.contrib4(d, attn7, xsb+1, ysb+0, zsb+0, wsb+1, dx7, dy7, dz7, dw7)
 		}
 
 		// Contribution (0,1,1,0)
@@ -1679,10 +1733,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy8 := dy0 - 1 - 2*squishConstant4D
 		dz8 := dz0 - 1 - 2*squishConstant4D
 		dw8 := dw0 - 0 - 2*squishConstant4D
-		attn8 := 2 - dx8*dx8 - dy8*dy8 - dz8*dz8 - dw8*dw8
+		attn8 := 2 - float64(dx8*dx8) - float64(dy8*dy8) - float64(dz8*dz8) - float64(dw8*dw8)
 		if attn8 > 0 {
-			attn8 *= attn8
-			value += attn8 * attn8 * s.extrapolate4(xsb+0, ysb+1, zsb+1, wsb+0, dx8, dy8, dz8, dw8)
+			value += s.contrib4(d, attn8, xsb+0, ysb+1, zsb+1, wsb+0, dx8, dy8, dz8, dw8)
 		}
 
 		// Contribution (0,1,0,1)
@@ -1690,10 +1743,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy9 := dy0 - 1 - 2*squishConstant4D
 		dz9 := dz0 - 0 - 2*squishConstant4D
 		dw9 := dw0 - 1 - 2*squishConstant4D
-		attn9 := 2 - dx9*dx9 - dy9*dy9 - dz9*dz9 - dw9*dw9
+		attn9 := 2 - float64(dx9*dx9) - float64(dy9*dy9) - float64(dz9*dz9) - float64(dw9*dw9)
 		if attn9 > 0 {
-			attn9 *= attn9
-			value += attn9 * attn9 * s.extrapolate4(xsb+0, ysb+1, zsb+0, wsb+1, dx9, dy9, dz9, dw9)
+			value += s.contrib4(d, attn9, xsb+0, ysb+1, zsb+0, wsb+1, dx9, dy9, dz9, dw9)
 		}
 
 		// Contribution (0,0,1,1)
@@ -1701,10 +1753,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy10 := dy0 - 0 - 2*squishConstant4D
 		dz10 := dz0 - 1 - 2*squishConstant4D
 		dw10 := dw0 - 1 - 2*squishConstant4D
-		attn10 := 2 - dx10*dx10 - dy10*dy10 - dz10*dz10 - dw10*dw10
+		attn10 := 2 - float64(dx10*dx10) - float64(dy10*dy10) - float64(dz10*dz10) - float64(dw10*dw10)
 		if attn10 > 0 {
-			attn10 *= attn10
-			value += attn10 * attn10 * s.extrapolate4(xsb+0, ysb+0, zsb+1, wsb+1, dx10, dy10, dz10, dw10)
+			value += s.contrib4(d, attn10, xsb+0, ysb+0, zsb+1, wsb+1, dx10, dy10, dz10, dw10)
 		}
 	} else { // We're inside the second dispentachoron (Rectified 4-Simplex)
 		var aScore, bScore float64
@@ -2043,10 +2094,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy4 := dy0 - 1 - 3*squishConstant4D
 		dz4 := dz0 - 1 - 3*squishConstant4D
 		dw4 := dw0 - 3*squishConstant4D
-		attn4 := 2 - dx4*dx4 - dy4*dy4 - dz4*dz4 - dw4*dw4
+		attn4 := 2 - float64(dx4*dx4) - float64(dy4*dy4) - float64(dz4*dz4) - float64(dw4*dw4)
 		if attn4 > 0 {
-			attn4 *= attn4
-			value += attn4 * attn4 * s.extrapolate4(xsb+1, ysb+1, zsb+1, wsb+0, dx4, dy4, dz4, dw4)
+			value += s.contrib4(d, attn4, xsb+1, ysb+1, zsb+1, wsb+0, dx4, dy4, dz4, dw4)
 		}
 
 		// Contribution (1,1,0,1)
@@ -2054,10 +2104,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy3 := dy4
 		dz3 := dz0 - 3*squishConstant4D
 		dw3 := dw0 - 1 - 3*squishConstant4D
-		attn3 := 2 - dx3*dx3 - dy3*dy3 - dz3*dz3 - dw3*dw3
+		attn3 := 2 - float64(dx3*dx3) - float64(dy3*dy3) - float64(dz3*dz3) - float64(dw3*dw3)
 		if attn3 > 0 {
-			attn3 *= attn3
-			value += attn3 * attn3 * s.extrapolate4(xsb+1, ysb+1, zsb+0, wsb+1, dx3, dy3, dz3, dw3)
+			value += s.contrib4(d, attn3, xsb+1, ysb+1, zsb+0, wsb+1, dx3, dy3, dz3, dw3)
 		}
 
 		// Contribution (1,0,1,1)
@@ -2065,10 +2114,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy2 := dy0 - 3*squishConstant4D
 		dz2 := dz4
 		dw2 := dw3
-		attn2 := 2 - dx2*dx2 - dy2*dy2 - dz2*dz2 - dw2*dw2
+		attn2 := 2 - float64(dx2*dx2) - float64(dy2*dy2) - float64(dz2*dz2) - float64(dw2*dw2)
 		if attn2 > 0 {
-			attn2 *= attn2
-			value += attn2 * attn2 * s.extrapolate4(xsb+1, ysb+0, zsb+1, wsb+1, dx2, dy2, dz2, dw2)
+			value += s.contrib4(d, attn2, xsb+1, ysb+0, zsb+1, wsb+1, dx2, dy2, dz2, dw2)
 		}
 
 		// Contribution (0,1,1,1)
@@ -2076,10 +2124,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dz1 := dz4
 		dy1 := dy4
 		dw1 := dw3
-		attn1 := 2 - dx1*dx1 - dy1*dy1 - dz1*dz1 - dw1*dw1
+		attn1 := 2 - float64(dx1*dx1) - float64(dy1*dy1) - float64(dz1*dz1) - float64(dw1*dw1)
 		if attn1 > 0 {
-			attn1 *= attn1
-			value += attn1 * attn1 * s.extrapolate4(xsb+0, ysb+1, zsb+1, wsb+1, dx1, dy1, dz1, dw1)
+			value += s.contrib4(d, attn1, xsb+0, ysb+1, zsb+1, wsb+1, dx1, dy1, dz1, dw1)
 		}
 
 		// Contribution (1,1,0,0)
@@ -2087,10 +2134,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy5 := dy0 - 1 - 2*squishConstant4D
 		dz5 := dz0 - 0 - 2*squishConstant4D
 		dw5 := dw0 - 0 - 2*squishConstant4D
-		attn5 := 2 - dx5*dx5 - dy5*dy5 - dz5*dz5 - dw5*dw5
+		attn5 := 2 - float64(dx5*dx5) - float64(dy5*dy5) - float64(dz5*dz5) - float64(dw5*dw5)
 		if attn5 > 0 {
-			attn5 *= attn5
-			value += attn5 * attn5 * s.extrapolate4(xsb+1, ysb+1, zsb+0, wsb+0, dx5, dy5, dz5, dw5)
+			value += s.contrib4(d, attn5, xsb+1, ysb+1, zsb+0, wsb+0, dx5, dy5, dz5, dw5)
 		}
 
 		// Contribution (1,0,1,0)
@@ -2098,10 +2144,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy6 := dy0 - 0 - 2*squishConstant4D
 		dz6 := dz0 - 1 - 2*squishConstant4D
 		dw6 := dw0 - 0 - 2*squishConstant4D
-		attn6 := 2 - dx6*dx6 - dy6*dy6 - dz6*dz6 - dw6*dw6
+		attn6 := 2 - float64(dx6*dx6) - float64(dy6*dy6) - float64(dz6*dz6) - float64(dw6*dw6)
 		if attn6 > 0 {
-			attn6 *= attn6
-			value += attn6 * attn6 * s.extrapolate4(xsb+1, ysb+0, zsb+1, wsb+0, dx6, dy6, dz6, dw6)
+			value += s.contrib4(d, attn6, xsb+1, ysb+0, zsb+1, wsb+0, dx6, dy6, dz6, dw6)
 		}
 
 		// Contribution (1,0,0,1)
@@ -2109,10 +2154,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy7 := dy0 - 0 - 2*squishConstant4D
 		dz7 := dz0 - 0 - 2*squishConstant4D
 		dw7 := dw0 - 1 - 2*squishConstant4D
-		attn7 := 2 - dx7*dx7 - dy7*dy7 - dz7*dz7 - dw7*dw7
+		attn7 := 2 - float64(dx7*dx7) - float64(dy7*dy7) - float64(dz7*dz7) - float64(dw7*dw7)
 		if attn7 > 0 {
-			attn7 *= attn7
-			value += attn7 * attn7 * s.extrapolate4(xsb+1, ysb+0, zsb+0, wsb+1, dx7, dy7, dz7, dw7)
+			value += s.contrib4(d, attn7, xsb+1, ysb+0, zsb+0, wsb+1, dx7, dy7, dz7, dw7)
 		}
 
 		// Contribution (0,1,1,0)
@@ -2120,10 +2164,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy8 := dy0 - 1 - 2*squishConstant4D
 		dz8 := dz0 - 1 - 2*squishConstant4D
 		dw8 := dw0 - 0 - 2*squishConstant4D
-		attn8 := 2 - dx8*dx8 - dy8*dy8 - dz8*dz8 - dw8*dw8
+		attn8 := 2 - float64(dx8*dx8) - float64(dy8*dy8) - float64(dz8*dz8) - float64(dw8*dw8)
 		if attn8 > 0 {
-			attn8 *= attn8
-			value += attn8 * attn8 * s.extrapolate4(xsb+0, ysb+1, zsb+1, wsb+0, dx8, dy8, dz8, dw8)
+			value += s.contrib4(d, attn8, xsb+0, ysb+1, zsb+1, wsb+0, dx8, dy8, dz8, dw8)
 		}
 
 		// Contribution (0,1,0,1)
@@ -2131,10 +2174,9 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy9 := dy0 - 1 - 2*squishConstant4D
 		dz9 := dz0 - 0 - 2*squishConstant4D
 		dw9 := dw0 - 1 - 2*squishConstant4D
-		attn9 := 2 - dx9*dx9 - dy9*dy9 - dz9*dz9 - dw9*dw9
+		attn9 := 2 - float64(dx9*dx9) - float64(dy9*dy9) - float64(dz9*dz9) - float64(dw9*dw9)
 		if attn9 > 0 {
-			attn9 *= attn9
-			value += attn9 * attn9 * s.extrapolate4(xsb+0, ysb+1, zsb+0, wsb+1, dx9, dy9, dz9, dw9)
+			value += s.contrib4(d, attn9, xsb+0, ysb+1, zsb+0, wsb+1, dx9, dy9, dz9, dw9)
 		}
 
 		// Contribution (0,0,1,1)
@@ -2142,32 +2184,28 @@ func (s *noise) Eval4(x, y, z, w float64) float64 {
 		dy10 := dy0 - 0 - 2*squishConstant4D
 		dz10 := dz0 - 1 - 2*squishConstant4D
 		dw10 := dw0 - 1 - 2*squishConstant4D
-		attn10 := 2 - dx10*dx10 - dy10*dy10 - dz10*dz10 - dw10*dw10
+		attn10 := 2 - float64(dx10*dx10) - float64(dy10*dy10) - float64(dz10*dz10) - float64(dw10*dw10)
 		if attn10 > 0 {
-			attn10 *= attn10
-			value += attn10 * attn10 * s.extrapolate4(xsb+0, ysb+0, zsb+1, wsb+1, dx10, dy10, dz10, dw10)
+			value += s.contrib4(d, attn10, xsb+0, ysb+0, zsb+1, wsb+1, dx10, dy10, dz10, dw10)
 		}
 	}
 
 	// First extra vertex
-	attnExt0 := 2 - dxExt0*dxExt0 - dyExt0*dyExt0 - dzExt0*dzExt0 - dwExt0*dwExt0
+	attnExt0 := 2 - float64(dxExt0*dxExt0) - float64(dyExt0*dyExt0) - float64(dzExt0*dzExt0) - float64(dwExt0*dwExt0)
 	if attnExt0 > 0 {
-		attnExt0 *= attnExt0
-		value += attnExt0 * attnExt0 * s.extrapolate4(xsvExt0, ysvExt0, zsvExt0, wsvExt0, dxExt0, dyExt0, dzExt0, dwExt0)
+		value += s.contrib4(d, attnExt0, xsvExt0, ysvExt0, zsvExt0, wsvExt0, dxExt0, dyExt0, dzExt0, dwExt0)
 	}
 
 	// Second extra vertex
-	attnExt1 := 2 - dxExt1*dxExt1 - dyExt1*dyExt1 - dzExt1*dzExt1 - dwExt1*dwExt1
+	attnExt1 := 2 - float64(dxExt1*dxExt1) - float64(dyExt1*dyExt1) - float64(dzExt1*dzExt1) - float64(dwExt1*dwExt1)
 	if attnExt1 > 0 {
-		attnExt1 *= attnExt1
-		value += attnExt1 * attnExt1 * s.extrapolate4(xsvExt1, ysvExt1, zsvExt1, wsvExt1, dxExt1, dyExt1, dzExt1, dwExt1)
+		value += s.contrib4(d, attnExt1, xsvExt1, ysvExt1, zsvExt1, wsvExt1, dxExt1, dyExt1, dzExt1, dwExt1)
 	}
 
 	// Third extra vertex
-	attnExt2 := 2 - dxExt2*dxExt2 - dyExt2*dyExt2 - dzExt2*dzExt2 - dwExt2*dwExt2
+	attnExt2 := 2 - float64(dxExt2*dxExt2) - float64(dyExt2*dyExt2) - float64(dzExt2*dzExt2) - float64(dwExt2*dwExt2)
 	if attnExt2 > 0 {
-		attnExt2 *= attnExt2
-		value += attnExt2 * attnExt2 * s.extrapolate4(xsvExt2, ysvExt2, zsvExt2, wsvExt2, dxExt2, dyExt2, dzExt2, dwExt2)
+		value += s.contrib4(d, attnExt2, xsvExt2, ysvExt2, zsvExt2, wsvExt2, dxExt2, dyExt2, dzExt2, dwExt2)
 	}
 
 	return value / normConstant4D
@@ -0,0 +1,2213 @@
+// Code generated by go run ./internal/genlattice; DO NOT EDIT.
+
+package opensimplex
+
+// Eval1 is noise.Eval1 in float32 arithmetic.
+func (s *noise32) Eval1(x float32) float32 {
+	xsb := floor32(x)
+	dx0 := x - float32(xsb)
+	dx1 := dx0 - 1
+
+	return (s.contrib1(xsb, dx0) + s.contrib1(xsb+1, dx1)) / normConstant1D
+}
+
+// contrib1 is noise.contrib1 in float32 arithmetic.
+func (s *noise32) contrib1(xsb int32, dx float32) float32 {
+	attn := 1 - float32(dx*dx)
+	attn2 := attn * attn
+	return float32(attn2 * attn2 * float32(gradients1D[s.gradIndex1(xsb)]) * dx)
+}
+
+// Eval2 is noise.Eval2 in float32 arithmetic.
+func (s *noise32) Eval2(x, y float32) float32 {
+	return s.eval2(x, y)
+}
+
+// Eval3 is noise.Eval3 in float32 arithmetic.
+func (s *noise32) Eval3(x, y, z float32) float32 {
+	return s.eval3(x, y, z)
+}
+
+// Eval4 is noise.Eval4 in float32 arithmetic.
+func (s *noise32) Eval4(x, y, z, w float32) float32 {
+	return s.eval4(x, y, z, w)
+}
+
+// eval2 is noise.eval2 in float32 arithmetic.
+func (s *noise32) eval2(x, y float32) float32 {
+	// Place input coordinates onto grid.
+	stretchOffset := float32((x + y) * stretchConstant2D32)
+	xs := x + stretchOffset
+	ys := y + stretchOffset
+
+	// Floor to get grid coordinates of rhombus (stretched square) super-cell origin.
+	xsb := floor32(xs)
+	ysb := floor32(ys)
+
+	// Skew out to get actual coordinates of rhombus origin. We'll need these later.
+	squishOffset := float32(float32(xsb+ysb) * squishConstant2D32)
+	xb := float32(xsb) + squishOffset
+	yb := float32(ysb) + squishOffset
+
+	// Compute grid coordinates relative to rhombus origin.
+	xins := xs - float32(xsb)
+	yins := ys - float32(ysb)
+
+	// Positions relative to origin point.
+	dx0 := x - xb
+	dy0 := y - yb
+
+	return s.eval2Cell(xsb, ysb, xins, yins, dx0, dy0)
+}
+
+// eval2Cell is noise.eval2Cell in float32 arithmetic.
+func (s *noise32) eval2Cell(xsb, ysb int32, xins, yins, dx0, dy0 float32) float32 {
+	// Sum those together to get a value that determines which region we're in.
+	inSum := xins + yins
+
+	// We'll be defining these inside the next block and using them afterwards.
+	var dxExt, dyExt float32
+	var xsvExt, ysvExt int32
+
+	value := float32(0)
+
+	// Contribution (1,0)
+	dx1 := dx0 - 1 - squishConstant2D32
+	dy1 := dy0 - 0 - squishConstant2D32
+	attn1 := 2 - float32(dx1*dx1) - float32(dy1*dy1)
+	if attn1 > 0 {
+		value += s.contrib2(attn1, xsb+1, ysb+0, dx1, dy1)
+	}
+
+	// Contribution (0,1)
+	dx2 := dx0 - 0 - squishConstant2D32
+	dy2 := dy0 - 1 - squishConstant2D32
+	attn2 := 2 - float32(dx2*dx2) - float32(dy2*dy2)
+	if attn2 > 0 {
+		value += s.contrib2(attn2, xsb+0, ysb+1, dx2, dy2)
+	}
+
+	if inSum <= 1 { // We're inside the triangle (2-Simplex) at (0,0)
+		zins := 1 - inSum
+		if zins > xins || zins > yins { // (0,0) is one of the closest two triangular vertices
+			if xins > yins {
+				xsvExt = xsb + 1
+				ysvExt = ysb - 1
+				dxExt = dx0 - 1
+				dyExt = dy0 + 1
+			} else {
+				xsvExt = xsb - 1
+				ysvExt = ysb + 1
+				dxExt = dx0 + 1
+				dyExt = dy0 - 1
+			}
+		} else { // (1,0) and (0,1) are the closest two vertices.
+			xsvExt = xsb + 1
+			ysvExt = ysb + 1
+			dxExt = dx0 - 1 - 2*squishConstant2D32
+			dyExt = dy0 - 1 - 2*squishConstant2D32
+		}
+	} else { // We're inside the triangle (2-Simplex) at (1,1)
+		zins := 2 - inSum
+		if zins < xins || zins < yins { // (0,0) is one of the closest two triangular vertices
+			if xins > yins {
+				xsvExt = xsb + 2
+				ysvExt = ysb + 0
+				dxExt = dx0 - 2 - 2*squishConstant2D32
+				dyExt = dy0 + 0 - 2*squishConstant2D32
+			} else {
+				xsvExt = xsb + 0
+				ysvExt = ysb + 2
+				dxExt = dx0 + 0 - 2*squishConstant2D32
+				dyExt = dy0 - 2 - 2*squishConstant2D32
+			}
+		} else { // (1,0) and (0,1) are the closest two vertices.
+			dxExt = dx0
+			dyExt = dy0
+			xsvExt = xsb
+			ysvExt = ysb
+		}
+		xsb += 1
+		ysb += 1
+		dx0 = dx0 - 1 - 2*squishConstant2D32
+		dy0 = dy0 - 1 - 2*squishConstant2D32
+	}
+
+	// Contribution (0,0) or (1,1)
+	attn0 := 2 - float32(dx0*dx0) - float32(dy0*dy0)
+	if attn0 > 0 {
+		value += s.contrib2(attn0, xsb, ysb, dx0, dy0)
+	}
+
+	// Extra Vertex
+	attnExt := 2 - float32(dxExt*dxExt) - float32(dyExt*dyExt)
+	if attnExt > 0 {
+		value += s.contrib2(attnExt, xsvExt, ysvExt, dxExt, dyExt)
+	}
+
+	return value / normConstant2D
+}
+
+// eval3 is noise.eval3 in float32 arithmetic.
+func (s *noise32) eval3(x, y, z float32) float32 {
+	// Place input coordinates on simplectic honeycomb.
+	stretchOffset := float32((x + y + z) * stretchConstant3D32)
+	xs := x + stretchOffset
+	ys := y + stretchOffset
+	zs := z + stretchOffset
+
+	// Floor to get simplectic honeycomb coordinates of rhombohedron (stretched cube) super-cell origin.
+	xsb := floor32(xs)
+	ysb := floor32(ys)
+	zsb := floor32(zs)
+
+	// Skew out to get actual coordinates of rhombohedron origin. We'll need these later.
+	squishOffset := float32(float32(xsb+ysb+zsb) * squishConstant3D32)
+	xb := float32(xsb) + squishOffset
+	yb := float32(ysb) + squishOffset
+	zb := float32(zsb) + squishOffset
+
+	// Compute simplectic honeycomb coordinates relative to rhombohedral origin.
+	xins := xs - float32(xsb)
+	yins := ys - float32(ysb)
+	zins := zs - float32(zsb)
+
+	// Positions relative to origin point.
+	dx0 := x - xb
+	dy0 := y - yb
+	dz0 := z - zb
+
+	return s.eval3Cell(xsb, ysb, zsb, xins, yins, zins, dx0, dy0, dz0)
+}
+
+// eval3Cell is noise.eval3Cell in float32 arithmetic.
+func (s *noise32) eval3Cell(xsb, ysb, zsb int32, xins, yins, zins, dx0, dy0, dz0 float32) float32 {
+	// Sum those together to get a value that determines which region we're in.
+	inSum := xins + yins + zins
+
+	// We'll be defining these inside the next block and using them afterwards.
+	var dxExt0, dyExt0, dzExt0 float32
+	var dxExt1, dyExt1, dzExt1 float32
+	var xsvExt0, ysvExt0, zsvExt0 int32
+	var xsvExt1, ysvExt1, zsvExt1 int32
+
+	value := float32(0)
+	if inSum <= 1 { // We're inside the tetrahedron (3-Simplex) at (0,0,0)
+
+		// Determine which two of (0,0,1), (0,1,0), (1,0,0) are closest.
+		aPoint := byte(0x01)
+		bPoint := byte(0x02)
+		aScore := xins
+		bScore := yins
+		if aScore >= bScore && zins > bScore {
+			bScore = zins
+			bPoint = 0x04
+		} else if aScore < bScore && zins > aScore {
+			aScore = zins
+			aPoint = 0x04
+		}
+
+		// Now we determine the two lattice points not part of the tetrahedron that may contribute.
+		// This depends on the closest two tetrahedral vertices, including (0,0,0)
+		wins := 1 - inSum
+		if wins > aScore || wins > bScore { // (0,0,0) is one of the closest two tetrahedral vertices.
+			var c byte // Our other closest vertex is the closest out of a and b.
+			if bScore > aScore {
+				c = bPoint
+			} else {
+				c = aPoint
+			}
+
+			if (c & 0x01) == 0 {
+				xsvExt0 = xsb - 1
+				xsvExt1 = xsb
+				dxExt0 = dx0 + 1
+				dxExt1 = dx0
+			} else {
+				xsvExt1 = xsb + 1
+				xsvExt0 = xsvExt1
+				dxExt1 = dx0 - 1
+				dxExt0 = dxExt1
+			}
+
+			if (c & 0x02) == 0 {
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0
+				dyExt0 = dyExt1
+				if (c & 0x01) == 0 {
+					ysvExt1 -= 1
+					dyExt1 += 1
+				} else {
+					ysvExt0 -= 1
+					dyExt0 += 1
+				}
+			} else {
+				ysvExt1 = ysb + 1
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - 1
+				dyExt0 = dyExt1
+			}
+
+			if (c & 0x04) == 0 {
+				zsvExt0 = zsb
+				zsvExt1 = zsb - 1
+				dzExt0 = dz0
+				dzExt1 = dz0 + 1
+			} else {
+				zsvExt1 = zsb + 1
+				zsvExt0 = zsvExt1
+				dzExt1 = dz0 - 1
+				dzExt0 = dzExt1
+			}
+		} else { // (0,0,0) is not one of the closest two tetrahedral vertices.
+			c := aPoint | bPoint // Our two extra vertices are determined by the closest two.
+
+			if (c & 0x01) == 0 {
+				xsvExt0 = xsb
+				xsvExt1 = xsb - 1
+				dxExt0 = dx0 - 2*squishConstant3D32
+				dxExt1 = dx0 + 1 - squishConstant3D32
+			} else {
+				xsvExt1 = xsb + 1
+				xsvExt0 = xsvExt1
+				dxExt0 = dx0 - 1 - 2*squishConstant3D32
+				dxExt1 = dx0 - 1 - squishConstant3D32
+			}
+
+			if (c & 0x02) == 0 {
+				ysvExt0 = ysb
+				ysvExt1 = ysb - 1
+				dyExt0 = dy0 - 2*squishConstant3D32
+				dyExt1 = dy0 + 1 - squishConstant3D32
+			} else {
+				ysvExt1 = ysb + 1
+				ysvExt0 = ysvExt1
+				dyExt0 = dy0 - 1 - 2*squishConstant3D32
+				dyExt1 = dy0 - 1 - squishConstant3D32
+			}
+
+			if (c & 0x04) == 0 {
+				zsvExt0 = zsb
+				zsvExt1 = zsb - 1
+				dzExt0 = dz0 - 2*squishConstant3D32
+				dzExt1 = dz0 + 1 - squishConstant3D32
+			} else {
+				zsvExt1 = zsb + 1
+				zsvExt0 = zsvExt1
+				dzExt0 = dz0 - 1 - 2*squishConstant3D32
+				dzExt1 = dz0 - 1 - squishConstant3D32
+			}
+		}
+
+		// Contribution (0,0,0)
+		attn0 := 2 - float32(dx0*dx0) - float32(dy0*dy0) - float32(dz0*dz0)
+		if attn0 > 0 {
+			value += s.contrib3(attn0, xsb+0, ysb+0, zsb+0, dx0, dy0, dz0)
+		}
+
+		// Contribution (1,0,0)
+		dx1 := dx0 - 1 - squishConstant3D32
+		dy1 := dy0 - 0 - squishConstant3D32
+		dz1 := dz0 - 0 - squishConstant3D32
+		attn1 := 2 - float32(dx1*dx1) - float32(dy1*dy1) - float32(dz1*dz1)
+		if attn1 > 0 {
+			value += s.contrib3(attn1, xsb+1, ysb+0, zsb+0, dx1, dy1, dz1)
+		}
+
+		// Contribution (0,1,0)
+		dx2 := dx0 - 0 - squishConstant3D32
+		dy2 := dy0 - 1 - squishConstant3D32
+		dz2 := dz1
+		attn2 := 2 - float32(dx2*dx2) - float32(dy2*dy2) - float32(dz2*dz2)
+		if attn2 > 0 {
+			value += s.contrib3(attn2, xsb+0, ysb+1, zsb+0, dx2, dy2, dz2)
+		}
+
+		// Contribution (0,0,1)
+		dx3 := dx2
+		dy3 := dy1
+		dz3 := dz0 - 1 - squishConstant3D32
+		attn3 := 2 - float32(dx3*dx3) - float32(dy3*dy3) - float32(dz3*dz3)
+		if attn3 > 0 {
+			value += s.contrib3(attn3, xsb+0, ysb+0, zsb+1, dx3, dy3, dz3)
+		}
+	} else if inSum >= 2 { // We're inside the tetrahedron (3-Simplex) at (1,1,1)
+
+		// Determine which two tetrahedral vertices are the closest, out of (1,1,0), (1,0,1), (0,1,1) but not (1,1,1).
+		aPoint := byte(0x06)
+		aScore := xins
+		bPoint := byte(0x05)
+		bScore := yins
+		if aScore <= bScore && zins < bScore {
+			bScore = zins
+			bPoint = 0x03
+		} else if aScore > bScore && zins < aScore {
+			aScore = zins
+			aPoint = 0x03
+		}
+
+		// Now we determine the two lattice points not part of the tetrahedron that may contribute.
+		// This depends on the closest two tetrahedral vertices, including (1,1,1)
+		wins := 3 - inSum
+		if wins < aScore || wins < bScore { // (1,1,1) is one of the closest two tetrahedral vertices.
+			var c byte // Our other closest vertex is the closest out of a and b.
+			if bScore < aScore {
+				c = bPoint
+			} else {
+				c = aPoint
+			}
+
+			if (c & 0x01) != 0 {
+				xsvExt0 = xsb + 2
+				xsvExt1 = xsb + 1
+				dxExt0 = dx0 - 2 - 3*squishConstant3D32
+				dxExt1 = dx0 - 1 - 3*squishConstant3D32
+			} else {
+				xsvExt1 = xsb
+				xsvExt0 = xsvExt1
+				dxExt1 = dx0 - 3*squishConstant3D32
+				dxExt0 = dxExt1
+			}
+
+			if (c & 0x02) != 0 {
+				ysvExt1 = ysb + 1
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - 1 - 3*squishConstant3D32
+				dyExt0 = dyExt1
+				if (c & 0x01) != 0 {
+					ysvExt1 += 1
+					dyExt1 -= 1
+				} else {
+					ysvExt0 += 1
+					dyExt0 -= 1
+				}
+			} else {
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - 3*squishConstant3D32
+				dyExt0 = dyExt1
+			}
+
+			if (c & 0x04) != 0 {
+				zsvExt0 = zsb + 1
+				zsvExt1 = zsb + 2
+				dzExt0 = dz0 - 1 - 3*squishConstant3D32
+				dzExt1 = dz0 - 2 - 3*squishConstant3D32
+			} else {
+				zsvExt1 = zsb
+				zsvExt0 = zsvExt1
+				dzExt1 = dz0 - 3*squishConstant3D32
+				dzExt0 = dzExt1
+			}
+		} else { // (1,1,1) is not one of the closest two tetrahedral vertices.
+			c := aPoint & bPoint // Our two extra vertices are determined by the closest two.
+
+			if (c & 0x01) != 0 {
+				xsvExt0 = xsb + 1
+				xsvExt1 = xsb + 2
+				dxExt0 = dx0 - 1 - squishConstant3D32
+				dxExt1 = dx0 - 2 - 2*squishConstant3D32
+			} else {
+				xsvExt1 = xsb
+				xsvExt0 = xsvExt1
+				dxExt0 = dx0 - squishConstant3D32
+				dxExt1 = dx0 - 2*squishConstant3D32
+			}
+
+			if (c & 0x02) != 0 {
+				ysvExt0 = ysb + 1
+				ysvExt1 = ysb + 2
+				dyExt0 = dy0 - 1 - squishConstant3D32
+				dyExt1 = dy0 - 2 - 2*squishConstant3D32
+			} else {
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				dyExt0 = dy0 - squishConstant3D32
+				dyExt1 = dy0 - 2*squishConstant3D32
+			}
+
+			if (c & 0x04) != 0 {
+				zsvExt0 = zsb + 1
+				zsvExt1 = zsb + 2
+				dzExt0 = dz0 - 1 - squishConstant3D32
+				dzExt1 = dz0 - 2 - 2*squishConstant3D32
+			} else {
+				zsvExt1 = zsb
+				zsvExt0 = zsvExt1
+				dzExt0 = dz0 - squishConstant3D32
+				dzExt1 = dz0 - 2*squishConstant3D32
+			}
+		}
+
+		// Contribution (1,1,0)
+		dx3 := dx0 - 1 - 2*squishConstant3D32
+		dy3 := dy0 - 1 - 2*squishConstant3D32
+		dz3 := dz0 - 0 - 2*squishConstant3D32
+		attn3 := 2 - float32(dx3*dx3) - float32(dy3*dy3) - float32(dz3*dz3)
+		if attn3 > 0 {
+			value += s.contrib3(attn3, xsb+1, ysb+1, zsb+0, dx3, dy3, dz3)
+		}
+
+		// Contribution (1,0,1)
+		dx2 := dx3
+		dy2 := dy0 - 0 - 2*squishConstant3D32
+		dz2 := dz0 - 1 - 2*squishConstant3D32
+		attn2 := 2 - float32(dx2*dx2) - float32(dy2*dy2) - float32(dz2*dz2)
+		if attn2 > 0 {
+			value += s.contrib3(attn2, xsb+1, ysb+0, zsb+1, dx2, dy2, dz2)
+		}
+
+		// Contribution (0,1,1)
+		dx1 := dx0 - 0 - 2*squishConstant3D32
+		dy1 := dy3
+		dz1 := dz2
+		attn1 := 2 - float32(dx1*dx1) - float32(dy1*dy1) - float32(dz1*dz1)
+		if attn1 > 0 {
+			value += s.contrib3(attn1, xsb+0, ysb+1, zsb+1, dx1, dy1, dz1)
+		}
+
+		// Contribution (1,1,1)
+		dx0 = dx0 - 1 - 3*squishConstant3D32
+		dy0 = dy0 - 1 - 3*squishConstant3D32
+		dz0 = dz0 - 1 - 3*squishConstant3D32
+		attn0 := 2 - float32(dx0*dx0) - float32(dy0*dy0) - float32(dz0*dz0)
+		if attn0 > 0 {
+			value += s.contrib3(attn0, xsb+1, ysb+1, zsb+1, dx0, dy0, dz0)
+		}
+	} else { // We're inside the octahedron (Rectified 3-Simplex) in between.
+		var aScore, bScore float32
+		var aPoint, bPoint byte
+		var aIsFurtherSide, bIsFurtherSide bool
+
+		// Decide between point (0,0,1) and (1,1,0) as closest
+		p1 := xins + yins
+		if p1 > 1 {
+			aScore = p1 - 1
+			aPoint = 0x03
+			aIsFurtherSide = true
+		} else {
+			aScore = 1 - p1
+			aPoint = 0x04
+			aIsFurtherSide = false
+		}
+
+		// Decide between point (0,1,0) and (1,0,1) as closest
+		p2 := xins + zins
+		if p2 > 1 {
+			bScore = p2 - 1
+			bPoint = 0x05
+			bIsFurtherSide = true
+		} else {
+			bScore = 1 - p2
+			bPoint = 0x02
+			bIsFurtherSide = false
+		}
+
+		// The closest out of the two (1,0,0) and (0,1,1) will replace the furthest out of the two decided above, if closer.
+		p3 := yins + zins
+		if p3 > 1 {
+			score := p3 - 1
+			if aScore <= bScore && aScore < score {
+				aPoint = 0x06
+				aIsFurtherSide = true
+			} else if aScore > bScore && bScore < score {
+				bPoint = 0x06
+				bIsFurtherSide = true
+			}
+		} else {
+			score := 1 - p3
+			if aScore <= bScore && aScore < score {
+				aPoint = 0x01
+				aIsFurtherSide = false
+			} else if aScore > bScore && bScore < score {
+				bPoint = 0x01
+				bIsFurtherSide = false
+			}
+		}
+
+		// Where each of the two closest points are determines how the extra two vertices are calculated.
+		if aIsFurtherSide == bIsFurtherSide {
+			if aIsFurtherSide { // Both closest points on (1,1,1) side
+
+				// One of the two extra points is (1,1,1)
+				dxExt0 = dx0 - 1 - 3*squishConstant3D32
+				dyExt0 = dy0 - 1 - 3*squishConstant3D32
+				dzExt0 = dz0 - 1 - 3*squishConstant3D32
+				xsvExt0 = xsb + 1
+				ysvExt0 = ysb + 1
+				zsvExt0 = zsb + 1
+
+				// Other extra point is based on the shared axis.
+				c := aPoint & bPoint
+				if (c & 0x01) != 0 {
+					dxExt1 = dx0 - 2 - 2*squishConstant3D32
+					dyExt1 = dy0 - 2*squishConstant3D32
+					dzExt1 = dz0 - 2*squishConstant3D32
+					xsvExt1 = xsb + 2
+					ysvExt1 = ysb
+					zsvExt1 = zsb
+				} else if (c & 0x02) != 0 {
+					dxExt1 = dx0 - 2*squishConstant3D32
+					dyExt1 = dy0 - 2 - 2*squishConstant3D32
+					dzExt1 = dz0 - 2*squishConstant3D32
+					xsvExt1 = xsb
+					ysvExt1 = ysb + 2
+					zsvExt1 = zsb
+				} else {
+					dxExt1 = dx0 - 2*squishConstant3D32
+					dyExt1 = dy0 - 2*squishConstant3D32
+					dzExt1 = dz0 - 2 - 2*squishConstant3D32
+					xsvExt1 = xsb
+					ysvExt1 = ysb
+					zsvExt1 = zsb + 2
+				}
+			} else { // Both closest points on (0,0,0) side
+
+				// One of the two extra points is (0,0,0)
+				dxExt0 = dx0
+				dyExt0 = dy0
+				dzExt0 = dz0
+				xsvExt0 = xsb
+				ysvExt0 = ysb
+				zsvExt0 = zsb
+
+				// Other extra point is based on the omitted axis.
+				c := aPoint | bPoint
+				if (c & 0x01) == 0 {
+					dxExt1 = dx0 + 1 - squishConstant3D32
+					dyExt1 = dy0 - 1 - squishConstant3D32
+					dzExt1 = dz0 - 1 - squishConstant3D32
+					xsvExt1 = xsb - 1
+					ysvExt1 = ysb + 1
+					zsvExt1 = zsb + 1
+				} else if (c & 0x02) == 0 {
+					dxExt1 = dx0 - 1 - squishConstant3D32
+					dyExt1 = dy0 + 1 - squishConstant3D32
+					dzExt1 = dz0 - 1 - squishConstant3D32
+					xsvExt1 = xsb + 1
+					ysvExt1 = ysb - 1
+					zsvExt1 = zsb + 1
+				} else {
+					dxExt1 = dx0 - 1 - squishConstant3D32
+					dyExt1 = dy0 - 1 - squishConstant3D32
+					dzExt1 = dz0 + 1 - squishConstant3D32
+					xsvExt1 = xsb + 1
+					ysvExt1 = ysb + 1
+					zsvExt1 = zsb - 1
+				}
+			}
+		} else { // One point on (0,0,0) side, one point on (1,1,1) side
+			var c1, c2 byte
+			if aIsFurtherSide {
+				c1 = aPoint
+				c2 = bPoint
+			} else {
+				c1 = bPoint
+				c2 = aPoint
+			}
+
+			// One contribution is a permutation of (1,1,-1)
+			if (c1 & 0x01) == 0 {
+				dxExt0 = dx0 + 1 - squishConstant3D32
+				dyExt0 = dy0 - 1 - squishConstant3D32
+				dzExt0 = dz0 - 1 - squishConstant3D32
+				xsvExt0 = xsb - 1
+				ysvExt0 = ysb + 1
+				zsvExt0 = zsb + 1
+			} else if (c1 & 0x02) == 0 {
+				dxExt0 = dx0 - 1 - squishConstant3D32
+				dyExt0 = dy0 + 1 - squishConstant3D32
+				dzExt0 = dz0 - 1 - squishConstant3D32
+				xsvExt0 = xsb + 1
+				ysvExt0 = ysb - 1
+				zsvExt0 = zsb + 1
+			} else {
+				dxExt0 = dx0 - 1 - squishConstant3D32
+				dyExt0 = dy0 - 1 - squishConstant3D32
+				dzExt0 = dz0 + 1 - squishConstant3D32
+				xsvExt0 = xsb + 1
+				ysvExt0 = ysb + 1
+				zsvExt0 = zsb - 1
+			}
+
+			// One contribution is a permutation of (0,0,2)
+			dxExt1 = dx0 - 2*squishConstant3D32
+			dyExt1 = dy0 - 2*squishConstant3D32
+			dzExt1 = dz0 - 2*squishConstant3D32
+			xsvExt1 = xsb
+			ysvExt1 = ysb
+			zsvExt1 = zsb
+			if (c2 & 0x01) != 0 {
+				dxExt1 -= 2
+				xsvExt1 += 2
+			} else if (c2 & 0x02) != 0 {
+				dyExt1 -= 2
+				ysvExt1 += 2
+			} else {
+				dzExt1 -= 2
+				zsvExt1 += 2
+			}
+		}
+
+		// Contribution (1,0,0)
+		dx1 := dx0 - 1 - squishConstant3D32
+		dy1 := dy0 - 0 - squishConstant3D32
+		dz1 := dz0 - 0 - squishConstant3D32
+		attn1 := 2 - float32(dx1*dx1) - float32(dy1*dy1) - float32(dz1*dz1)
+		if attn1 > 0 {
+			value += s.contrib3(attn1, xsb+1, ysb+0, zsb+0, dx1, dy1, dz1)
+		}
+
+		// Contribution (0,1,0)
+		dx2 := dx0 - 0 - squishConstant3D32
+		dy2 := dy0 - 1 - squishConstant3D32
+		dz2 := dz1
+		attn2 := 2 - float32(dx2*dx2) - float32(dy2*dy2) - float32(dz2*dz2)
+		if attn2 > 0 {
+			value += s.contrib3(attn2, xsb+0, ysb+1, zsb+0, dx2, dy2, dz2)
+		}
+
+		// Contribution (0,0,1)
+		dx3 := dx2
+		dy3 := dy1
+		dz3 := dz0 - 1 - squishConstant3D32
+		attn3 := 2 - float32(dx3*dx3) - float32(dy3*dy3) - float32(dz3*dz3)
+		if attn3 > 0 {
+			value += s.contrib3(attn3, xsb+0, ysb+0, zsb+1, dx3, dy3, dz3)
+		}
+
+		// Contribution (1,1,0)
+		dx4 := dx0 - 1 - 2*squishConstant3D32
+		dy4 := dy0 - 1 - 2*squishConstant3D32
+		dz4 := dz0 - 0 - 2*squishConstant3D32
+		attn4 := 2 - float32(dx4*dx4) - float32(dy4*dy4) - float32(dz4*dz4)
+		if attn4 > 0 {
+			value += s.contrib3(attn4, xsb+1, ysb+1, zsb+0, dx4, dy4, dz4)
+		}
+
+		// Contribution (1,0,1)
+		dx5 := dx4
+		dy5 := dy0 - 0 - 2*squishConstant3D32
+		dz5 := dz0 - 1 - 2*squishConstant3D32
+		attn5 := 2 - float32(dx5*dx5) - float32(dy5*dy5) - float32(dz5*dz5)
+		if attn5 > 0 {
+			value += s.contrib3(attn5, xsb+1, ysb+0, zsb+1, dx5, dy5, dz5)
+		}
+
+		// Contribution (0,1,1)
+		dx6 := dx0 - 0 - 2*squishConstant3D32
+		dy6 := dy4
+		dz6 := dz5
+		attn6 := 2 - float32(dx6*dx6) - float32(dy6*dy6) - float32(dz6*dz6)
+		if attn6 > 0 {
+			value += s.contrib3(attn6, xsb+0, ysb+1, zsb+1, dx6, dy6, dz6)
+		}
+	}
+
+	// First extra vertex
+	attnExt0 := 2 - float32(dxExt0*dxExt0) - float32(dyExt0*dyExt0) - float32(dzExt0*dzExt0)
+	if attnExt0 > 0 {
+		value += s.contrib3(attnExt0, xsvExt0, ysvExt0, zsvExt0, dxExt0, dyExt0, dzExt0)
+	}
+
+	// Second extra vertex
+	attnExt1 := 2 - float32(dxExt1*dxExt1) - float32(dyExt1*dyExt1) - float32(dzExt1*dzExt1)
+	if attnExt1 > 0 {
+		value += s.contrib3(attnExt1, xsvExt1, ysvExt1, zsvExt1, dxExt1, dyExt1, dzExt1)
+	}
+
+	return value / normConstant3D
+}
+
+// eval4 is noise.eval4 in float32 arithmetic.
+func (s *noise32) eval4(x, y, z, w float32) float32 {
+	// Place input coordinates on simplectic honeycomb.
+	stretchOffset := float32((x + y + z + w) * stretchConstant4D32)
+	xs := x + stretchOffset
+	ys := y + stretchOffset
+	zs := z + stretchOffset
+	ws := w + stretchOffset
+
+	// Floor to get simplectic honeycomb coordinates of rhombo-hypercube super-cell origin.
+	xsb := floor32(xs)
+	ysb := floor32(ys)
+	zsb := floor32(zs)
+	wsb := floor32(ws)
+
+	// Skew out to get actual coordinates of stretched rhombo-hypercube origin. We'll need these later.
+	squishOffset := float32(float32(xsb+ysb+zsb+wsb) * squishConstant4D32)
+	xb := float32(xsb) + squishOffset
+	yb := float32(ysb) + squishOffset
+	zb := float32(zsb) + squishOffset
+	wb := float32(wsb) + squishOffset
+
+	// Compute simplectic honeycomb coordinates relative to rhombo-hypercube origin.
+	xins := xs - float32(xsb)
+	yins := ys - float32(ysb)
+	zins := zs - float32(zsb)
+	wins := ws - float32(wsb)
+
+	// Positions relative to origin point.
+	dx0 := x - xb
+	dy0 := y - yb
+	dz0 := z - zb
+	dw0 := w - wb
+
+	return s.eval4Cell(xsb, ysb, zsb, wsb, xins, yins, zins, wins, dx0, dy0, dz0, dw0)
+}
+
+// eval4Cell is noise.eval4Cell in float32 arithmetic.
+func (s *noise32) eval4Cell(xsb, ysb, zsb, wsb int32, xins, yins, zins, wins, dx0, dy0, dz0, dw0 float32) float32 {
+	// Sum those together to get a value that determines which region we're in.
+	inSum := xins + yins + zins + wins
+
+	// We'll be defining these inside the next block and using them afterwards.
+	var dxExt0, dyExt0, dzExt0, dwExt0 float32
+	var dxExt1, dyExt1, dzExt1, dwExt1 float32
+	var dxExt2, dyExt2, dzExt2, dwExt2 float32
+	var xsvExt0, ysvExt0, zsvExt0, wsvExt0 int32
+	var xsvExt1, ysvExt1, zsvExt1, wsvExt1 int32
+	var xsvExt2, ysvExt2, zsvExt2, wsvExt2 int32
+
+	var value float32 = 0
+	if inSum <= 1 { // We're inside the pentachoron (4-Simplex) at (0,0,0,0)
+		// Determine which two of (0,0,0,1), (0,0,1,0), (0,1,0,0), (1,0,0,0) are closest.
+		var aPoint byte = 0x01
+		aScore := xins
+		var bPoint byte = 0x02
+		bScore := yins
+		if aScore >= bScore && zins > bScore {
+			bScore = zins
+			bPoint = 0x04
+		} else if aScore < bScore && zins > aScore {
+			aScore = zins
+			aPoint = 0x04
+		}
+		if aScore >= bScore && wins > bScore {
+			bScore = wins
+			bPoint = 0x08
+		} else if aScore < bScore && wins > aScore {
+			aScore = wins
+			aPoint = 0x08
+		}
+
+		// Now we determine the three lattice points not part of the pentachoron that may contribute.
+		// This depends on the closest two pentachoron vertices, including (0,0,0,0)
+		uins := 1 - inSum
+		if uins > aScore || uins > bScore { // (0,0,0,0) is one of the closest two pentachoron vertices.
+			var c byte
+			// Our other closest vertex is the closest out of a and b.
+			if bScore > aScore {
+				c = bPoint
+			} else {
+				c = aPoint
+			}
+			if (c & 0x01) == 0 {
+				xsvExt0 = xsb - 1
+				xsvExt2 = xsb
+				xsvExt1 = xsvExt2
+				dxExt0 = dx0 + 1
+				dxExt2 = dx0
+				dxExt1 = dxExt2
+			} else {
+				xsvExt2 = xsb + 1
+				xsvExt1 = xsvExt2
+				xsvExt0 = xsvExt1
+				dxExt2 = dx0 - 1
+				dxExt1 = dxExt2
+				dxExt0 = dxExt1
+			}
+
+			if (c & 0x02) == 0 {
+				ysvExt2 = ysb
+				ysvExt1 = ysvExt2
+				ysvExt0 = ysvExt1
+				dyExt2 = dy0
+				dyExt1 = dyExt2
+				dyExt0 = dyExt1
+				if (c & 0x01) == 0x01 {
+					ysvExt0 -= 1
+					dyExt0 += 1
+				} else {
+					ysvExt1 -= 1
+					dyExt1 += 1
+				}
+			} else {
+				ysvExt2 = ysb + 1
+				ysvExt1 = ysvExt2
+				ysvExt0 = ysvExt1
+				dyExt2 = dy0 - 1
+				dyExt1 = dyExt2
+				dyExt0 = dyExt1
+			}
+
+			if (c & 0x04) == 0 {
+				zsvExt2 = zsb
+				zsvExt1 = zsvExt2
+				zsvExt0 = zsvExt1
+				dzExt2 = dz0
+				dzExt1 = dzExt2
+				dzExt0 = dzExt1
+				if (c & 0x03) != 0 {
+					if (c & 0x03) == 0x03 {
+						zsvExt0 -= 1
+						dzExt0 += 1
+					} else {
+						zsvExt1 -= 1
+						dzExt1 += 1
+					}
+				} else {
+					zsvExt2 -= 1
+					dzExt2 += 1
+				}
+			} else {
+				zsvExt2 = zsb + 1
+				zsvExt1 = zsvExt2
+				zsvExt0 = zsvExt1
+				dzExt2 = dz0 - 1
+				dzExt1 = dzExt2
+				dzExt0 = dzExt1
+			}
+
+			if (c & 0x08) == 0 {
+				wsvExt1 = wsb
+				wsvExt0 = wsvExt1
+				wsvExt2 = wsb - 1
+				dwExt1 = dw0
+				dwExt0 = dwExt1
+				dwExt2 = dw0 + 1
+			} else {
+				wsvExt2 = wsb + 1
+				wsvExt1 = wsvExt2
+				wsvExt0 = wsvExt1
+				dwExt2 = dw0 - 1
+				dwExt1 = dwExt2
+				dwExt0 = dwExt1
+			}
+		} else { // (0,0,0,0) is not one of the closest two pentachoron vertices.
+			c := aPoint | bPoint // Our three extra vertices are determined by the closest two.
+
+			if (c & 0x01) == 0 {
+				xsvExt2 = xsb
+				xsvExt0 = xsvExt2
+				xsvExt1 = xsb - 1
+				dxExt0 = dx0 - 2*squishConstant4D32
+				dxExt1 = dx0 + 1 - squishConstant4D32
+				dxExt2 = dx0 - squishConstant4D32
+			} else {
+				xsvExt2 = xsb + 1
+				xsvExt1 = xsvExt2
+				xsvExt0 = xsvExt1
+				dxExt0 = dx0 - 1 - 2*squishConstant4D32
+				dxExt2 = dx0 - 1 - squishConstant4D32
+				dxExt1 = dxExt2
+			}
+
+			if (c & 0x02) == 0 {
+				ysvExt2 = ysb
+				ysvExt1 = ysvExt2
+				ysvExt0 = ysvExt1
+				dyExt0 = dy0 - 2*squishConstant4D32
+				dyExt2 = dy0 - squishConstant4D32
+				dyExt1 = dyExt2
+				if (c & 0x01) == 0x01 {
+					ysvExt1 -= 1
+					dyExt1 += 1
+				} else {
+					ysvExt2 -= 1
+					dyExt2 += 1
+				}
+			} else {
+				ysvExt2 = ysb + 1
+				ysvExt1 = ysvExt2
+				ysvExt0 = ysvExt1
+				dyExt0 = dy0 - 1 - 2*squishConstant4D32
+				dyExt2 = dy0 - 1 - squishConstant4D32
+				dyExt1 = dyExt2
+			}
+
+			if (c & 0x04) == 0 {
+				zsvExt2 = zsb
+				zsvExt1 = zsvExt2
+				zsvExt0 = zsvExt1
+				dzExt0 = dz0 - 2*squishConstant4D32
+				dzExt2 = dz0 - squishConstant4D32
+				dzExt1 = dzExt2
+				if (c & 0x03) == 0x03 {
+					zsvExt1 -= 1
+					dzExt1 += 1
+				} else {
+					zsvExt2 -= 1
+					dzExt2 += 1
+				}
+			} else {
+				zsvExt2 = zsb + 1
+				zsvExt1 = zsvExt2
+				zsvExt0 = zsvExt1
+				dzExt0 = dz0 - 1 - 2*squishConstant4D32
+				dzExt2 = dz0 - 1 - squishConstant4D32
+				dzExt1 = dzExt2
+			}
+
+			if (c & 0x08) == 0 {
+				wsvExt1 = wsb
+				wsvExt0 = wsvExt1
+				wsvExt2 = wsb - 1
+				dwExt0 = dw0 - 2*squishConstant4D32
+				dwExt1 = dw0 - squishConstant4D32
+				dwExt2 = dw0 + 1 - squishConstant4D32
+			} else {
+				wsvExt2 = wsb + 1
+				wsvExt1 = wsvExt2
+				wsvExt0 = wsvExt1
+				dwExt0 = dw0 - 1 - 2*squishConstant4D32
+				dwExt2 = dw0 - 1 - squishConstant4D32
+				dwExt1 = dwExt2
+			}
+		}
+
+		// Contribution (0,0,0,0)
+		attn0 := 2 - float32(dx0*dx0) - float32(dy0*dy0) - float32(dz0*dz0) - float32(dw0*dw0)
+		if attn0 > 0 {
+			value += s.contrib4(attn0, xsb+0, ysb+0, zsb+0, wsb+0, dx0, dy0, dz0, dw0)
+		}
+
+		// Contribution (1,0,0,0)
+		dx1 := dx0 - 1 - squishConstant4D32
+		dy1 := dy0 - 0 - squishConstant4D32
+		dz1 := dz0 - 0 - squishConstant4D32
+		dw1 := dw0 - 0 - squishConstant4D32
+		attn1 := 2 - float32(dx1*dx1) - float32(dy1*dy1) - float32(dz1*dz1) - float32(dw1*dw1)
+		if attn1 > 0 {
+			value += s.contrib4(attn1, xsb+1, ysb+0, zsb+0, wsb+0, dx1, dy1, dz1, dw1)
+		}
+
+		// Contribution (0,1,0,0)
+		dx2 := dx0 - 0 - squishConstant4D32
+		dy2 := dy0 - 1 - squishConstant4D32
+		dz2 := dz1
+		dw2 := dw1
+		attn2 := 2 - float32(dx2*dx2) - float32(dy2*dy2) - float32(dz2*dz2) - float32(dw2*dw2)
+		if attn2 > 0 {
+			value += s.contrib4(attn2, xsb+0, ysb+1, zsb+0, wsb+0, dx2, dy2, dz2, dw2)
+		}
+
+		// Contribution (0,0,1,0)
+		dx3 := dx2
+		dy3 := dy1
+		dz3 := dz0 - 1 - squishConstant4D32
+		dw3 := dw1
+		attn3 := 2 - float32(dx3*dx3) - float32(dy3*dy3) - float32(dz3*dz3) - float32(dw3*dw3)
+		if attn3 > 0 {
+			value += s.contrib4(attn3, xsb+0, ysb+0, zsb+1, wsb+0, dx3, dy3, dz3, dw3)
+		}
+
+		// Contribution (0,0,0,1)
+		dx4 := dx2
+		dy4 := dy1
+		dz4 := dz1
+		dw4 := dw0 - 1 - squishConstant4D32
+		attn4 := 2 - float32(dx4*dx4) - float32(dy4*dy4) - float32(dz4*dz4) - float32(dw4*dw4)
+		if attn4 > 0 {
+			value += s.contrib4(attn4, xsb+0, ysb+0, zsb+0, wsb+1, dx4, dy4, dz4, dw4)
+		}
+	} else if inSum >= 3 { // We're inside the pentachoron (4-Simplex) at (1,1,1,1)
+		// Determine which two of (1,1,1,0), (1,1,0,1), (1,0,1,1), (0,1,1,1) are closest.
+		var aPoint byte = 0x0E
+		aScore := xins
+		var bPoint byte = 0x0D
+		bScore := yins
+		if aScore <= bScore && zins < bScore {
+			bScore = zins
+			bPoint = 0x0B
+		} else if aScore > bScore && zins < aScore {
+			aScore = zins
+			aPoint = 0x0B
+		}
+		if aScore <= bScore && wins < bScore {
+			bScore = wins
+			bPoint = 0x07
+		} else if aScore > bScore && wins < aScore {
+			aScore = wins
+			aPoint = 0x07
+		}
+
+		// Now we determine the three lattice points not part of the pentachoron that may contribute.
+		// This depends on the closest two pentachoron vertices, including (0,0,0,0)
+		uins := 4 - inSum
+		if uins < aScore || uins < bScore { // (1,1,1,1) is one of the closest two pentachoron vertices.
+			var c byte
+			// Our other closest vertex is the closest out of a and b.
+			if bScore < aScore {
+				c = bPoint
+			} else {
+				c = aPoint
+			}
+
+			if (c & 0x01) != 0 {
+				xsvExt0 = xsb + 2
+				xsvExt2 = xsb + 1
+				xsvExt1 = xsvExt2
+				dxExt0 = dx0 - 2 - 4*squishConstant4D32
+				dxExt2 = dx0 - 1 - 4*squishConstant4D32
+				dxExt1 = dxExt2
+			} else {
+				xsvExt2 = xsb
+				xsvExt1 = xsvExt2
+				xsvExt0 = xsvExt1
+				dxExt2 = dx0 - 4*squishConstant4D32
+				dxExt1 = dxExt2
+				dxExt0 = dxExt1
+			}
+
+			if (c & 0x02) != 0 {
+				ysvExt2 = ysb + 1
+				ysvExt1 = ysvExt2
+				ysvExt0 = ysvExt1
+				dyExt2 = dy0 - 1 - 4*squishConstant4D32
+				dyExt1 = dyExt2
+				dyExt0 = dyExt1
+				if (c & 0x01) != 0 {
+					ysvExt1 += 1
+					dyExt1 -= 1
+				} else {
+					ysvExt0 += 1
+					dyExt0 -= 1
+				}
+			} else {
+				ysvExt2 = ysb
+				ysvExt1 = ysvExt2
+				ysvExt0 = ysvExt1
+				dyExt2 = dy0 - 4*squishConstant4D32
+				dyExt1 = dyExt2
+				dyExt0 = dyExt1
+			}
+
+			if (c & 0x04) != 0 {
+				zsvExt2 = zsb + 1
+				zsvExt1 = zsvExt2
+				zsvExt0 = zsvExt1
+				dzExt2 = dz0 - 1 - 4*squishConstant4D32
+				dzExt1 = dzExt2
+				dzExt0 = dzExt1
+				if (c & 0x03) != 0x03 {
+					if (c & 0x03) == 0 {
+						zsvExt0 += 1
+						dzExt0 -= 1
+					} else {
+						zsvExt1 += 1
+						dzExt1 -= 1
+					}
+				} else {
+					zsvExt2 += 1
+					dzExt2 -= 1
+				}
+			} else {
+				zsvExt2 = zsb
+				zsvExt1 = zsvExt2
+				zsvExt0 = zsvExt1
+				dzExt2 = dz0 - 4*squishConstant4D32
+				dzExt1 = dzExt2
+				dzExt0 = dzExt1
+			}
+
+			if (c & 0x08) != 0 {
+				wsvExt1 = wsb + 1
+				wsvExt0 = wsvExt1
+				wsvExt2 = wsb + 2
+				dwExt1 = dw0 - 1 - 4*squishConstant4D32
+				dwExt0 = dwExt1
+				dwExt2 = dw0 - 2 - 4*squishConstant4D32
+			} else {
+				wsvExt2 = wsb
+				wsvExt1 = wsvExt2
+				wsvExt0 = wsvExt1
+				dwExt2 = dw0 - 4*squishConstant4D32
+				dwExt1 = dwExt2
+				dwExt0 = dwExt1
+			}
+		} else { // (1,1,1,1) is not one of the closest two pentachoron vertices.
+			c := aPoint & bPoint // Our three extra vertices are determined by the closest two.
+
+			if (c & 0x01) != 0 {
+				xsvExt2 = xsb + 1
+				xsvExt0 = xsvExt2
+				xsvExt1 = xsb + 2
+				dxExt0 = dx0 - 1 - 2*squishConstant4D32
+				dxExt1 = dx0 - 2 - 3*squishConstant4D32
+				dxExt2 = dx0 - 1 - 3*squishConstant4D32
+			} else {
+				xsvExt2 = xsb
+				xsvExt1 = xsvExt2
+				xsvExt0 = xsvExt1
+				dxExt0 = dx0 - 2*squishConstant4D32
+				dxExt2 = dx0 - 3*squishConstant4D32
+				dxExt1 = dxExt2
+			}
+
+			if (c & 0x02) != 0 {
+				ysvExt2 = ysb + 1
+				ysvExt1 = ysvExt2
+				ysvExt0 = ysvExt1
+				dyExt0 = dy0 - 1 - 2*squishConstant4D32
+				dyExt2 = dy0 - 1 - 3*squishConstant4D32
+				dyExt1 = dyExt2
+				if (c & 0x01) != 0 {
+					ysvExt2 += 1
+					dyExt2 -= 1
+				} else {
+					ysvExt1 += 1
+					dyExt1 -= 1
+				}
+			} else {
+				ysvExt2 = ysb
+				ysvExt1 = ysvExt2
+				ysvExt0 = ysvExt1
+				dyExt0 = dy0 - 2*squishConstant4D32
+				dyExt2 = dy0 - 3*squishConstant4D32
+				dyExt1 = dyExt2
+			}
+
+			if (c & 0x04) != 0 {
+				zsvExt2 = zsb + 1
+				zsvExt1 = zsvExt2
+				zsvExt0 = zsvExt1
+				dzExt0 = dz0 - 1 - 2*squishConstant4D32
+				dzExt2 = dz0 - 1 - 3*squishConstant4D32
+				dzExt1 = dzExt2
+				if (c & 0x03) != 0 {
+					zsvExt2 += 1
+					dzExt2 -= 1
+				} else {
+					zsvExt1 += 1
+					dzExt1 -= 1
+				}
+			} else {
+				zsvExt2 = zsb
+				zsvExt1 = zsvExt2
+				zsvExt0 = zsvExt1
+				dzExt0 = dz0 - 2*squishConstant4D32
+				dzExt2 = dz0 - 3*squishConstant4D32
+				dzExt1 = dzExt2
+			}
+
+			if (c & 0x08) != 0 {
+				wsvExt1 = wsb + 1
+				wsvExt0 = wsvExt1
+				wsvExt2 = wsb + 2
+				dwExt0 = dw0 - 1 - 2*squishConstant4D32
+				dwExt1 = dw0 - 1 - 3*squishConstant4D32
+				dwExt2 = dw0 - 2 - 3*squishConstant4D32
+			} else {
+				wsvExt2 = wsb
+				wsvExt1 = wsvExt2
+				wsvExt0 = wsvExt1
+				dwExt0 = dw0 - 2*squishConstant4D32
+				dwExt2 = dw0 - 3*squishConstant4D32
+				dwExt1 = dwExt2
+			}
+		}
+
+		// Contribution (1,1,1,0)
+		dx4 := dx0 - 1 - 3*squishConstant4D32
+		dy4 := dy0 - 1 - 3*squishConstant4D32
+		dz4 := dz0 - 1 - 3*squishConstant4D32
+		dw4 := dw0 - 3*squishConstant4D32
+		attn4 := 2 - float32(dx4*dx4) - float32(dy4*dy4) - float32(dz4*dz4) - float32(dw4*dw4)
+		if attn4 > 0 {
+			value += s.contrib4(attn4, xsb+1, ysb+1, zsb+1, wsb+0, dx4, dy4, dz4, dw4)
+		}
+
+		// Contribution (1,1,0,1)
+		dx3 := dx4
+		dy3 := dy4
+		dz3 := dz0 - 3*squishConstant4D32
+		dw3 := dw0 - 1 - 3*squishConstant4D32
+		attn3 := 2 - float32(dx3*dx3) - float32(dy3*dy3) - float32(dz3*dz3) - float32(dw3*dw3)
+		if attn3 > 0 {
+			value += s.contrib4(attn3, xsb+1, ysb+1, zsb+0, wsb+1, dx3, dy3, dz3, dw3)
+		}
+
+		// Contribution (1,0,1,1)
+		dx2 := dx4
+		dy2 := dy0 - 3*squishConstant4D32
+		dz2 := dz4
+		dw2 := dw3
+		attn2 := 2 - float32(dx2*dx2) - float32(dy2*dy2) - float32(dz2*dz2) - float32(dw2*dw2)
+		if attn2 > 0 {
+			value += s.contrib4(attn2, xsb+1, ysb+0, zsb+1, wsb+1, dx2, dy2, dz2, dw2)
+		}
+
+		// Contribution (0,1,1,1)
+		dx1 := dx0 - 3*squishConstant4D32
+		dz1 := dz4
+		dy1 := dy4
+		dw1 := dw3
+		attn1 := 2 - float32(dx1*dx1) - float32(dy1*dy1) - float32(dz1*dz1) - float32(dw1*dw1)
+		if attn1 > 0 {
+			value += s.contrib4(attn1, xsb+0, ysb+1, zsb+1, wsb+1, dx1, dy1, dz1, dw1)
+		}
+
+		// Contribution (1,1,1,1)
+		dx0 = dx0 - 1 - 4*squishConstant4D32
+		dy0 = dy0 - 1 - 4*squishConstant4D32
+		dz0 = dz0 - 1 - 4*squishConstant4D32
+		dw0 = dw0 - 1 - 4*squishConstant4D32
+		attn0 := 2 - float32(dx0*dx0) - float32(dy0*dy0) - float32(dz0*dz0) - float32(dw0*dw0)
+		if attn0 > 0 {
+			value += s.contrib4(attn0, xsb+1, ysb+1, zsb+1, wsb+1, dx0, dy0, dz0, dw0)
+		}
+	} else if inSum <= 2 { // We're inside the first dispentachoron (Rectified 4-Simplex)
+		var aScore, bScore float32
+		var aPoint, bPoint byte
+
+		aIsBiggerSide := true
+		bIsBiggerSide := true
+
+		// Decide between (1,1,0,0) and (0,0,1,1)
+		if xins+yins > zins+wins {
+			aScore = xins + yins
+			aPoint = 0x03
+		} else {
+			aScore = zins + wins
+			aPoint = 0x0C
+		}
+
+		// Decide between (1,0,1,0) and (0,1,0,1)
+		if xins+zins > yins+wins {
+			bScore = xins + zins
+			bPoint = 0x05
+		} else {
+			bScore = yins + wins
+			bPoint = 0x0A
+		}
+
+		// Closer between (1,0,0,1) and (0,1,1,0) will replace the further of a and b, if closer.
+		if xins+wins > yins+zins {
+			score := xins + wins
+			if aScore >= bScore && score > bScore {
+				bScore = score
+				bPoint = 0x09
+			} else if aScore < bScore && score > aScore {
+				aScore = score
+				aPoint = 0x09
+			}
+		} else {
+			score := yins + zins
+			if aScore >= bScore && score > bScore {
+				bScore = score
+				bPoint = 0x06
+			} else if aScore < bScore && score > aScore {
+				aScore = score
+				aPoint = 0x06
+			}
+		}
+
+		// Decide if (1,0,0,0) is closer.
+		p1 := 2 - inSum + xins
+		if aScore >= bScore && p1 > bScore {
+			bScore = p1
+			bPoint = 0x01
+			bIsBiggerSide = false
+		} else if aScore < bScore && p1 > aScore {
+			aScore = p1
+			aPoint = 0x01
+			aIsBiggerSide = false
+		}
+
+		// Decide if (0,1,0,0) is closer.
+		p2 := 2 - inSum + yins
+		if aScore >= bScore && p2 > bScore {
+			bScore = p2
+			bPoint = 0x02
+			bIsBiggerSide = false
+		} else if aScore < bScore && p2 > aScore {
+			aScore = p2
+			aPoint = 0x02
+			aIsBiggerSide = false
+		}
+
+		// Decide if (0,0,1,0) is closer.
+		p3 := 2 - inSum + zins
+		if aScore >= bScore && p3 > bScore {
+			bScore = p3
+			bPoint = 0x04
+			bIsBiggerSide = false
+		} else if aScore < bScore && p3 > aScore {
+			aScore = p3
+			aPoint = 0x04
+			aIsBiggerSide = false
+		}
+
+		// Decide if (0,0,0,1) is closer.
+		p4 := 2 - inSum + wins
+		if aScore >= bScore && p4 > bScore {
+			bPoint = 0x08
+			bIsBiggerSide = false
+		} else if aScore < bScore && p4 > aScore {
+			aPoint = 0x08
+			aIsBiggerSide = false
+		}
+
+		// Where each of the two closest points are determines how the extra three vertices are calculated.
+		if aIsBiggerSide == bIsBiggerSide {
+			if aIsBiggerSide { // Both closest points on the bigger side
+				c1 := aPoint | bPoint
+				c2 := aPoint & bPoint
+				if (c1 & 0x01) == 0 {
+					xsvExt0 = xsb
+					xsvExt1 = xsb - 1
+					dxExt0 = dx0 - 3*squishConstant4D32
+					dxExt1 = dx0 + 1 - 2*squishConstant4D32
+				} else {
+					xsvExt1 = xsb + 1
+					xsvExt0 = xsvExt1
+					dxExt0 = dx0 - 1 - 3*squishConstant4D32
+					dxExt1 = dx0 - 1 - 2*squishConstant4D32
+				}
+
+				if (c1 & 0x02) == 0 {
+					ysvExt0 = ysb
+					ysvExt1 = ysb - 1
+					dyExt0 = dy0 - 3*squishConstant4D32
+					dyExt1 = dy0 + 1 - 2*squishConstant4D32
+				} else {
+					ysvExt1 = ysb + 1
+					ysvExt0 = ysvExt1
+					dyExt0 = dy0 - 1 - 3*squishConstant4D32
+					dyExt1 = dy0 - 1 - 2*squishConstant4D32
+				}
+
+				if (c1 & 0x04) == 0 {
+					zsvExt0 = zsb
+					zsvExt1 = zsb - 1
+					dzExt0 = dz0 - 3*squishConstant4D32
+					dzExt1 = dz0 + 1 - 2*squishConstant4D32
+				} else {
+					zsvExt1 = zsb + 1
+					zsvExt0 = zsvExt1
+					dzExt0 = dz0 - 1 - 3*squishConstant4D32
+					dzExt1 = dz0 - 1 - 2*squishConstant4D32
+				}
+
+				if (c1 & 0x08) == 0 {
+					wsvExt0 = wsb
+					wsvExt1 = wsb - 1
+					dwExt0 = dw0 - 3*squishConstant4D32
+					dwExt1 = dw0 + 1 - 2*squishConstant4D32
+				} else {
+					wsvExt1 = wsb + 1
+					wsvExt0 = wsvExt1
+					dwExt0 = dw0 - 1 - 3*squishConstant4D32
+					dwExt1 = dw0 - 1 - 2*squishConstant4D32
+				}
+
+				// One combination is a permutation of (0,0,0,2) based on c2
+				xsvExt2 = xsb
+				ysvExt2 = ysb
+				zsvExt2 = zsb
+				wsvExt2 = wsb
+				dxExt2 = dx0 - 2*squishConstant4D32
+				dyExt2 = dy0 - 2*squishConstant4D32
+				dzExt2 = dz0 - 2*squishConstant4D32
+				dwExt2 = dw0 - 2*squishConstant4D32
+				if (c2 & 0x01) != 0 {
+					xsvExt2 += 2
+					dxExt2 -= 2
+				} else if (c2 & 0x02) != 0 {
+					ysvExt2 += 2
+					dyExt2 -= 2
+				} else if (c2 & 0x04) != 0 {
+					zsvExt2 += 2
+					dzExt2 -= 2
+				} else {
+					wsvExt2 += 2
+					dwExt2 -= 2
+				}
+
+			} else { // Both closest points on the smaller side
+				// One of the two extra points is (0,0,0,0)
+				xsvExt2 = xsb
+				ysvExt2 = ysb
+				zsvExt2 = zsb
+				wsvExt2 = wsb
+				dxExt2 = dx0
+				dyExt2 = dy0
+				dzExt2 = dz0
+				dwExt2 = dw0
+
+				// Other two points are based on the omitted axes.
+				c := aPoint | bPoint
+
+				if (c & 0x01) == 0 {
+					xsvExt0 = xsb - 1
+					xsvExt1 = xsb
+					dxExt0 = dx0 + 1 - squishConstant4D32
+					dxExt1 = dx0 - squishConstant4D32
+				} else {
+					xsvExt1 = xsb + 1
+					xsvExt0 = xsvExt1
+					dxExt1 = dx0 - 1 - squishConstant4D32
+					dxExt0 = dxExt1
+				}
+
+				if (c & 0x02) == 0 {
+					ysvExt1 = ysb
+					ysvExt0 = ysvExt1
+					dyExt1 = dy0 - squishConstant4D32
+					dyExt0 = dyExt1
+					if (c & 0x01) == 0x01 {
+						ysvExt0 -= 1
+						dyExt0 += 1
+					} else {
+						ysvExt1 -= 1
+						dyExt1 += 1
+					}
+				} else {
+					ysvExt1 = ysb + 1
+					ysvExt0 = ysvExt1
+					dyExt1 = dy0 - 1 - squishConstant4D32
+					dyExt0 = dyExt1
+				}
+
+				if (c & 0x04) == 0 {
+					zsvExt1 = zsb
+					zsvExt0 = zsvExt1
+					dzExt1 = dz0 - squishConstant4D32
+					dzExt0 = dzExt1
+					if (c & 0x03) == 0x03 {
+						zsvExt0 -= 1
+						dzExt0 += 1
+					} else {
+						zsvExt1 -= 1
+						dzExt1 += 1
+					}
+				} else {
+					zsvExt1 = zsb + 1
+					zsvExt0 = zsvExt1
+					dzExt1 = dz0 - 1 - squishConstant4D32
+					dzExt0 = dzExt1
+				}
+
+				if (c & 0x08) == 0 {
+					wsvExt0 = wsb
+					wsvExt1 = wsb - 1
+					dwExt0 = dw0 - squishConstant4D32
+					dwExt1 = dw0 + 1 - squishConstant4D32
+				} else {
+					wsvExt1 = wsb + 1
+					wsvExt0 = wsvExt1
+					dwExt1 = dw0 - 1 - squishConstant4D32
+					dwExt0 = dwExt1
+				}
+
+			}
+		} else { // One point on each "side"
+			var c1, c2 byte
+			if aIsBiggerSide {
+				c1 = aPoint
+				c2 = bPoint
+			} else {
+				c1 = bPoint
+				c2 = aPoint
+			}
+
+			// Two contributions are the bigger-sided point with each 0 replaced with -1.
+			if (c1 & 0x01) == 0 {
+				xsvExt0 = xsb - 1
+				xsvExt1 = xsb
+				dxExt0 = dx0 + 1 - squishConstant4D32
+				dxExt1 = dx0 - squishConstant4D32
+			} else {
+				xsvExt1 = xsb + 1
+				xsvExt0 = xsvExt1
+				dxExt1 = dx0 - 1 - squishConstant4D32
+				dxExt0 = dxExt1
+			}
+
+			if (c1 & 0x02) == 0 {
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - squishConstant4D32
+				dyExt0 = dyExt1
+				if (c1 & 0x01) == 0x01 {
+					ysvExt0 -= 1
+					dyExt0 += 1
+				} else {
+					ysvExt1 -= 1
+					dyExt1 += 1
+				}
+			} else {
+				ysvExt1 = ysb + 1
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - 1 - squishConstant4D32
+				dyExt0 = dyExt1
+			}
+
+			if (c1 & 0x04) == 0 {
+				zsvExt1 = zsb
+				zsvExt0 = zsvExt1
+				dzExt1 = dz0 - squishConstant4D32
+				dzExt0 = dzExt1
+				if (c1 & 0x03) == 0x03 {
+					zsvExt0 -= 1
+					dzExt0 += 1
+				} else {
+					zsvExt1 -= 1
+					dzExt1 += 1
+				}
+			} else {
+				zsvExt1 = zsb + 1
+				zsvExt0 = zsvExt1
+				dzExt1 = dz0 - 1 - squishConstant4D32
+				dzExt0 = dzExt1
+			}
+
+			if (c1 & 0x08) == 0 {
+				wsvExt0 = wsb
+				wsvExt1 = wsb - 1
+				dwExt0 = dw0 - squishConstant4D32
+				dwExt1 = dw0 + 1 - squishConstant4D32
+			} else {
+				wsvExt1 = wsb + 1
+				wsvExt0 = wsvExt1
+				dwExt1 = dw0 - 1 - squishConstant4D32
+				dwExt0 = dwExt1
+			}
+
+			// One contribution is a permutation of (0,0,0,2) based on the smaller-sided point
+			xsvExt2 = xsb
+			ysvExt2 = ysb
+			zsvExt2 = zsb
+			wsvExt2 = wsb
+			dxExt2 = dx0 - 2*squishConstant4D32
+			dyExt2 = dy0 - 2*squishConstant4D32
+			dzExt2 = dz0 - 2*squishConstant4D32
+			dwExt2 = dw0 - 2*squishConstant4D32
+			if (c2 & 0x01) != 0 {
+				xsvExt2 += 2
+				dxExt2 -= 2
+			} else if (c2 & 0x02) != 0 {
+				ysvExt2 += 2
+				dyExt2 -= 2
+			} else if (c2 & 0x04) != 0 {
+				zsvExt2 += 2
+				dzExt2 -= 2
+			} else {
+				wsvExt2 += 2
+				dwExt2 -= 2
+			}
+		}
+
+		// Contribution (1,0,0,0)
+		dx1 := dx0 - 1 - squishConstant4D32
+		dy1 := dy0 - 0 - squishConstant4D32
+		dz1 := dz0 - 0 - squishConstant4D32
+		dw1 := dw0 - 0 - squishConstant4D32
+		attn1 := 2 - float32(dx1*dx1) - float32(dy1*dy1) - float32(dz1*dz1) - float32(dw1*dw1)
+		if attn1 > 0 {
+			value += s.contrib4(attn1, xsb+1, ysb+0, zsb+0, wsb+0, dx1, dy1, dz1, dw1)
+		}
+
+		// Contribution (0,1,0,0)
+		dx2 := dx0 - 0 - squishConstant4D32
+		dy2 := dy0 - 1 - squishConstant4D32
+		dz2 := dz1
+		dw2 := dw1
+		attn2 := 2 - float32(dx2*dx2) - float32(dy2*dy2) - float32(dz2*dz2) - float32(dw2*dw2)
+		if attn2 > 0 {
+			value += s.contrib4(attn2, xsb+0, ysb+1, zsb+0, wsb+0, dx2, dy2, dz2, dw2)
+		}
+
+		// Contribution (0,0,1,0)
+		dx3 := dx2
+		dy3 := dy1
+		dz3 := dz0 - 1 - squishConstant4D32
+		dw3 := dw1
+		attn3 := 2 - float32(dx3*dx3) - float32(dy3*dy3) - float32(dz3*dz3) - float32(dw3*dw3)
+		if attn3 > 0 {
+			value += s.contrib4(attn3, xsb+0, ysb+0, zsb+1, wsb+0, dx3, dy3, dz3, dw3)
+		}
+
+		// Contribution (0,0,0,1)
+		dx4 := dx2
+		dy4 := dy1
+		dz4 := dz1
+		dw4 := dw0 - 1 - squishConstant4D32
+		attn4 := 2 - float32(dx4*dx4) - float32(dy4*dy4) - float32(dz4*dz4) - float32(dw4*dw4)
+		if attn4 > 0 {
+			value += s.contrib4(attn4, xsb+0, ysb+0, zsb+0, wsb+1, dx4, dy4, dz4, dw4)
+		}
+
+		// Contribution (1,1,0,0)
+		dx5 := dx0 - 1 - 2*squishConstant4D32
+		dy5 := dy0 - 1 - 2*squishConstant4D32
+		dz5 := dz0 - 0 - 2*squishConstant4D32
+		dw5 := dw0 - 0 - 2*squishConstant4D32
+		attn5 := 2 - float32(dx5*dx5) - float32(dy5*dy5) - float32(dz5*dz5) - float32(dw5*dw5)
+		if attn5 > 0 {
+			value += s.contrib4(attn5, xsb+1, ysb+1, zsb+0, wsb+0, dx5, dy5, dz5, dw5)
+		}
+
+		// Contribution (1,0,1,0)
+		dx6 := dx0 - 1 - 2*squishConstant4D32
+		dy6 := dy0 - 0 - 2*squishConstant4D32
+		dz6 := dz0 - 1 - 2*squishConstant4D32
+		dw6 := dw0 - 0 - 2*squishConstant4D32
+		attn6 := 2 - float32(dx6*dx6) - float32(dy6*dy6) - float32(dz6*dz6) - float32(dw6*dw6)
+		if attn6 > 0 {
+			value += s.contrib4(attn6, xsb+1, ysb+0, zsb+1, wsb+0, dx6, dy6, dz6, dw6)
+		}
+
+		// Contribution (1,0,0,1)
+		dx7 := dx0 - 1 - 2*squishConstant4D32
+		dy7 := dy0 - 0 - 2*squishConstant4D32
+		dz7 := dz0 - 0 - 2*squishConstant4D32
+		dw7 := dw0 - 1 - 2*squishConstant4D32
+		attn7 := 2 - float32(dx7*dx7) - float32(dy7*dy7) - float32(dz7*dz7) - float32(dw7*dw7)
+		if attn7 > 0 {
+			value += s.contrib4(attn7, xsb+1, ysb+0, zsb+0, wsb+1, dx7, dy7, dz7, dw7)
+		}
+
+		// Contribution (0,1,1,0)
+		dx8 := dx0 - 0 - 2*squishConstant4D32
+		dy8 := dy0 - 1 - 2*squishConstant4D32
+		dz8 := dz0 - 1 - 2*squishConstant4D32
+		dw8 := dw0 - 0 - 2*squishConstant4D32
+		attn8 := 2 - float32(dx8*dx8) - float32(dy8*dy8) - float32(dz8*dz8) - float32(dw8*dw8)
+		if attn8 > 0 {
+			value += s.contrib4(attn8, xsb+0, ysb+1, zsb+1, wsb+0, dx8, dy8, dz8, dw8)
+		}
+
+		// Contribution (0,1,0,1)
+		dx9 := dx0 - 0 - 2*squishConstant4D32
+		dy9 := dy0 - 1 - 2*squishConstant4D32
+		dz9 := dz0 - 0 - 2*squishConstant4D32
+		dw9 := dw0 - 1 - 2*squishConstant4D32
+		attn9 := 2 - float32(dx9*dx9) - float32(dy9*dy9) - float32(dz9*dz9) - float32(dw9*dw9)
+		if attn9 > 0 {
+			value += s.contrib4(attn9, xsb+0, ysb+1, zsb+0, wsb+1, dx9, dy9, dz9, dw9)
+		}
+
+		// Contribution (0,0,1,1)
+		dx10 := dx0 - 0 - 2*squishConstant4D32
+		dy10 := dy0 - 0 - 2*squishConstant4D32
+		dz10 := dz0 - 1 - 2*squishConstant4D32
+		dw10 := dw0 - 1 - 2*squishConstant4D32
+		attn10 := 2 - float32(dx10*dx10) - float32(dy10*dy10) - float32(dz10*dz10) - float32(dw10*dw10)
+		if attn10 > 0 {
+			value += s.contrib4(attn10, xsb+0, ysb+0, zsb+1, wsb+1, dx10, dy10, dz10, dw10)
+		}
+	} else { // We're inside the second dispentachoron (Rectified 4-Simplex)
+		var aScore, bScore float32
+		var aPoint, bPoint byte
+
+		aIsBiggerSide := true
+		bIsBiggerSide := true
+
+		// Decide between (0,0,1,1) and (1,1,0,0)
+		if xins+yins < zins+wins {
+			aScore = xins + yins
+			aPoint = 0x0C
+		} else {
+			aScore = zins + wins
+			aPoint = 0x03
+		}
+
+		// Decide between (0,1,0,1) and (1,0,1,0)
+		if xins+zins < yins+wins {
+			bScore = xins + zins
+			bPoint = 0x0A
+		} else {
+			bScore = yins + wins
+			bPoint = 0x05
+		}
+
+		// Closer between (0,1,1,0) and (1,0,0,1) will replace the further of a and b, if closer.
+		if xins+wins < yins+zins {
+			score := xins + wins
+			if aScore <= bScore && score < bScore {
+				bScore = score
+				bPoint = 0x06
+			} else if aScore > bScore && score < aScore {
+				aScore = score
+				aPoint = 0x06
+			}
+		} else {
+			score := yins + zins
+			if aScore <= bScore && score < bScore {
+				bScore = score
+				bPoint = 0x09
+			} else if aScore > bScore && score < aScore {
+				aScore = score
+				aPoint = 0x09
+			}
+		}
+
+		// Decide if (0,1,1,1) is closer.
+		p1 := 3 - inSum + xins
+		if aScore <= bScore && p1 < bScore {
+			bScore = p1
+			bPoint = 0x0E
+			bIsBiggerSide = false
+		} else if aScore > bScore && p1 < aScore {
+			aScore = p1
+			aPoint = 0x0E
+			aIsBiggerSide = false
+		}
+
+		// Decide if (1,0,1,1) is closer.
+		p2 := 3 - inSum + yins
+		if aScore <= bScore && p2 < bScore {
+			bScore = p2
+			bPoint = 0x0D
+			bIsBiggerSide = false
+		} else if aScore > bScore && p2 < aScore {
+			aScore = p2
+			aPoint = 0x0D
+			aIsBiggerSide = false
+		}
+
+		// Decide if (1,1,0,1) is closer.
+		p3 := 3 - inSum + zins
+		if aScore <= bScore && p3 < bScore {
+			bScore = p3
+			bPoint = 0x0B
+			bIsBiggerSide = false
+		} else if aScore > bScore && p3 < aScore {
+			aScore = p3
+			aPoint = 0x0B
+			aIsBiggerSide = false
+		}
+
+		// Decide if (1,1,1,0) is closer.
+		p4 := 3 - inSum + wins
+		if aScore <= bScore && p4 < bScore {
+			bPoint = 0x07
+			bIsBiggerSide = false
+		} else if aScore > bScore && p4 < aScore {
+			aPoint = 0x07
+			aIsBiggerSide = false
+		}
+
+		// Where each of the two closest points are determines how the extra three vertices are calculated.
+		if aIsBiggerSide == bIsBiggerSide {
+			if aIsBiggerSide { // Both closest points on the bigger side
+				c1 := aPoint & bPoint
+				c2 := aPoint | bPoint
+
+				// Two contributions are permutations of (0,0,0,1) and (0,0,0,2) based on c1
+				xsvExt1 = xsb
+				xsvExt0 = xsvExt1
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				zsvExt1 = zsb
+				zsvExt0 = zsvExt1
+				wsvExt1 = wsb
+				wsvExt0 = wsvExt1
+				dxExt0 = dx0 - squishConstant4D32
+				dyExt0 = dy0 - squishConstant4D32
+				dzExt0 = dz0 - squishConstant4D32
+				dwExt0 = dw0 - squishConstant4D32
+				dxExt1 = dx0 - 2*squishConstant4D32
+				dyExt1 = dy0 - 2*squishConstant4D32
+				dzExt1 = dz0 - 2*squishConstant4D32
+				dwExt1 = dw0 - 2*squishConstant4D32
+				if (c1 & 0x01) != 0 {
+					xsvExt0 += 1
+					dxExt0 -= 1
+					xsvExt1 += 2
+					dxExt1 -= 2
+				} else if (c1 & 0x02) != 0 {
+					ysvExt0 += 1
+					dyExt0 -= 1
+					ysvExt1 += 2
+					dyExt1 -= 2
+				} else if (c1 & 0x04) != 0 {
+					zsvExt0 += 1
+					dzExt0 -= 1
+					zsvExt1 += 2
+					dzExt1 -= 2
+				} else {
+					wsvExt0 += 1
+					dwExt0 -= 1
+					wsvExt1 += 2
+					dwExt1 -= 2
+				}
+
+				// One contribution is a permutation of (1,1,1,-1) based on c2
+				xsvExt2 = xsb + 1
+				ysvExt2 = ysb + 1
+				zsvExt2 = zsb + 1
+				wsvExt2 = wsb + 1
+				dxExt2 = dx0 - 1 - 2*squishConstant4D32
+				dyExt2 = dy0 - 1 - 2*squishConstant4D32
+				dzExt2 = dz0 - 1 - 2*squishConstant4D32
+				dwExt2 = dw0 - 1 - 2*squishConstant4D32
+				if (c2 & 0x01) == 0 {
+					xsvExt2 -= 2
+					dxExt2 += 2
+				} else if (c2 & 0x02) == 0 {
+					ysvExt2 -= 2
+					dyExt2 += 2
+				} else if (c2 & 0x04) == 0 {
+					zsvExt2 -= 2
+					dzExt2 += 2
+				} else {
+					wsvExt2 -= 2
+					dwExt2 += 2
+				}
+			} else { // Both closest points on the smaller side
+				// One of the two extra points is (1,1,1,1)
+				xsvExt2 = xsb + 1
+				ysvExt2 = ysb + 1
+				zsvExt2 = zsb + 1
+				wsvExt2 = wsb + 1
+				dxExt2 = dx0 - 1 - 4*squishConstant4D32
+				dyExt2 = dy0 - 1 - 4*squishConstant4D32
+				dzExt2 = dz0 - 1 - 4*squishConstant4D32
+				dwExt2 = dw0 - 1 - 4*squishConstant4D32
+
+				// Other two points are based on the shared axes.
+				c := aPoint & bPoint
+
+				if (c & 0x01) != 0 {
+					xsvExt0 = xsb + 2
+					xsvExt1 = xsb + 1
+					dxExt0 = dx0 - 2 - 3*squishConstant4D32
+					dxExt1 = dx0 - 1 - 3*squishConstant4D32
+				} else {
+					xsvExt1 = xsb
+					xsvExt0 = xsvExt1
+					dxExt1 = dx0 - 3*squishConstant4D32
+					dxExt0 = dxExt1
+				}
+
+				if (c & 0x02) != 0 {
+					ysvExt1 = ysb + 1
+					ysvExt0 = ysvExt1
+					dyExt1 = dy0 - 1 - 3*squishConstant4D32
+					dyExt0 = dyExt1
+					if (c & 0x01) == 0 {
+						ysvExt0 += 1
+						dyExt0 -= 1
+					} else {
+						ysvExt1 += 1
+						dyExt1 -= 1
+					}
+				} else {
+					ysvExt1 = ysb
+					ysvExt0 = ysvExt1
+					dyExt1 = dy0 - 3*squishConstant4D32
+					dyExt0 = dyExt1
+				}
+
+				if (c & 0x04) != 0 {
+					zsvExt1 = zsb + 1
+					zsvExt0 = zsvExt1
+					dzExt1 = dz0 - 1 - 3*squishConstant4D32
+					dzExt0 = dzExt1
+					if (c & 0x03) == 0 {
+						zsvExt0 += 1
+						dzExt0 -= 1
+					} else {
+						zsvExt1 += 1
+						dzExt1 -= 1
+					}
+				} else {
+					zsvExt1 = zsb
+					zsvExt0 = zsvExt1
+					dzExt1 = dz0 - 3*squishConstant4D32
+					dzExt0 = dzExt1
+				}
+
+				if (c & 0x08) != 0 {
+					wsvExt0 = wsb + 1
+					wsvExt1 = wsb + 2
+					dwExt0 = dw0 - 1 - 3*squishConstant4D32
+					dwExt1 = dw0 - 2 - 3*squishConstant4D32
+				} else {
+					wsvExt1 = wsb
+					wsvExt0 = wsvExt1
+					dwExt1 = dw0 - 3*squishConstant4D32
+					dwExt0 = dwExt1
+				}
+			}
+		} else { // One point on each "side"
+			var c1, c2 byte
+			if aIsBiggerSide {
+				c1 = aPoint
+				c2 = bPoint
+			} else {
+				c1 = bPoint
+				c2 = aPoint
+			}
+
+			// Two contributions are the bigger-sided point with each 1 replaced with 2.
+			if (c1 & 0x01) != 0 {
+				xsvExt0 = xsb + 2
+				xsvExt1 = xsb + 1
+				dxExt0 = dx0 - 2 - 3*squishConstant4D32
+				dxExt1 = dx0 - 1 - 3*squishConstant4D32
+			} else {
+				xsvExt1 = xsb
+				xsvExt0 = xsvExt1
+				dxExt1 = dx0 - 3*squishConstant4D32
+				dxExt0 = dxExt1
+			}
+
+			if (c1 & 0x02) != 0 {
+				ysvExt1 = ysb + 1
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - 1 - 3*squishConstant4D32
+				dyExt0 = dyExt1
+				if (c1 & 0x01) == 0 {
+					ysvExt0 += 1
+					dyExt0 -= 1
+				} else {
+					ysvExt1 += 1
+					dyExt1 -= 1
+				}
+			} else {
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - 3*squishConstant4D32
+				dyExt0 = dyExt1
+			}
+
+			if (c1 & 0x04) != 0 {
+				zsvExt1 = zsb + 1
+				zsvExt0 = zsvExt1
+				dzExt1 = dz0 - 1 - 3*squishConstant4D32
+				dzExt0 = dzExt1
+				if (c1 & 0x03) == 0 {
+					zsvExt0 += 1
+					dzExt0 -= 1
+				} else {
+					zsvExt1 += 1
+					dzExt1 -= 1
+				}
+			} else {
+				zsvExt1 = zsb
+				zsvExt0 = zsvExt1
+				dzExt1 = dz0 - 3*squishConstant4D32
+				dzExt0 = dzExt1
+			}
+
+			if (c1 & 0x08) != 0 {
+				wsvExt0 = wsb + 1
+				wsvExt1 = wsb + 2
+				dwExt0 = dw0 - 1 - 3*squishConstant4D32
+				dwExt1 = dw0 - 2 - 3*squishConstant4D32
+			} else {
+				wsvExt1 = wsb
+				wsvExt0 = wsvExt1
+				dwExt1 = dw0 - 3*squishConstant4D32
+				dwExt0 = dwExt1
+			}
+
+			// One contribution is a permutation of (1,1,1,-1) based on the smaller-sided point
+			xsvExt2 = xsb + 1
+			ysvExt2 = ysb + 1
+			zsvExt2 = zsb + 1
+			wsvExt2 = wsb + 1
+			dxExt2 = dx0 - 1 - 2*squishConstant4D32
+			dyExt2 = dy0 - 1 - 2*squishConstant4D32
+			dzExt2 = dz0 - 1 - 2*squishConstant4D32
+			dwExt2 = dw0 - 1 - 2*squishConstant4D32
+			if (c2 & 0x01) == 0 {
+				xsvExt2 -= 2
+				dxExt2 += 2
+			} else if (c2 & 0x02) == 0 {
+				ysvExt2 -= 2
+				dyExt2 += 2
+			} else if (c2 & 0x04) == 0 {
+				zsvExt2 -= 2
+				dzExt2 += 2
+			} else {
+				wsvExt2 -= 2
+				dwExt2 += 2
+			}
+		}
+
+		// Contribution (1,1,1,0)
+		dx4 := dx0 - 1 - 3*squishConstant4D32
+		dy4 := dy0 - 1 - 3*squishConstant4D32
+		dz4 := dz0 - 1 - 3*squishConstant4D32
+		dw4 := dw0 - 3*squishConstant4D32
+		attn4 := 2 - float32(dx4*dx4) - float32(dy4*dy4) - float32(dz4*dz4) - float32(dw4*dw4)
+		if attn4 > 0 {
+			value += s.contrib4(attn4, xsb+1, ysb+1, zsb+1, wsb+0, dx4, dy4, dz4, dw4)
+		}
+
+		// Contribution (1,1,0,1)
+		dx3 := dx4
+		dy3 := dy4
+		dz3 := dz0 - 3*squishConstant4D32
+		dw3 := dw0 - 1 - 3*squishConstant4D32
+		attn3 := 2 - float32(dx3*dx3) - float32(dy3*dy3) - float32(dz3*dz3) - float32(dw3*dw3)
+		if attn3 > 0 {
+			value += s.contrib4(attn3, xsb+1, ysb+1, zsb+0, wsb+1, dx3, dy3, dz3, dw3)
+		}
+
+		// Contribution (1,0,1,1)
+		dx2 := dx4
+		dy2 := dy0 - 3*squishConstant4D32
+		dz2 := dz4
+		dw2 := dw3
+		attn2 := 2 - float32(dx2*dx2) - float32(dy2*dy2) - float32(dz2*dz2) - float32(dw2*dw2)
+		if attn2 > 0 {
+			value += s.contrib4(attn2, xsb+1, ysb+0, zsb+1, wsb+1, dx2, dy2, dz2, dw2)
+		}
+
+		// Contribution (0,1,1,1)
+		dx1 := dx0 - 3*squishConstant4D32
+		dz1 := dz4
+		dy1 := dy4
+		dw1 := dw3
+		attn1 := 2 - float32(dx1*dx1) - float32(dy1*dy1) - float32(dz1*dz1) - float32(dw1*dw1)
+		if attn1 > 0 {
+			value += s.contrib4(attn1, xsb+0, ysb+1, zsb+1, wsb+1, dx1, dy1, dz1, dw1)
+		}
+
+		// Contribution (1,1,0,0)
+		dx5 := dx0 - 1 - 2*squishConstant4D32
+		dy5 := dy0 - 1 - 2*squishConstant4D32
+		dz5 := dz0 - 0 - 2*squishConstant4D32
+		dw5 := dw0 - 0 - 2*squishConstant4D32
+		attn5 := 2 - float32(dx5*dx5) - float32(dy5*dy5) - float32(dz5*dz5) - float32(dw5*dw5)
+		if attn5 > 0 {
+			value += s.contrib4(attn5, xsb+1, ysb+1, zsb+0, wsb+0, dx5, dy5, dz5, dw5)
+		}
+
+		// Contribution (1,0,1,0)
+		dx6 := dx0 - 1 - 2*squishConstant4D32
+		dy6 := dy0 - 0 - 2*squishConstant4D32
+		dz6 := dz0 - 1 - 2*squishConstant4D32
+		dw6 := dw0 - 0 - 2*squishConstant4D32
+		attn6 := 2 - float32(dx6*dx6) - float32(dy6*dy6) - float32(dz6*dz6) - float32(dw6*dw6)
+		if attn6 > 0 {
+			value += s.contrib4(attn6, xsb+1, ysb+0, zsb+1, wsb+0, dx6, dy6, dz6, dw6)
+		}
+
+		// Contribution (1,0,0,1)
+		dx7 := dx0 - 1 - 2*squishConstant4D32
+		dy7 := dy0 - 0 - 2*squishConstant4D32
+		dz7 := dz0 - 0 - 2*squishConstant4D32
+		dw7 := dw0 - 1 - 2*squishConstant4D32
+		attn7 := 2 - float32(dx7*dx7) - float32(dy7*dy7) - float32(dz7*dz7) - float32(dw7*dw7)
+		if attn7 > 0 {
+			value += s.contrib4(attn7, xsb+1, ysb+0, zsb+0, wsb+1, dx7, dy7, dz7, dw7)
+		}
+
+		// Contribution (0,1,1,0)
+		dx8 := dx0 - 0 - 2*squishConstant4D32
+		dy8 := dy0 - 1 - 2*squishConstant4D32
+		dz8 := dz0 - 1 - 2*squishConstant4D32
+		dw8 := dw0 - 0 - 2*squishConstant4D32
+		attn8 := 2 - float32(dx8*dx8) - float32(dy8*dy8) - float32(dz8*dz8) - float32(dw8*dw8)
+		if attn8 > 0 {
+			value += s.contrib4(attn8, xsb+0, ysb+1, zsb+1, wsb+0, dx8, dy8, dz8, dw8)
+		}
+
+		// Contribution (0,1,0,1)
+		dx9 := dx0 - 0 - 2*squishConstant4D32
+		dy9 := dy0 - 1 - 2*squishConstant4D32
+		dz9 := dz0 - 0 - 2*squishConstant4D32
+		dw9 := dw0 - 1 - 2*squishConstant4D32
+		attn9 := 2 - float32(dx9*dx9) - float32(dy9*dy9) - float32(dz9*dz9) - float32(dw9*dw9)
+		if attn9 > 0 {
+			value += s.contrib4(attn9, xsb+0, ysb+1, zsb+0, wsb+1, dx9, dy9, dz9, dw9)
+		}
+
+		// Contribution (0,0,1,1)
+		dx10 := dx0 - 0 - 2*squishConstant4D32
+		dy10 := dy0 - 0 - 2*squishConstant4D32
+		dz10 := dz0 - 1 - 2*squishConstant4D32
+		dw10 := dw0 - 1 - 2*squishConstant4D32
+		attn10 := 2 - float32(dx10*dx10) - float32(dy10*dy10) - float32(dz10*dz10) - float32(dw10*dw10)
+		if attn10 > 0 {
+			value += s.contrib4(attn10, xsb+0, ysb+0, zsb+1, wsb+1, dx10, dy10, dz10, dw10)
+		}
+	}
+
+	// First extra vertex
+	attnExt0 := 2 - float32(dxExt0*dxExt0) - float32(dyExt0*dyExt0) - float32(dzExt0*dzExt0) - float32(dwExt0*dwExt0)
+	if attnExt0 > 0 {
+		value += s.contrib4(attnExt0, xsvExt0, ysvExt0, zsvExt0, wsvExt0, dxExt0, dyExt0, dzExt0, dwExt0)
+	}
+
+	// Second extra vertex
+	attnExt1 := 2 - float32(dxExt1*dxExt1) - float32(dyExt1*dyExt1) - float32(dzExt1*dzExt1) - float32(dwExt1*dwExt1)
+	if attnExt1 > 0 {
+		value += s.contrib4(attnExt1, xsvExt1, ysvExt1, zsvExt1, wsvExt1, dxExt1, dyExt1, dzExt1, dwExt1)
+	}
+
+	// Third extra vertex
+	attnExt2 := 2 - float32(dxExt2*dxExt2) - float32(dyExt2*dyExt2) - float32(dzExt2*dzExt2) - float32(dwExt2*dwExt2)
+	if attnExt2 > 0 {
+		value += s.contrib4(attnExt2, xsvExt2, ysvExt2, zsvExt2, wsvExt2, dxExt2, dyExt2, dzExt2, dwExt2)
+	}
+
+	return value / normConstant4D
+}
+
+// gradIndex1 is noise.gradIndex1 in float32 arithmetic.
+func (s *noise32) gradIndex1(xsb int32) int16 {
+	return s.perm[xsb&0xFF] & 0x0F
+}
+
+// gradIndex2 is noise.gradIndex2 in float32 arithmetic.
+func (s *noise32) gradIndex2(xsb, ysb int32) int16 {
+	return s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF] & 0x0E
+}
+
+// gradIndex3 is noise.gradIndex3 in float32 arithmetic.
+func (s *noise32) gradIndex3(xsb, ysb, zsb int32) int16 {
+	return s.permGradIndex3D[(int32(s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF])+zsb)&0xFF]
+}
+
+// gradIndex4 is noise.gradIndex4 in float32 arithmetic.
+func (s *noise32) gradIndex4(xsb, ysb, zsb, wsb int32) int16 {
+	return s.perm[(int32(s.perm[(int32(s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF])+zsb)&0xFF])+wsb)&0xFF] & 0xFC
+}
+
+// contrib2 is noise.contrib2 in float32 arithmetic.
+func (s *noise32) contrib2(attn float32, xsb, ysb int32, dx, dy float32) float32 {
+	index := s.gradIndex2(xsb, ysb)
+	gx := float32(gradients2D[index])
+	gy := float32(gradients2D[index+1])
+	ext := float32(gx*dx) + float32(gy*dy)
+
+	attn2 := attn * attn
+
+	return float32(attn2 * attn2 * ext)
+}
+
+// contrib3 is noise.contrib3 in float32 arithmetic.
+func (s *noise32) contrib3(attn float32, xsb, ysb, zsb int32, dx, dy, dz float32) float32 {
+	index := s.gradIndex3(xsb, ysb, zsb)
+	gx := float32(gradients3D[index])
+	gy := float32(gradients3D[index+1])
+	gz := float32(gradients3D[index+2])
+	ext := float32(gx*dx) + float32(gy*dy) + float32(gz*dz)
+
+	attn2 := attn * attn
+
+	return float32(attn2 * attn2 * ext)
+}
+
+// contrib4 is noise.contrib4 in float32 arithmetic.
+func (s *noise32) contrib4(attn float32, xsb, ysb, zsb, wsb int32, dx, dy, dz, dw float32) float32 {
+	index := s.gradIndex4(xsb, ysb, zsb, wsb)
+	gx := float32(gradients4D[index])
+	gy := float32(gradients4D[index+1])
+	gz := float32(gradients4D[index+2])
+	gw := float32(gradients4D[index+3])
+	ext := float32(gx*dx) + float32(gy*dy) + float32(gz*dz) + float32(gw*dw)
+
+	attn2 := attn * attn
+
+	return float32(attn2 * attn2 * ext)
+}
@@ -0,0 +1,550 @@
+package opensimplex
+
+import (
+	"container/heap"
+	"flag"
+	"math"
+	"testing"
+)
+
+// Deriving the 4D bound takes a minute or more, so by default TestDeriveBounds
+// checks the recorded result of the derivation, derived4D, instead. Run
+//
+//	go test -run TestDeriveBounds -bounds -timeout 0
+//
+// to derive it again.
+var deriveAll = flag.Bool("bounds", false, "derive the 4D output bound in TestDeriveBounds")
+
+// derived4D records the result of deriveBound(4, 1e-8).
+var derived4D = struct {
+	bound, found float64
+	at           [4]float64
+}{
+	bound: 1.0342209422652933,
+	found: 1.034220932265462,
+	at:    [4]float64{1.121676265614489, 1.121676265614489, 0.12167934302915373, 0.10328883033578848},
+}
+
+func TestDeriveBounds(t *testing.T) {
+	for _, c := range []struct {
+		dims    int
+		tol     float64
+		normMin float64
+	}{
+		{2, 1e-9, normMin2},
+		{3, 1e-9, normMin3},
+		{4, 1e-8, normMin4},
+	} {
+		var bound, found float64
+		if c.dims == 4 && !*deriveAll {
+			// The bound itself can only be checked by deriving it again, but
+			// the found value must still be reached at the recorded point.
+			b, _ := newBoundSearch(4)
+			at := boundBox{lo: derived4D.at, hi: derived4D.at}
+			if v := b.upper(&at); v != derived4D.found {
+				t.Errorf("4D: summed contribution at %v is %v, recorded %v", derived4D.at, v, derived4D.found)
+			}
+			bound, found = derived4D.bound, derived4D.found
+		} else {
+			bound, found, _ = deriveBound(c.dims, c.tol)
+			if c.dims == 4 && (bound != derived4D.bound || found != derived4D.found) {
+				t.Errorf("4D: derived %v and %v, recorded %v and %v", bound, found, derived4D.bound, derived4D.found)
+			}
+		}
+
+		t.Logf("%dD: |Eval| <= %.12f, reached %.12f", c.dims, bound, found)
+		if bound > c.normMin {
+			t.Errorf("%dD: derived bound %v exceeds normMin %v", c.dims, bound, c.normMin)
+		}
+		if c.normMin-found > 2*c.tol {
+			t.Errorf("%dD: normMin %v is more than %v above the reached %v", c.dims, c.normMin, 2*c.tol, found)
+		}
+	}
+}
+
+// cellGrid calls f with n evenly spaced points along each of the dims axes of
+// a box covering one period of the lattice, so every position of a point
+// within its lattice cell is sampled.
+func cellGrid(dims, n int, f func(p [4]float64)) {
+	squish := [...]float64{2: squishConstant2D, 3: squishConstant3D, 4: squishConstant4D}[dims]
+	size := 1 + float64(dims)*squish
+
+	var p [4]float64
+	var walk func(i int)
+	walk = func(i int) {
+		if i == dims {
+			f(p)
+			return
+		}
+		for k := 0; k < n; k++ {
+			p[i] = float64(float32(size * float64(k) / float64(n-1)))
+			walk(i + 1)
+		}
+	}
+	walk(0)
+}
+
+func TestNormalizedEnvelope(t *testing.T) {
+	for _, c := range []struct {
+		dims    int
+		n       int
+		normMin float64
+		tol     float64
+	}{
+		{2, 201, normMin2, 1e-4},
+		{3, 41, normMin3, 1e-2},
+		{4, 13, normMin4, 0.1},
+	} {
+		for _, sign := range []float64{1, -1} {
+			env := newEnvelope(sign)
+			norm, norm32 := NewRanged(env, 0, 1, RangeLinear), NewRanged32(env, 0, 1, RangeLinear)
+
+			extreme := 0.0
+			cellGrid(c.dims, c.n, func(p [4]float64) {
+				var r, v float64
+				var v32 float32
+				switch c.dims {
+				case 2:
+					r, v = env.Eval2(p[0], p[1]), norm.Eval2(p[0], p[1])
+					v32 = norm32.Eval2(float32(p[0]), float32(p[1]))
+				case 3:
+					r, v = env.Eval3(p[0], p[1], p[2]), norm.Eval3(p[0], p[1], p[2])
+					v32 = norm32.Eval3(float32(p[0]), float32(p[1]), float32(p[2]))
+				case 4:
+					r, v = env.Eval4(p[0], p[1], p[2], p[3]), norm.Eval4(p[0], p[1], p[2], p[3])
+					v32 = norm32.Eval4(float32(p[0]), float32(p[1]), float32(p[2]), float32(p[3]))
+				}
+
+				if v < 0 || v >= 1 || v32 < 0 || v32 >= 1 {
+					t.Fatalf("%dD: normalized envelope at %v is %v (float32 %v), outside [0, 1)", c.dims, p[:c.dims], v, v32)
+				}
+				extreme = math.Max(extreme, r*sign)
+			})
+
+			if extreme > c.normMin || c.normMin-extreme > c.tol {
+				t.Errorf("%dD: sampled extreme %v not within %v below normMin %v", c.dims, extreme*sign, c.tol, c.normMin)
+			}
+		}
+	}
+}
+
+func TestNormalizedRange(t *testing.T) {
+	n, n32 := NewNormalized(99), NewNormalized32(99)
+	cellGrid(3, 41, func(p [4]float64) {
+		for _, v := range []float64{
+			n.Eval2(p[0]*8, p[1]*8),
+			n.Eval3(p[0]*8, p[1]*8, p[2]*8),
+			n.Eval4(p[0]*8, p[1]*8, p[2]*8, p[0]-p[1]),
+			float64(n32.Eval2(float32(p[0]*8), float32(p[1]*8))),
+			float64(n32.Eval3(float32(p[0]*8), float32(p[1]*8), float32(p[2]*8))),
+			float64(n32.Eval4(float32(p[0]*8), float32(p[1]*8), float32(p[2]*8), float32(p[0]-p[1]))),
+		} {
+			if v < 0 || v >= 1 {
+				t.Fatalf("normalized noise at %v is %v, outside [0, 1)", p[:3], v)
+			}
+		}
+	})
+}
+
+// extremeHash is a lattice hash used only to analyse the range of the noise.
+// Instead of hashing a vertex, it picks the gradient that pushes the noise at
+// the point at furthest towards sign. Evaluating the noise at that
+// point then gives the largest (or smallest) value any permutation table could
+// produce there, using the exact vertex selection of the eval functions. A
+// noise instance using it is not safe for concurrent use.
+type extremeHash struct {
+	at   [4]float64
+	sign float64
+}
+
+// envelope is a Noise whose Eval2, Eval3 and Eval4 return the upper envelope
+// of the noise over all seeds when sign is 1, and the lower envelope when sign
+// is -1.
+type envelope struct {
+	noise
+	extreme *extremeHash
+}
+
+func newEnvelope(sign float64) *envelope {
+	e := &envelope{extreme: &extremeHash{sign: sign}}
+	e.lattice = e.extreme
+	return e
+}
+
+// Eval2 returns the extreme value of the 2D noise at (x, y) over all seeds.
+func (e *envelope) Eval2(x, y float64) float64 {
+	e.extreme.at = [4]float64{x, y}
+	return e.noise.Eval2(x, y)
+}
+
+// Eval3 returns the extreme value of the 3D noise at (x, y, z) over all seeds.
+func (e *envelope) Eval3(x, y, z float64) float64 {
+	e.extreme.at = [4]float64{x, y, z}
+	return e.noise.Eval3(x, y, z)
+}
+
+// Eval4 returns the extreme value of the 4D noise at (x, y, z, w) over all
+// seeds.
+func (e *envelope) Eval4(x, y, z, w float64) float64 {
+	e.extreme.at = [4]float64{x, y, z, w}
+	return e.noise.Eval4(x, y, z, w)
+}
+
+// best returns the index of the gradient in grads, of dimension len(d), whose
+// dot product with d times e.sign is largest.
+func (e *extremeHash) best(grads []int8, d []float64) int16 {
+	n := len(d)
+	best, bestDot := 0, 0.0
+	for i := 0; i+n <= len(grads); i += n {
+		dot := 0.0
+		for j, dj := range d {
+			dot += float64(grads[i+j]) * dj
+		}
+		if dot*e.sign > bestDot*e.sign || i == 0 {
+			best, bestDot = i, dot
+		}
+	}
+	return int16(best)
+}
+
+func (e *extremeHash) gradIndex1(xsb int32) int16 {
+	return e.best(gradients1D[:], []float64{e.at[0] - float64(xsb)})
+}
+
+func (e *extremeHash) gradIndex2(xsb, ysb int32) int16 {
+	squishOffset := float64(xsb+ysb) * squishConstant2D
+	return e.best(gradients2D[:], []float64{
+		e.at[0] - float64(xsb) - squishOffset,
+		e.at[1] - float64(ysb) - squishOffset,
+	})
+}
+
+func (e *extremeHash) gradIndex3(xsb, ysb, zsb int32) int16 {
+	squishOffset := float64(xsb+ysb+zsb) * squishConstant3D
+	return e.best(gradients3D[:], []float64{
+		e.at[0] - float64(xsb) - squishOffset,
+		e.at[1] - float64(ysb) - squishOffset,
+		e.at[2] - float64(zsb) - squishOffset,
+	})
+}
+
+func (e *extremeHash) gradIndex4(xsb, ysb, zsb, wsb int32) int16 {
+	squishOffset := float64(xsb+ysb+zsb+wsb) * squishConstant4D
+	return e.best(gradients4D[:], []float64{
+		e.at[0] - float64(xsb) - squishOffset,
+		e.at[1] - float64(ysb) - squishOffset,
+		e.at[2] - float64(zsb) - squishOffset,
+		e.at[3] - float64(wsb) - squishOffset,
+	})
+}
+
+// The bound derivation below proves an upper bound on the envelope, and so on
+// the noise for every seed, by branch and bound over one period of the
+// lattice.
+//
+// It works on the sum of the contributions of all lattice vertices within the
+// kernel radius, each with its best gradient. In 2D that is exactly the upper
+// envelope; in 3D and 4D the eval functions leave out a few vertices with a
+// small positive contribution, so the sum is an upper bound of the envelope.
+// The sum is continuous, so bounding it over ever smaller boxes converges.
+
+// boundSearch holds the lattice being searched.
+type boundSearch struct {
+	dims  int
+	grads []int8
+	norm  float64
+
+	// vertices holds the input space positions of all lattice vertices that
+	// can contribute anywhere in the searched region.
+	vertices [][4]float64
+}
+
+// boundBox is an axis-aligned box of the input space, [lo, hi] in each of the
+// first dims coordinates.
+type boundBox struct {
+	lo, hi [4]float64
+	upper  float64
+}
+
+// boxHeap is a max-heap of boxes on their upper bound, for container/heap.
+type boxHeap []boundBox
+
+func (h boxHeap) Len() int            { return len(h) }
+func (h boxHeap) Less(i, j int) bool  { return h[i].upper > h[j].upper }
+func (h boxHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *boxHeap) Push(x interface{}) { *h = append(*h, x.(boundBox)) }
+
+func (h *boxHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+func newBoundSearch(dims int) (*boundSearch, boundBox) {
+	b := &boundSearch{dims: dims}
+
+	var squish float64
+	switch dims {
+	case 2:
+		b.grads, b.norm, squish = gradients2D[:], normConstant2D, squishConstant2D
+	case 3:
+		b.grads, b.norm, squish = gradients3D[:], normConstant3D, squishConstant3D
+	case 4:
+		b.grads, b.norm, squish = gradients4D[:], normConstant4D, squishConstant4D
+	default:
+		panic("opensimplex: bounds only exist for 2 to 4 dimensions")
+	}
+
+	// The box [0, 1+dims*squish]^dims covers the image of the unit cell of the
+	// lattice, which repeats over the whole space.
+	var root boundBox
+	for i := 0; i < dims; i++ {
+		root.hi[i] = 1 + float64(dims)*squish
+	}
+
+	var v [4]int
+	var walk func(i int)
+	walk = func(i int) {
+		if i < dims {
+			for v[i] = -4; v[i] <= 5; v[i]++ {
+				walk(i + 1)
+			}
+			return
+		}
+
+		sum := 0
+		for _, c := range v[:dims] {
+			sum += c
+		}
+		var p [4]float64
+		for j, c := range v[:dims] {
+			p[j] = float64(c) + float64(sum)*squish
+		}
+		if near, _ := b.dist2(&p, &root); near < 2 {
+			b.vertices = append(b.vertices, p)
+		}
+	}
+	walk(0)
+
+	root.upper = b.upper(&root)
+	return b, root
+}
+
+// dist2 returns the squared distances from p to the nearest and the farthest
+// point of the box.
+func (b *boundSearch) dist2(p *[4]float64, box *boundBox) (near, far float64) {
+	for i := 0; i < b.dims; i++ {
+		lo, hi := box.lo[i]-p[i], box.hi[i]-p[i]
+		switch {
+		case lo > 0:
+			near += lo * lo
+		case hi < 0:
+			near += hi * hi
+		}
+		far += math.Max(lo*lo, hi*hi)
+	}
+	return near, far
+}
+
+// meetsSorted reports whether the box contains a point whose coordinates are
+// in descending order. The lattice and the gradient sets are symmetric under
+// permutations of the axes, and so is the summed contribution, so the search
+// only needs to cover that part of the box of one period.
+func (b *boundSearch) meetsSorted(box *boundBox) bool {
+	v := box.lo[b.dims-1]
+	for i := b.dims - 2; i >= 0; i-- {
+		v = math.Max(v, box.lo[i])
+		if v > box.hi[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// taylorTerm is the second-order expansion of the contribution
+// attn^4 * (g.d) of one vertex and gradient at the center of a box: its value,
+// gradient, and a bound of the norm of its Hessian over the box.
+type taylorTerm struct {
+	value float64
+	grad  [4]float64
+	hess  float64
+}
+
+// maxCombinations caps the number of gradient combinations upper expands
+// before it falls back to bounding each vertex on its own.
+const maxCombinations = 256
+
+// upper returns an upper bound of the summed contributions over the box. For a
+// box of a single point it is the summed contribution at that point.
+//
+// Each vertex contributes attn^4 * ext, where ext = max(g.d) over the
+// gradients g. Two bounds are used:
+//
+//   - The interval bound takes attn at the point of the box nearest to the
+//     vertex, and maximizes each g.d over the box.
+//   - Where attn is positive everywhere in the box, the contribution is the
+//     largest of the polynomials attn^4 * (g.d) of the gradients that can be
+//     the best one somewhere in the box. The sum over the vertices is bounded
+//     by its second-order Taylor expansion at the center, maximized over the
+//     combinations of those gradients, with the norm of each Hessian bounded
+//     by |g| * (48 attn^2 |d|^3 + 24 attn^3 |d|). Near a maximum the
+//     gradients of the vertices cancel out, so this bound converges
+//     quadratically.
+func (b *boundSearch) upper(box *boundBox) float64 {
+	n := b.dims
+
+	var c, h [4]float64
+	r2 := 0.0
+	for i := 0; i < n; i++ {
+		c[i] = (box.lo[i] + box.hi[i]) / 2
+		h[i] = (box.hi[i] - box.lo[i]) / 2
+		r2 += h[i] * h[i]
+	}
+
+	// fixed sums the interval bounds of the vertices whose attn reaches zero
+	// in the box, separate sums the best bound of each other vertex on its
+	// own, and candidates holds their Taylor expansions.
+	fixed, separate := 0.0, 0.0
+	var candidates [][]taylorTerm
+	combinations := 1
+
+	for k := range b.vertices {
+		p := &b.vertices[k]
+		near, far := b.dist2(p, box)
+		if near >= 2 {
+			continue
+		}
+
+		var d [4]float64
+		dd := 0.0
+		for i := 0; i < n; i++ {
+			d[i] = c[i] - p[i]
+			dd += d[i] * d[i]
+		}
+
+		var dots, spreads [64]float64
+		interval, floor := 0.0, math.Inf(-1)
+		for g := 0; g < len(b.grads); g += n {
+			dot, spread := 0.0, 0.0
+			for i := 0; i < n; i++ {
+				gi := float64(b.grads[g+i])
+				dot += gi * d[i]
+				spread += math.Abs(gi) * h[i]
+			}
+			dots[g/n], spreads[g/n] = dot, spread
+			interval = math.Max(interval, dot+spread)
+			floor = math.Max(floor, dot-spread)
+		}
+		attn := 2 - near
+		attn *= attn
+		interval *= attn * attn
+
+		if far >= 2 || r2 == 0 {
+			fixed += interval
+			continue
+		}
+
+		a := 2 - dd
+		a2 := a * a
+		amax, dmax := 2-near, math.Sqrt(far)
+		var terms []taylorTerm
+		own := 0.0
+		for g := 0; g < len(b.grads); g += n {
+			dot := dots[g/n]
+			if dot+spreads[g/n] < floor {
+				continue
+			}
+
+			t := taylorTerm{value: a2 * a2 * dot}
+			gn, lin := 0.0, 0.0
+			for i := 0; i < n; i++ {
+				gi := float64(b.grads[g+i])
+				gn += gi * gi
+				t.grad[i] = -8*a2*a*dot*d[i] + a2*a2*gi
+				lin += math.Abs(t.grad[i]) * h[i]
+			}
+			t.hess = math.Sqrt(gn) * (48*amax*amax*dmax*dmax*dmax + 24*amax*amax*amax*dmax)
+			own = math.Max(own, t.value+lin+t.hess*r2/2)
+			terms = append(terms, t)
+		}
+
+		separate += math.Min(interval, own)
+		candidates = append(candidates, terms)
+		combinations *= len(terms)
+		if combinations > maxCombinations {
+			combinations = 0
+		}
+	}
+
+	if combinations == 0 {
+		return (fixed + separate) / b.norm
+	}
+
+	combined := math.Inf(-1)
+	var pick []int
+	for m := 0; m < combinations; m++ {
+		pick = pick[:0]
+		for rest, k := m, 0; k < len(candidates); k++ {
+			pick = append(pick, rest%len(candidates[k]))
+			rest /= len(candidates[k])
+		}
+
+		var sum taylorTerm
+		for k, t := range candidates {
+			term := &t[pick[k]]
+			sum.value += term.value
+			sum.hess += term.hess
+			for i := 0; i < n; i++ {
+				sum.grad[i] += term.grad[i]
+			}
+		}
+		v := sum.value + sum.hess*r2/2
+		for i := 0; i < n; i++ {
+			v += math.Abs(sum.grad[i]) * h[i]
+		}
+		combined = math.Max(combined, v)
+	}
+
+	return (fixed + math.Min(separate, combined)) / b.norm
+}
+
+// deriveBound returns a proven upper bound of |Eval| in dims dimensions over
+// all seeds, and the largest summed contribution found at a point, which is
+// within tol of it, along with that point. The gradient sets are symmetric
+// under negation, so the same bound holds for the lower envelope.
+func deriveBound(dims int, tol float64) (bound, found float64, at [4]float64) {
+	b, root := newBoundSearch(dims)
+
+	boxes := &boxHeap{root}
+	for {
+		top := heap.Pop(boxes).(boundBox)
+		if top.upper-found <= tol {
+			return top.upper, found, at
+		}
+
+		for child := 0; child < 1<<dims; child++ {
+			var c, center boundBox
+			for i := 0; i < dims; i++ {
+				mid := (top.lo[i] + top.hi[i]) / 2
+				if child&(1<<i) == 0 {
+					c.lo[i], c.hi[i] = top.lo[i], mid
+				} else {
+					c.lo[i], c.hi[i] = mid, top.hi[i]
+				}
+				center.lo[i] = (c.lo[i] + c.hi[i]) / 2
+				center.hi[i] = center.lo[i]
+			}
+
+			if !b.meetsSorted(&c) {
+				continue
+			}
+			if v := b.upper(&center); v > found {
+				found, at = v, center.lo
+			}
+			if c.upper = b.upper(&c); c.upper > found {
+				heap.Push(boxes, c)
+			}
+		}
+	}
+}
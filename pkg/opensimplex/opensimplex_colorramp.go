@@ -0,0 +1,246 @@
+package opensimplex
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// Interpolation selects how a ColorRamp blends between two adjacent stops.
+type Interpolation int
+
+const (
+	// InterpLinear blends the colors of adjacent stops linearly.
+	InterpLinear Interpolation = iota
+	// InterpSmoothstep blends the colors of adjacent stops with an ease-in/ease-out
+	// curve, which hides the seams at the stops.
+	InterpSmoothstep
+	// InterpConstant uses the color of the closest stop at or below the value,
+	// giving hard bands.
+	InterpConstant
+)
+
+var interpolationNames = [...]string{
+	InterpLinear:     "linear",
+	InterpSmoothstep: "smoothstep",
+	InterpConstant:   "constant",
+}
+
+// String returns the name of the interpolation as used in JSON ramps.
+func (i Interpolation) String() string {
+	if i < 0 || int(i) >= len(interpolationNames) {
+		return fmt.Sprintf("Interpolation(%d)", int(i))
+	}
+	return interpolationNames[i]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (i Interpolation) MarshalText() ([]byte, error) {
+	if i < 0 || int(i) >= len(interpolationNames) {
+		return nil, fmt.Errorf("opensimplex: unknown interpolation %d", int(i))
+	}
+	return []byte(interpolationNames[i]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (i *Interpolation) UnmarshalText(text []byte) error {
+	for k, name := range interpolationNames {
+		if strings.EqualFold(string(text), name) {
+			*i = Interpolation(k)
+			return nil
+		}
+	}
+	return fmt.Errorf("opensimplex: unknown interpolation %q", text)
+}
+
+// ColorStop maps a noise value to a color. The color is not premultiplied by
+// its alpha, like the "#rrggbbaa" form it is encoded as.
+type ColorStop struct {
+	Value float64
+	Color color.NRGBA
+}
+
+type jsonColorStop struct {
+	Value float64 `json:"value"`
+	Color string  `json:"color"`
+}
+
+// MarshalJSON encodes the stop as {"value": v, "color": "#rrggbbaa"}.
+func (s ColorStop) MarshalJSON() ([]byte, error) {
+	c := s.Color
+	return json.Marshal(jsonColorStop{
+		Value: s.Value,
+		Color: fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A),
+	})
+}
+
+// UnmarshalJSON decodes a stop from {"value": v, "color": "#rrggbb"}. The color
+// may also be given as "#rrggbbaa"; it is opaque otherwise.
+func (s *ColorStop) UnmarshalJSON(data []byte) error {
+	var j jsonColorStop
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	c, err := parseHexColor(j.Color)
+	if err != nil {
+		return err
+	}
+
+	s.Value, s.Color = j.Value, c
+	return nil
+}
+
+func parseHexColor(h string) (color.NRGBA, error) {
+	var c color.NRGBA
+	var err error
+
+	switch len(h) {
+	case 7:
+		c.A = 0xFF
+		_, err = fmt.Sscanf(h, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 9:
+		_, err = fmt.Sscanf(h, "#%02x%02x%02x%02x", &c.R, &c.G, &c.B, &c.A)
+	default:
+		err = fmt.Errorf("want #rrggbb or #rrggbbaa")
+	}
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("opensimplex: invalid color %q: %v", h, err)
+	}
+	return c, nil
+}
+
+// ColorRamp maps noise values to colors by interpolating between stops, for
+// example to render a heightmap as water, sand, grass, rock and snow.
+//
+// The stops must be sorted by ascending value. Values below the first stop or
+// above the last one get the color of that stop.
+//
+// A ColorRamp encodes to and decodes from JSON of the form
+//
+//	{
+//		"interpolation": "smoothstep",
+//		"stops": [
+//			{"value": 0.0, "color": "#1d3b8a"},
+//			{"value": 0.5, "color": "#e8d59a"},
+//			{"value": 1.0, "color": "#ffffff"}
+//		]
+//	}
+//
+// where interpolation is one of "linear", "smoothstep" and "constant", and
+// defaults to "linear".
+type ColorRamp struct {
+	Interpolation Interpolation `json:"interpolation"`
+	Stops         []ColorStop   `json:"stops"`
+}
+
+// LoadColorRamp decodes a JSON ColorRamp from r, and checks that its stops are
+// sorted.
+func LoadColorRamp(r io.Reader) (*ColorRamp, error) {
+	var ramp ColorRamp
+	if err := json.NewDecoder(r).Decode(&ramp); err != nil {
+		return nil, err
+	}
+
+	if len(ramp.Stops) == 0 {
+		return nil, fmt.Errorf("opensimplex: color ramp has no stops")
+	}
+	for i := 1; i < len(ramp.Stops); i++ {
+		if ramp.Stops[i].Value < ramp.Stops[i-1].Value {
+			return nil, fmt.Errorf("opensimplex: color ramp stop %d is out of order", i)
+		}
+	}
+
+	return &ramp, nil
+}
+
+// At returns the color of the ramp at v. An empty ramp is transparent black.
+// Colors are interpolated before they are premultiplied, so a stop's alpha
+// does not darken the blend towards it.
+func (r *ColorRamp) At(v float64) color.NRGBA {
+	stops := r.Stops
+	if len(stops) == 0 {
+		return color.NRGBA{}
+	}
+	if v <= stops[0].Value {
+		return stops[0].Color
+	}
+
+	// Find the first stop above v.
+	i := 1
+	for i < len(stops) && stops[i].Value <= v {
+		i++
+	}
+	if i == len(stops) {
+		return stops[i-1].Color
+	}
+
+	lo, hi := stops[i-1], stops[i]
+	t := (v - lo.Value) / (hi.Value - lo.Value)
+	switch r.Interpolation {
+	case InterpConstant:
+		return lo.Color
+	case InterpSmoothstep:
+		t = t * t * (3 - 2*t)
+	}
+
+	return color.NRGBA{
+		R: lerpChannel(lo.Color.R, hi.Color.R, t),
+		G: lerpChannel(lo.Color.G, hi.Color.G, t),
+		B: lerpChannel(lo.Color.B, hi.Color.B, t),
+		A: lerpChannel(lo.Color.A, hi.Color.A, t),
+	}
+}
+
+func lerpChannel(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t + 0.5)
+}
+
+// Image returns an image.Image that colors the normalized Eval2 of base with
+// the ramp. Pixels are mapped to noise space like those of Image.
+func (r *ColorRamp) Image(base Noise, rect image.Rectangle, scale float64) *RampImage {
+	return &RampImage{Image: *NewImage(base, rect, scale), Ramp: r}
+}
+
+// RampImage is an Image whose pixels are colored by a ColorRamp instead of
+// being greyscale.
+type RampImage struct {
+	Image
+
+	Ramp *ColorRamp
+}
+
+// ColorModel returns color.NRGBAModel.
+func (m *RampImage) ColorModel() color.Model {
+	return color.NRGBAModel
+}
+
+// Opaque reports whether all the stops of the ramp are opaque.
+func (m *RampImage) Opaque() bool {
+	if len(m.Ramp.Stops) == 0 {
+		return false
+	}
+	for _, s := range m.Ramp.Stops {
+		if s.Color.A != 0xFF {
+			return false
+		}
+	}
+	return true
+}
+
+// At returns the color of the pixel at (x, y).
+func (m *RampImage) At(x, y int) color.Color {
+	return m.NRGBAAt(x, y)
+}
+
+// NRGBAAt returns the color of the pixel at (x, y), like image.NRGBA does.
+// Pixels outside of the image's bounds are transparent black.
+func (m *RampImage) NRGBAAt(x, y int) color.NRGBA {
+	if !(image.Point{X: x, Y: y}.In(m.Rect)) {
+		return color.NRGBA{}
+	}
+	return m.Ramp.At(m.value(x, y))
+}
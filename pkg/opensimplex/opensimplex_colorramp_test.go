@@ -0,0 +1,103 @@
+package opensimplex
+
+import (
+	"encoding/json"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestColorRampInterpolation(t *testing.T) {
+	black, white := color.NRGBA{A: 0xFF}, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}
+	ramp := &ColorRamp{Stops: []ColorStop{{0.2, black}, {0.6, white}}}
+
+	for _, c := range []struct {
+		interp Interpolation
+		v      float64
+		want   uint8
+	}{
+		{InterpLinear, 0, 0},
+		{InterpLinear, 0.2, 0},
+		{InterpLinear, 0.3, 64},
+		{InterpLinear, 0.4, 128},
+		{InterpLinear, 0.6, 0xFF},
+		{InterpLinear, 1, 0xFF},
+		{InterpSmoothstep, 0.3, 40},
+		{InterpSmoothstep, 0.4, 128},
+		{InterpConstant, 0.3, 0},
+		{InterpConstant, 0.59, 0},
+		{InterpConstant, 0.6, 0xFF},
+	} {
+		ramp.Interpolation = c.interp
+		if got := ramp.At(c.v); got.R != c.want || got.A != 0xFF {
+			t.Errorf("%v ramp at %v = %v, want grey %v", c.interp, c.v, got, c.want)
+		}
+	}
+}
+
+func TestLoadColorRamp(t *testing.T) {
+	ramp, err := LoadColorRamp(strings.NewReader(`{
+		"interpolation": "constant",
+		"stops": [
+			{"value": 0.4, "color": "#1d3b8a"},
+			{"value": 0.7, "color": "#ffffff80"}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ColorRamp{
+		Interpolation: InterpConstant,
+		Stops: []ColorStop{
+			{0.4, color.NRGBA{R: 0x1d, G: 0x3b, B: 0x8a, A: 0xFF}},
+			{0.7, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0x80}},
+		},
+	}
+	if ramp.Interpolation != want.Interpolation || len(ramp.Stops) != 2 || ramp.Stops[0] != want.Stops[0] || ramp.Stops[1] != want.Stops[1] {
+		t.Fatalf("LoadColorRamp() = %+v, want %+v", ramp, want)
+	}
+
+	data, err := json.Marshal(ramp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != `{"interpolation":"constant","stops":[{"value":0.4,"color":"#1d3b8aff"},{"value":0.7,"color":"#ffffff80"}]}` {
+		t.Fatalf("json.Marshal() = %s", got)
+	}
+
+	// "#ffffff80" is half-transparent white, which premultiplies to half grey.
+	if r, _, _, a := color.Color(ramp.At(1)).RGBA(); r != 0x8080 || a != 0x8080 {
+		t.Errorf("ramp.At(1).RGBA() = %#x, %#x, want 0x8080, 0x8080", r, a)
+	}
+
+	for _, bad := range []string{
+		`{"stops": []}`,
+		`{"stops": [{"value": 1, "color": "#000000"}, {"value": 0, "color": "#000000"}]}`,
+		`{"stops": [{"value": 0, "color": "black"}]}`,
+		`{"interpolation": "cubic", "stops": [{"value": 0, "color": "#000000"}]}`,
+	} {
+		if _, err := LoadColorRamp(strings.NewReader(bad)); err == nil {
+			t.Errorf("LoadColorRamp(%s) succeeded", bad)
+		}
+	}
+}
+
+func TestRampImageMatchesImage(t *testing.T) {
+	ramp := &ColorRamp{Stops: []ColorStop{{0, color.NRGBA{A: 0xFF}}, {1, color.NRGBA{R: 0xFF, A: 0xFF}}}}
+	m := ramp.Image(New(0), image.Rect(0, 0, 16, 16), 1.0/24)
+	grey := NewImage(New(0), image.Rect(0, 0, 16, 16), 1.0/24)
+
+	if !m.Opaque() {
+		t.Fatal("ramp image with opaque stops is not opaque")
+	}
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			want := uint8(float64(grey.Gray16At(x, y).Y)/0x10000*0xFF + 0.5)
+			if got := m.NRGBAAt(x, y).R; got < want-1 || got > want+1 {
+				t.Fatalf("ramp pixel %v, %v = %v, want about %v", x, y, got, want)
+			}
+		}
+	}
+}
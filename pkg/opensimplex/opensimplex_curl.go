@@ -0,0 +1,43 @@
+package opensimplex
+
+// Curl evaluates divergence-free vector fields, for advecting particles or
+// fluid-like motion, as the curl of noise potentials.
+//
+// The 2D field is the curl of a single scalar potential psi, (dpsi/dy,
+// -dpsi/dx). The 3D field is the curl of a vector potential whose three
+// components are independently seeded noises. The partial derivatives come
+// from the analytic derivatives of the noise, so each component costs one
+// evaluation rather than several finite differences.
+type Curl struct {
+	potentials [3]*noise
+}
+
+// curlSeedStep separates the seeds of the potentials of a Curl. It is the
+// 64-bit golden ratio 0x9E3779B97F4A7C15, as an int64.
+const curlSeedStep int64 = -7046029254386353131
+
+// NewCurl constructs a Curl instance with a 64-bit seed. Its potentials are
+// New(seed), New(seed+step) and New(seed+2*step) for a fixed odd step.
+func NewCurl(seed int64) *Curl {
+	c := &Curl{}
+	for i := range c.potentials {
+		c.potentials[i] = newNoise(seed + int64(i)*curlSeedStep)
+	}
+
+	return c
+}
+
+// Curl2 returns the velocity at (x, y) of a 2D divergence-free field.
+func (c *Curl) Curl2(x, y float64) (vx, vy float64) {
+	_, dx, dy := c.potentials[0].Eval2Deriv(x, y)
+	return dy, -dx
+}
+
+// Curl3 returns the velocity at (x, y, z) of a 3D divergence-free field.
+func (c *Curl) Curl3(x, y, z float64) (vx, vy, vz float64) {
+	_, _, d1y, d1z := c.potentials[0].Eval3Deriv(x, y, z)
+	_, d2x, _, d2z := c.potentials[1].Eval3Deriv(x, y, z)
+	_, d3x, d3y, _ := c.potentials[2].Eval3Deriv(x, y, z)
+
+	return d3y - d2z, d1z - d3x, d2x - d1y
+}
@@ -0,0 +1,100 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestCurlMatchesPotentials(t *testing.T) {
+	const h = 1e-6
+
+	seed, step := int64(42), curlSeedStep
+	c := NewCurl(seed)
+	p1, p2, p3 := New(seed), New(seed+step), New(seed+2*step)
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+
+	// Like the derivatives themselves, the 3D field is allowed to disagree at a
+	// few region boundaries.
+	misses := 0
+	for i := 0; i < 10000; i++ {
+		x, y, z := r.Float64()*64-32, r.Float64()*64-32, r.Float64()*64-32
+
+		vx, vy := c.Curl2(x, y)
+		wx := (p1.Eval2(x, y+h) - p1.Eval2(x, y-h)) / (2 * h)
+		wy := -(p1.Eval2(x+h, y) - p1.Eval2(x-h, y)) / (2 * h)
+		if math.Abs(vx-wx) > 1e-4 || math.Abs(vy-wy) > 1e-4 {
+			t.Fatalf("Curl2 at %v, %v is %v, %v, finite differences give %v, %v", x, y, vx, vy, wx, wy)
+		}
+
+		d := func(n Noise, axis int) float64 {
+			a, b := [3]float64{x, y, z}, [3]float64{x, y, z}
+			a[axis], b[axis] = a[axis]+h, b[axis]-h
+			return (n.Eval3(a[0], a[1], a[2]) - n.Eval3(b[0], b[1], b[2])) / (2 * h)
+		}
+		vx, vy, vz := c.Curl3(x, y, z)
+		wx, wy, wz := d(p3, 1)-d(p2, 2), d(p1, 2)-d(p3, 0), d(p2, 0)-d(p1, 1)
+		if math.Abs(vx-wx) > 1e-4 || math.Abs(vy-wy) > 1e-4 || math.Abs(vz-wz) > 1e-4 {
+			if misses++; misses > 10 {
+				t.Fatalf("Curl3 at %v, %v, %v is %v, %v, %v, finite differences give %v, %v, %v", x, y, z, vx, vy, vz, wx, wy, wz)
+			}
+		}
+	}
+}
+
+func TestCurlDivergenceFree(t *testing.T) {
+	const h = 1e-4
+
+	c := NewCurl(7)
+	// #nosec: G404
+	r := rand.New(rand.NewSource(2))
+
+	misses := 0
+	for i := 0; i < 10000; i++ {
+		x, y, z := r.Float64()*64-32, r.Float64()*64-32, r.Float64()*64-32
+
+		ax, _ := c.Curl2(x+h, y)
+		bx, _ := c.Curl2(x-h, y)
+		_, ay := c.Curl2(x, y+h)
+		_, by := c.Curl2(x, y-h)
+		if div := (ax - bx + ay - by) / (2 * h); math.Abs(div) > 1e-3 {
+			t.Fatalf("Curl2 has divergence %v at %v, %v", div, x, y)
+		}
+
+		ax, _, _ = c.Curl3(x+h, y, z)
+		bx, _, _ = c.Curl3(x-h, y, z)
+		_, ay, _ = c.Curl3(x, y+h, z)
+		_, by, _ = c.Curl3(x, y-h, z)
+		_, _, az := c.Curl3(x, y, z+h)
+		_, _, bz := c.Curl3(x, y, z-h)
+		if div := (ax - bx + ay - by + az - bz) / (2 * h); math.Abs(div) > 1e-3 {
+			if misses++; misses > 10 {
+				t.Fatalf("Curl3 has divergence %v at %v, %v, %v", div, x, y, z)
+			}
+		}
+	}
+}
+
+func TestCurlSeeded(t *testing.T) {
+	a, b := NewCurl(1), NewCurl(2)
+	if ax, ay := a.Curl2(0.3, 0.7); ax == 0 && ay == 0 {
+		t.Error("Curl2 is zero")
+	}
+	ax, ay, az := a.Curl3(0.3, 0.7, 1.1)
+	bx, by, bz := b.Curl3(0.3, 0.7, 1.1)
+	if ax == bx && ay == by && az == bz {
+		t.Error("Curl3 ignores the seed")
+	}
+	if a.potentials[0].perm == a.potentials[1].perm || a.potentials[1].perm == a.potentials[2].perm {
+		t.Error("Curl potentials share a permutation table")
+	}
+}
+
+func BenchmarkCurl3(b *testing.B) {
+	c := NewCurl(1)
+
+	for iter := 0; iter < b.N; iter++ {
+		c.Curl3(float64(iter)/1000, 0.5, 0.25)
+	}
+}
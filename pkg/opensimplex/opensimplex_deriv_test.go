@@ -0,0 +1,88 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestDerivativesMatchEval(t *testing.T) {
+	n := New(42).(NoiseWithDerivatives)
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 10000; i++ {
+		x, y, z, w := r.Float64()*64-32, r.Float64()*64-32, r.Float64()*64-32, r.Float64()*64-32
+
+		if v, _, _ := n.Eval2Deriv(x, y); v != n.Eval2(x, y) {
+			t.Fatalf("Eval2Deriv value %v differs from Eval2 %v at %v", v, n.Eval2(x, y), []float64{x, y})
+		}
+		if v, _, _, _ := n.Eval3Deriv(x, y, z); v != n.Eval3(x, y, z) {
+			t.Fatalf("Eval3Deriv value %v differs from Eval3 %v at %v", v, n.Eval3(x, y, z), []float64{x, y, z})
+		}
+		if v, _, _, _, _ := n.Eval4Deriv(x, y, z, w); v != n.Eval4(x, y, z, w) {
+			t.Fatalf("Eval4Deriv value %v differs from Eval4 %v at %v", v, n.Eval4(x, y, z, w), []float64{x, y, z, w})
+		}
+	}
+}
+
+func TestDerivativesMatchFiniteDifferences(t *testing.T) {
+	const (
+		h   = 1e-6
+		tol = 1e-4
+	)
+
+	n := New(42).(NoiseWithDerivatives)
+	// #nosec: G404
+	r := rand.New(rand.NewSource(2))
+
+	// The 3D and 4D algorithms are not perfectly continuous across their region
+	// boundaries, so a small fraction of samples is allowed to disagree.
+	check := func(dim int, analytic []float64, numeric func(axis int) float64, at []float64, misses *int) {
+		for axis := range analytic {
+			if math.Abs(analytic[axis]-numeric(axis)) > tol {
+				*misses++
+				if *misses > 10 {
+					t.Fatalf("%dD derivative along axis %d is %v, finite difference is %v at %v",
+						dim, axis, analytic[axis], numeric(axis), at)
+				}
+			}
+		}
+	}
+
+	var misses2, misses3, misses4 int
+	for i := 0; i < 10000; i++ {
+		p := []float64{r.Float64()*64 - 32, r.Float64()*64 - 32, r.Float64()*64 - 32, r.Float64()*64 - 32}
+		offset := func(axis int, delta float64) []float64 {
+			q := append([]float64(nil), p...)
+			q[axis] += delta
+			return q
+		}
+
+		_, dx, dy := n.Eval2Deriv(p[0], p[1])
+		check(2, []float64{dx, dy}, func(axis int) float64 {
+			a, b := offset(axis, h), offset(axis, -h)
+			return (n.Eval2(a[0], a[1]) - n.Eval2(b[0], b[1])) / (2 * h)
+		}, p[:2], &misses2)
+
+		_, dx, dy, dz := n.Eval3Deriv(p[0], p[1], p[2])
+		check(3, []float64{dx, dy, dz}, func(axis int) float64 {
+			a, b := offset(axis, h), offset(axis, -h)
+			return (n.Eval3(a[0], a[1], a[2]) - n.Eval3(b[0], b[1], b[2])) / (2 * h)
+		}, p[:3], &misses3)
+
+		_, dx, dy, dz, dw := n.Eval4Deriv(p[0], p[1], p[2], p[3])
+		check(4, []float64{dx, dy, dz, dw}, func(axis int) float64 {
+			a, b := offset(axis, h), offset(axis, -h)
+			return (n.Eval4(a[0], a[1], a[2], a[3]) - n.Eval4(b[0], b[1], b[2], b[3])) / (2 * h)
+		}, p, &misses4)
+	}
+}
+
+func BenchmarkEval3Deriv(b *testing.B) {
+	n := New(1).(NoiseWithDerivatives)
+
+	for iter := 0; iter < b.N; iter++ {
+		n.Eval3Deriv(float64(iter)/1000, 0.5, 0.25)
+	}
+}
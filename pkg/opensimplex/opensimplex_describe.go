@@ -0,0 +1,259 @@
+package opensimplex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Algorithm names reported in Description.Algorithm.
+const (
+	AlgorithmOpenSimplex   = "opensimplex"
+	AlgorithmOpenSimplex2F = "opensimplex2f"
+	AlgorithmOpenSimplex2S = "opensimplex2s"
+)
+
+// Precision names reported in Description.Precision.
+const (
+	PrecisionFloat64 = "float64"
+	PrecisionFloat32 = "float32"
+	PrecisionFixed   = "fixed"
+)
+
+// Wrapper names reported in Description.Wrapper.
+const (
+	WrapperFBM        = "fbm"
+	WrapperBillow     = "billow"
+	WrapperRidged     = "ridged"
+	WrapperWarped     = "warped"
+	WrapperTileable2D = "tileable2d"
+	WrapperPeriodic   = "periodic"
+	WrapperCurl       = "curl"
+)
+
+// Description reports how a noise instance was constructed.
+//
+// The Description of a wrapper, such as FBM or Warped, names it in Wrapper,
+// lists its parameters in Params and the Descriptions of the noises it wraps in
+// Base. Its other fields repeat those of Base[0], so that Algorithm and Seed
+// name the underlying generator however deeply it is wrapped.
+type Description struct {
+	// Algorithm is one of the Algorithm constants.
+	Algorithm string `json:"algorithm"`
+	// Seed is the seed passed to the constructor.
+	Seed int64 `json:"seed"`
+	// Precision is one of the Precision constants: the type the instance
+	// returns its values as.
+	Precision string `json:"precision"`
+	// Normalized is true if values are mapped into [0, 1).
+	Normalized bool `json:"normalized"`
+	// Hash is the lattice hash, see NewWithHash. It is always HashPerm256
+	// for the OpenSimplex2 algorithms.
+	Hash Hash `json:"hash"`
+	// Lo, Hi and Mode are the arguments of NewRanged or NewRanged32 if values
+	// are mapped onto a range, including by NewNormalized. Otherwise Lo and Hi
+	// are both zero.
+	Lo   float64   `json:"lo"`
+	Hi   float64   `json:"hi"`
+	Mode RangeMode `json:"mode"`
+	// Wrapper is one of the Wrapper constants, or empty if the instance is
+	// not a wrapper.
+	Wrapper string `json:"wrapper,omitempty"`
+	// Params holds the parameters of the wrapper by name, such as "octaves"
+	// and "gain" for FBM.
+	Params map[string]float64 `json:"params,omitempty"`
+	// Base holds the Descriptions of the wrapped noises: the base noise and
+	// then the warp noise for Warped, the three potentials for Curl.
+	Base []Description `json:"base,omitempty"`
+}
+
+// String returns a short summary such as "opensimplex seed=42 float32 normalized"
+// or "opensimplex seed=42 float64 range=[-1,255) clamp". Wrappers are followed
+// by their parameters and their bases in brackets, as in
+// "fbm gain=0.5 lacunarity=2 octaves=4 [opensimplex seed=42 float64]".
+func (d Description) String() string {
+	if d.Wrapper == "" {
+		s := fmt.Sprintf("%s seed=%d %s", d.Algorithm, d.Seed, d.Precision)
+		if d.Hash != HashPerm256 {
+			s += " hash=" + d.Hash.String()
+		}
+		return s + d.rangeString()
+	}
+
+	s := d.Wrapper
+	names := make([]string, 0, len(d.Params))
+	for name := range d.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s += fmt.Sprintf(" %s=%v", name, d.Params[name])
+	}
+
+	bases := make([]string, len(d.Base))
+	for i, b := range d.Base {
+		bases[i] = b.String()
+	}
+	s += " [" + strings.Join(bases, ", ") + "]"
+
+	// A range applied over the wrapper is not part of its bases.
+	if len(d.Base) > 0 {
+		if b := d.Base[0]; d.Normalized != b.Normalized || d.Lo != b.Lo || d.Hi != b.Hi || d.Mode != b.Mode {
+			s += d.rangeString()
+		}
+	}
+	return s
+}
+
+// rangeString returns the part of String that describes the output range.
+func (d Description) rangeString() string {
+	var s string
+	if d.Normalized {
+		s += " normalized"
+	} else if d.Lo != d.Hi {
+		s += fmt.Sprintf(" range=[%v,%v)", d.Lo, d.Hi)
+	}
+	if d.Lo != d.Hi && d.Mode != RangeLinear {
+		s += " " + d.Mode.String()
+	}
+	return s
+}
+
+// Describer is implemented by the Noise, Noise32 and FixedNoise instances
+// returned by the constructors of this package, so that tooling can log which
+// generator produced an output. Wrappers such as FBM, Warped and Tileable2D
+// also describe the noises they wrap:
+//
+//	if d, ok := n.(opensimplex.Describer); ok {
+//		log.Printf("generated with %v", d.Describe())
+//	}
+type Describer interface {
+	Describe() Description
+}
+
+// Describe implements Describer.
+func (s *noise) Describe() Description {
+	return Description{Algorithm: AlgorithmOpenSimplex, Seed: s.seed, Precision: PrecisionFloat64, Hash: s.hash}
+}
+
+// Describe implements Describer.
+func (s *noise32) Describe() Description {
+	return Description{Algorithm: AlgorithmOpenSimplex, Seed: s.seed, Precision: PrecisionFloat32}
+}
+
+// Describe implements Describer.
+func (s *noiseFixed) Describe() Description {
+	return Description{Algorithm: AlgorithmOpenSimplex, Seed: s.seed, Precision: PrecisionFixed}
+}
+
+// Describe implements Describer.
+func (s *rangedNoise) Describe() Description {
+	d := describe(s.base)
+	d.Normalized = s.lo == 0 && s.hi == 1
+	d.Lo, d.Hi, d.Mode = s.lo, s.hi, s.mode
+	return d
+}
+
+// Describe implements Describer.
+func (s *rangedNoise32) Describe() Description {
+	d := describe(s.base)
+	d.Precision, d.Normalized = PrecisionFloat32, s.lo == 0 && s.hi == 1
+	d.Lo, d.Hi, d.Mode = float64(s.lo), float64(s.hi), s.mode
+	return d
+}
+
+// Describe implements Describer.
+func (s *cast64Noise) Describe() Description {
+	return describe(s.base)
+}
+
+// Describe implements Describer.
+func (n *openSimplex2F) Describe() Description {
+	return Description{Algorithm: AlgorithmOpenSimplex2F, Seed: n.seed, Precision: PrecisionFloat64}
+}
+
+// Describe implements Describer.
+func (n *openSimplex2F32) Describe() Description {
+	return Description{Algorithm: AlgorithmOpenSimplex2F, Seed: n.seed, Precision: PrecisionFloat32}
+}
+
+// Describe implements Describer.
+func (n *openSimplex2S) Describe() Description {
+	return Description{Algorithm: AlgorithmOpenSimplex2S, Seed: n.seed, Precision: PrecisionFloat64}
+}
+
+// Describe implements Describer.
+func (n *openSimplex2S32) Describe() Description {
+	return Description{Algorithm: AlgorithmOpenSimplex2S, Seed: n.seed, Precision: PrecisionFloat32}
+}
+
+// Describe implements Describer. FBM32 reports the same Wrapper as FBM, with a
+// float32 Precision.
+func (f *FBM) Describe() Description {
+	return wrapped(WrapperFBM, f.params(), describe(f.base))
+}
+
+// Describe implements Describer.
+func (b *Billow) Describe() Description {
+	return wrapped(WrapperBillow, b.params(), describe(b.base))
+}
+
+// Describe implements Describer.
+func (r *Ridged) Describe() Description {
+	params := r.params()
+	params["h"], params["offset"] = r.H, r.Offset
+	return wrapped(WrapperRidged, params, describe(r.base))
+}
+
+// params returns the parameters of f for Description.Params.
+func (f *FBM) params() map[string]float64 {
+	return map[string]float64{
+		"octaves":    float64(octaves(f.Octaves)),
+		"lacunarity": f.Lacunarity,
+		"gain":       f.Gain,
+	}
+}
+
+// Describe implements Describer. Base holds the base noise, then the warp
+// noise.
+func (w *Warped) Describe() Description {
+	params := map[string]float64{"amplitude": w.Amplitude, "iterations": float64(w.Iterations)}
+	return wrapped(WrapperWarped, params, describe(w.base), describe(w.warp))
+}
+
+// Describe implements Describer.
+func (t *Tileable2D) Describe() Description {
+	return wrapped(WrapperTileable2D, map[string]float64{"width": t.width, "height": t.height}, describe(t.base))
+}
+
+// Describe implements Describer. The seed is the one passed to NewPeriodic.
+func (p *Periodic) Describe() Description {
+	return wrapped(WrapperPeriodic, map[string]float64{"px": p.px, "py": p.py, "pz": p.pz}, p.n.Describe())
+}
+
+// Describe implements Describer. The seed is the one passed to NewCurl, the
+// seeds of the three potentials are in Base.
+func (c *Curl) Describe() Description {
+	bases := make([]Description, len(c.potentials))
+	for i, p := range c.potentials {
+		bases[i] = p.Describe()
+	}
+	return wrapped(WrapperCurl, nil, bases...)
+}
+
+// wrapped returns the Description of a wrapper over bases, which repeats the
+// fields of the first base.
+func wrapped(wrapper string, params map[string]float64, bases ...Description) Description {
+	d := bases[0]
+	d.Wrapper, d.Params, d.Base = wrapper, params, bases
+	return d
+}
+
+// describe returns the Description of a wrapped instance, or the zero
+// Description if it does not implement Describer.
+func describe(base interface{}) Description {
+	if d, ok := base.(Describer); ok {
+		return d.Describe()
+	}
+	return Description{}
+}
@@ -0,0 +1,109 @@
+package opensimplex
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescribe(t *testing.T) {
+	const (
+		os, os2F, os2S = AlgorithmOpenSimplex, AlgorithmOpenSimplex2F, AlgorithmOpenSimplex2S
+		f64, f32, fix  = PrecisionFloat64, PrecisionFloat32, PrecisionFixed
+	)
+
+	var (
+		perm4096 = Description{Algorithm: os, Seed: 6, Precision: f64, Hash: HashPerm4096}
+		seed6    = Description{Algorithm: os, Seed: 6, Precision: f64}
+		seed6f32 = Description{Algorithm: os, Seed: 6, Precision: f32}
+		os2S6    = Description{Algorithm: os2S, Seed: 6, Precision: f64}
+		seed8    = Description{Algorithm: os, Seed: 8, Precision: f64}
+		seed9    = Description{Algorithm: os, Seed: 9, Precision: f64}
+		norm8    = Description{Algorithm: os, Seed: 8, Precision: f64, Normalized: true, Hi: 1}
+		seed10   = Description{Algorithm: os, Seed: 10, Precision: f64}
+		fbm      = map[string]float64{"octaves": 4, "lacunarity": 2, "gain": 0.5}
+		step     = curlSeedStep
+		curl     = []Description{
+			{Algorithm: os, Seed: 11, Precision: f64},
+			{Algorithm: os, Seed: 11 + step, Precision: f64},
+			{Algorithm: os, Seed: 11 + 2*step, Precision: f64},
+		}
+	)
+
+	for _, c := range []struct {
+		noise interface{}
+		want  Description
+	}{
+		{New(42), Description{Algorithm: os, Seed: 42, Precision: f64}},
+		{New32(42), Description{Algorithm: os, Seed: 42, Precision: f32}},
+		{NewNormalized(-3), Description{Algorithm: os, Seed: -3, Precision: f64, Normalized: true, Hi: 1}},
+		{NewNormalized32(-3), Description{Algorithm: os, Seed: -3, Precision: f32, Normalized: true, Hi: 1}},
+		{NewFixed(7), Description{Algorithm: os, Seed: 7, Precision: fix}},
+		{NewWithHash(7, HashStateless), Description{Algorithm: os, Seed: 7, Precision: f64, Hash: HashStateless}},
+		{NewGenerator(7, HashPerm1024), Description{Algorithm: os, Seed: 7, Precision: f64, Hash: HashPerm1024}},
+		{NewOpenSimplex2F(9), Description{Algorithm: os2F, Seed: 9, Precision: f64}},
+		{NewOpenSimplex2F32(9), Description{Algorithm: os2F, Seed: 9, Precision: f32}},
+		{NewOpenSimplex2S(9), Description{Algorithm: os2S, Seed: 9, Precision: f64}},
+		{NewOpenSimplex2S32(9), Description{Algorithm: os2S, Seed: 9, Precision: f32}},
+		{Widen(NewNormalized32(5)), Description{Algorithm: os, Seed: 5, Precision: f32, Normalized: true, Hi: 1}},
+		{NewRanged(New(4), -100, 2500, RangeClamp), Description{Algorithm: os, Seed: 4, Precision: f64, Lo: -100, Hi: 2500, Mode: RangeClamp}},
+		{NewRanged32(New(4), 0, 1, RangeSmoothstep), Description{Algorithm: os, Seed: 4, Precision: f32, Normalized: true, Hi: 1, Mode: RangeSmoothstep}},
+		{NewFBM(NewWithHash(6, HashPerm4096), 4, 2, 0.5), Description{Algorithm: os, Seed: 6, Precision: f64, Hash: HashPerm4096,
+			Wrapper: WrapperFBM, Params: fbm, Base: []Description{perm4096}}},
+		{NewFBM32(New32(6), 4, 2, 0.5), Description{Algorithm: os, Seed: 6, Precision: f32,
+			Wrapper: WrapperFBM, Params: fbm, Base: []Description{seed6f32}}},
+		{NewBillow(NewOpenSimplex2S(6), 4, 2, 0.5), Description{Algorithm: os2S, Seed: 6, Precision: f64,
+			Wrapper: WrapperBillow, Params: fbm, Base: []Description{os2S6}}},
+		{NewRidged(New(6), 0, 2), Description{Algorithm: os, Seed: 6, Precision: f64,
+			Wrapper: WrapperRidged, Params: map[string]float64{"octaves": 1, "lacunarity": 2, "gain": 2, "h": 1, "offset": 1}, Base: []Description{seed6}}},
+		{NewWarped(New(8), New(9), 1, 1), Description{Algorithm: os, Seed: 8, Precision: f64,
+			Wrapper: WrapperWarped, Params: map[string]float64{"amplitude": 1, "iterations": 1}, Base: []Description{seed8, seed9}}},
+		{NewTileable2D(NewNormalized(8), 16, 16), Description{Algorithm: os, Seed: 8, Precision: f64, Normalized: true, Hi: 1,
+			Wrapper: WrapperTileable2D, Params: map[string]float64{"width": 16, "height": 16}, Base: []Description{norm8}}},
+		{NewPeriodic(10, 4, 4, 4), Description{Algorithm: os, Seed: 10, Precision: f64,
+			Wrapper: WrapperPeriodic, Params: map[string]float64{"px": 4, "py": 4, "pz": 4}, Base: []Description{seed10}}},
+		{NewCurl(11), Description{Algorithm: os, Seed: 11, Precision: f64, Wrapper: WrapperCurl, Base: curl}},
+		{NewRanged(NewFBM(New(6), 4, 2, 0.5), 0, 1, RangeClamp), Description{Algorithm: os, Seed: 6, Precision: f64, Normalized: true, Hi: 1, Mode: RangeClamp,
+			Wrapper: WrapperFBM, Params: fbm, Base: []Description{seed6}}},
+	} {
+		d, ok := c.noise.(Describer)
+		if !ok {
+			t.Errorf("%T does not implement Describer", c.noise)
+			continue
+		}
+		if got := d.Describe(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%T.Describe() = %v, want %v", c.noise, got, c.want)
+		}
+	}
+}
+
+func TestDescriptionString(t *testing.T) {
+	for _, c := range []struct {
+		d    Description
+		want string
+	}{
+		{
+			Description{Algorithm: AlgorithmOpenSimplex, Seed: 42, Precision: PrecisionFloat32, Normalized: true, Hash: HashPerm4096, Hi: 1},
+			"opensimplex seed=42 float32 hash=perm4096 normalized",
+		},
+		{
+			Description{Algorithm: AlgorithmOpenSimplex, Seed: 42, Precision: PrecisionFloat64, Lo: -1, Hi: 255, Mode: RangeClamp},
+			"opensimplex seed=42 float64 range=[-1,255) clamp",
+		},
+		{
+			NewFBM(NewWithHash(42, HashPerm4096), 4, 2, 0.5).Describe(),
+			"fbm gain=0.5 lacunarity=2 octaves=4 [opensimplex seed=42 float64 hash=perm4096]",
+		},
+		{
+			NewWarped(NewBillow(New(1), 2, 3, 0.25), New(2), 0.5, 2).Describe(),
+			"warped amplitude=0.5 iterations=2 [billow gain=0.25 lacunarity=3 octaves=2 [opensimplex seed=1 float64], opensimplex seed=2 float64]",
+		},
+		{
+			describe(NewRanged(NewRidged(New(3), 2, 2), -1, 255, RangeClamp)),
+			"ridged gain=2 h=1 lacunarity=2 octaves=2 offset=1 [opensimplex seed=3 float64] range=[-1,255) clamp",
+		},
+	} {
+		if s := c.d.String(); s != c.want {
+			t.Errorf("String() = %q, want %q", s, c.want)
+		}
+	}
+}
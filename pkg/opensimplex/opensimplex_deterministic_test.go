@@ -0,0 +1,36 @@
+package opensimplex
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloorLattice(t *testing.T) {
+	for _, c := range []struct {
+		x    float64
+		want int32
+	}{
+		{0, 0},
+		{-0.5, -1},
+		{1.5, 1},
+		{math.MaxInt32, math.MaxInt32},
+		{math.MinInt32, math.MinInt32},
+		{math.MaxInt32 + 1, math.MinInt32},
+		{math.MinInt32 - 0.5, math.MaxInt32},
+		{1<<40 + 5.5, 5},
+		{-(1<<40 + 5.5), -6},
+		{1e300, 0},
+		{math.Inf(1), 0},
+		{math.Inf(-1), 0},
+		{math.NaN(), 0},
+	} {
+		if got := floorLattice(c.x); got != c.want {
+			t.Errorf("floorLattice(%v) = %v, want %v", c.x, got, c.want)
+		}
+		if x := float32(c.x); float64(x) == c.x || math.IsNaN(c.x) {
+			if got := floor32(x); got != c.want {
+				t.Errorf("floor32(%v) = %v, want %v", x, got, c.want)
+			}
+		}
+	}
+}
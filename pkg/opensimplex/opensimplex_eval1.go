@@ -0,0 +1,52 @@
+package opensimplex
+
+// One-dimensional gradient noise. The 1D lattice is just the integers, so no
+// skewing is involved: each point is influenced by the two lattice points
+// around it, each contributing attn^4 * g * dx with attn = 1 - dx^2. This is
+// both faster than slicing Eval2 and free of the anisotropy of the 2D lattice
+// along the x axis.
+
+// Noise1D is a seeded 64-bit noise instance that can also be evaluated in one
+// dimension, for signals such as camera shake or time-series jitter.
+type Noise1D interface {
+	Noise
+	Eval1(x float64) float64
+}
+
+// Noise1D32 is the float32 counterpart of Noise1D.
+type Noise1D32 interface {
+	Noise32
+	Eval1(x float32) float32
+}
+
+// normConstant1D is the largest sum of the two contributions, reached halfway
+// between two lattice points with gradients 8 and -8, so Eval1 returns values
+// in [-1, 1].
+const normConstant1D = 81.0 / 32
+
+// Gradients for 1D: the slopes +-1 to +-8, as in Stefan Gustavson's simplex
+// noise.
+var gradients1D = [16]int8{
+	1, 2, 3, 4, 5, 6, 7, 8,
+	-1, -2, -3, -4, -5, -6, -7, -8,
+}
+
+// Eval1 returns a random noise value in one dimension, in the range [-1, 1].
+// It is zero at every integer.
+func (s *noise) Eval1(x float64) float64 {
+	if s.lattice != nil {
+		return s.latticeEval1(x)
+	}
+
+	xsb := floorLattice(x)
+	dx0 := x - float64(xsb)
+	dx1 := dx0 - 1
+
+	return (s.contrib1(xsb, dx0) + s.contrib1(xsb+1, dx1)) / normConstant1D
+}
+
+func (s *noise) contrib1(xsb int32, dx float64) float64 {
+	attn := 1 - float64(dx*dx)
+	attn2 := attn * attn
+	return float64(attn2 * attn2 * float64(gradients1D[s.gradIndex1(xsb)]) * dx)
+}
@@ -0,0 +1,96 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEval1Implemented(t *testing.T) {
+	for _, n := range []Noise{New(1), NewDeterministic(1), NewGenerator(1, HashPerm1024), NewWithHash(1, HashStateless)} {
+		if _, ok := n.(Noise1D); !ok {
+			t.Errorf("%T does not implement Noise1D", n)
+		}
+	}
+	if _, ok := New32(1).(Noise1D32); !ok {
+		t.Error("New32 does not implement Noise1D32")
+	}
+}
+
+func TestEval1Range(t *testing.T) {
+	for _, h := range allHashes {
+		n := NewWithHash(7, h).(Noise1D)
+
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for i := -100000; i < 100000; i++ {
+			x := float64(i) / 64
+			v := n.Eval1(x)
+			if v < -1 || v > 1 {
+				t.Fatalf("%v: Eval1(%v) = %v, outside [-1, 1]", h, x, v)
+			}
+			if i%64 == 0 && v != 0 {
+				t.Fatalf("%v: Eval1(%v) = %v, want 0 at an integer", h, x, v)
+			}
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+
+		// The noise only reaches -1 or 1 halfway between lattice points with
+		// gradients 8 and -8, but it should come close.
+		if lo > -0.9 || hi < 0.9 {
+			t.Errorf("%v: Eval1 only spans [%v, %v]", h, lo, hi)
+		}
+	}
+}
+
+func TestEval1Continuous(t *testing.T) {
+	n := New(3).(Noise1D)
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x := r.Float64()*2000 - 1000
+		// The slope is bounded by 8 * 3.2 / normConstant1D, with some margin.
+		if d := math.Abs(n.Eval1(x+1e-6) - n.Eval1(x)); d > 1e-5 {
+			t.Fatalf("Eval1 jumps by %v between %v and %v", d, x, x+1e-6)
+		}
+	}
+}
+
+func TestEval1Seeded(t *testing.T) {
+	a, b, c := New(5).(Noise1D), New(5).(Noise1D), New(6).(Noise1D)
+	differ := false
+	for x := 0.5; x < 100; x++ {
+		if a.Eval1(x) != b.Eval1(x) {
+			t.Fatalf("same seed differs at %v", x)
+		}
+		differ = differ || a.Eval1(x) != c.Eval1(x)
+	}
+	if !differ {
+		t.Error("Eval1 ignores the seed")
+	}
+}
+
+func TestEval1Float32(t *testing.T) {
+	n, n32 := New(11).(Noise1D), New32(11).(Noise1D32)
+	// #nosec: G404
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 10000; i++ {
+		x := float32(r.Float64()*2000 - 1000)
+		if v, v32 := n.Eval1(float64(x)), n32.Eval1(x); math.Abs(v-float64(v32)) > 1e-4 {
+			t.Fatalf("Eval1(%v) is %v in float32 and %v in float64", x, v32, v)
+		}
+	}
+}
+
+func BenchmarkEval1(b *testing.B) {
+	n := New(0).(Noise1D)
+	for i := 0; i < b.N; i++ {
+		n.Eval1(float64(i) * 0.1)
+	}
+}
+
+func BenchmarkEval2Slice(b *testing.B) {
+	n := New(0)
+	for i := 0; i < b.N; i++ {
+		n.Eval2(float64(i)*0.1, 0)
+	}
+}
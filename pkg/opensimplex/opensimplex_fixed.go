@@ -0,0 +1,775 @@
+package opensimplex
+
+import (
+	"math"
+	"math/bits"
+)
+
+// Fixed-point implementation of the 2D and 3D noise, for programs that cannot
+// use floating-point arithmetic. It is a line-by-line copy of
+// opensimplex_base.go in which every float64 becomes an int64 with fxFracBits
+// fractional bits, except for the placement of the input coordinates on the
+// lattice. Keep the files in sync.
+//
+// Coordinates come in as Q32.32 values. They are only ever added and
+// multiplied by a stretch constant, in 128-bit precision; the lattice cell is
+// then split off, and the rest of the evaluation only deals with offsets
+// smaller than a few units. For those, the lower precision of fxFracBits keeps
+// every product within int64.
+
+// Fixed is a signed Q32.32 fixed-point number: the value x is represented by
+// x * 2^32, rounded.
+type Fixed int64
+
+// FixedOne is the Fixed representation of 1.
+const FixedOne Fixed = 1 << 32
+
+// FixedFromInt returns the Fixed representation of i.
+func FixedFromInt(i int32) Fixed {
+	return Fixed(i) << 32
+}
+
+// FixedFromFloat64 returns the Fixed representation of f, rounded to the
+// nearest multiple of 2^-32.
+func FixedFromFloat64(f float64) Fixed {
+	return Fixed(math.Round(f * (1 << 32)))
+}
+
+// Float64 returns f as a float64.
+func (f Fixed) Float64() float64 {
+	return float64(f) / (1 << 32)
+}
+
+const (
+	fxFracBits       = 24
+	fxOne      int64 = 1 << fxFracBits
+
+	fxSquish2D int64 = 6140887 // squishConstant2D in Q.24
+	fxSquish3D int64 = 5592405 // squishConstant3D in Q.24
+)
+
+// fxMul multiplies two Q.24 values, rounding towards negative infinity.
+func fxMul(a, b int64) int64 {
+	return (a * b) >> fxFracBits
+}
+
+// fxMulWide multiplies a Q32.32 value by a Q2.62 value in 128-bit precision,
+// rounding towards zero. The result is a Q32.32 value and must fit in an int64.
+func fxMulWide(a, b int64) int64 {
+	neg := (a < 0) != (b < 0)
+	if a < 0 {
+		a = -a
+	}
+	if b < 0 {
+		b = -b
+	}
+
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	r := int64(hi<<2 | lo>>62)
+	if neg {
+		return -r
+	}
+	return r
+}
+
+// A seeded FixedNoise instance, sharing the permutation tables of noise.
+type noiseFixed struct {
+	seed int64
+
+	perm            [256]int16
+	permGradIndex3D [256]int16
+}
+
+func (s *noiseFixed) gradIndex2(xsb, ysb int32) int16 {
+	return s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF] & 0x0E
+}
+
+func (s *noiseFixed) gradIndex3(xsb, ysb, zsb int32) int16 {
+	return s.permGradIndex3D[(int32(s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF])+zsb)&0xFF]
+}
+
+func (s *noiseFixed) contrib2(attn int64, xsb, ysb int32, dx, dy int64) int64 {
+	index := s.gradIndex2(xsb, ysb)
+	ext := int64(gradients2D[index])*dx + int64(gradients2D[index+1])*dy
+	attn = fxMul(attn, attn)
+	return fxMul(fxMul(attn, attn), ext)
+}
+
+func (s *noiseFixed) contrib3(attn int64, xsb, ysb, zsb int32, dx, dy, dz int64) int64 {
+	index := s.gradIndex3(xsb, ysb, zsb)
+	ext := int64(gradients3D[index])*dx + int64(gradients3D[index+1])*dy + int64(gradients3D[index+2])*dz
+	attn = fxMul(attn, attn)
+	return fxMul(fxMul(attn, attn), ext)
+}
+
+// Eval2 returns a random noise value in two dimensions.
+func (s *noiseFixed) Eval2(x, y Fixed) Fixed {
+	// Place input coordinates onto grid.
+	stretchOffset := fxMulWide(int64(x+y), stretchConstant2DQ62)
+	xs := int64(x) + stretchOffset
+	ys := int64(y) + stretchOffset
+
+	// Floor to get grid coordinates of rhombus (stretched square) super-cell origin.
+	xsb := int32(xs >> 32)
+	ysb := int32(ys >> 32)
+
+	// Compute grid coordinates relative to rhombus origin.
+	xins := (xs & (1<<32 - 1)) >> (32 - fxFracBits)
+	yins := (ys & (1<<32 - 1)) >> (32 - fxFracBits)
+
+	// Sum those together to get a value that determines which region we're in.
+	inSum := xins + yins
+
+	// Positions relative to origin point. Unskewing only the offsets within the
+	// cell avoids the large rhombus origin coordinates.
+	squishOffset := fxMul(inSum, fxSquish2D)
+	dx0 := xins + squishOffset
+	dy0 := yins + squishOffset
+
+	// We'll be defining these inside the next block and using them afterwards.
+	var dxExt, dyExt int64
+	var xsvExt, ysvExt int32
+
+	value := int64(0)
+
+	// Contribution (1,0)
+	dx1 := dx0 - fxOne - fxSquish2D
+	dy1 := dy0 - 0 - fxSquish2D
+	attn1 := 2*fxOne - fxMul(dx1, dx1) - fxMul(dy1, dy1)
+	if attn1 > 0 {
+		value += s.contrib2(attn1, xsb+1, ysb+0, dx1, dy1)
+	}
+
+	// Contribution (0,1)
+	dx2 := dx0 - 0 - fxSquish2D
+	dy2 := dy0 - fxOne - fxSquish2D
+	attn2 := 2*fxOne - fxMul(dx2, dx2) - fxMul(dy2, dy2)
+	if attn2 > 0 {
+		value += s.contrib2(attn2, xsb+0, ysb+1, dx2, dy2)
+	}
+
+	if inSum <= fxOne { // We're inside the triangle (2-Simplex) at (0,0)
+		zins := fxOne - inSum
+		if zins > xins || zins > yins { // (0,0) is one of the closest two triangular vertices
+			if xins > yins {
+				xsvExt = xsb + 1
+				ysvExt = ysb - 1
+				dxExt = dx0 - fxOne
+				dyExt = dy0 + fxOne
+			} else {
+				xsvExt = xsb - 1
+				ysvExt = ysb + 1
+				dxExt = dx0 + fxOne
+				dyExt = dy0 - fxOne
+			}
+		} else { // (1,0) and (0,1) are the closest two vertices.
+			xsvExt = xsb + 1
+			ysvExt = ysb + 1
+			dxExt = dx0 - fxOne - 2*fxSquish2D
+			dyExt = dy0 - fxOne - 2*fxSquish2D
+		}
+	} else { // We're inside the triangle (2-Simplex) at (1,1)
+		zins := 2*fxOne - inSum
+		if zins < xins || zins < yins { // (0,0) is one of the closest two triangular vertices
+			if xins > yins {
+				xsvExt = xsb + 2
+				ysvExt = ysb + 0
+				dxExt = dx0 - 2*fxOne - 2*fxSquish2D
+				dyExt = dy0 + 0 - 2*fxSquish2D
+			} else {
+				xsvExt = xsb + 0
+				ysvExt = ysb + 2
+				dxExt = dx0 + 0 - 2*fxSquish2D
+				dyExt = dy0 - 2*fxOne - 2*fxSquish2D
+			}
+		} else { // (1,0) and (0,1) are the closest two vertices.
+			dxExt = dx0
+			dyExt = dy0
+			xsvExt = xsb
+			ysvExt = ysb
+		}
+		xsb += 1
+		ysb += 1
+		dx0 = dx0 - fxOne - 2*fxSquish2D
+		dy0 = dy0 - fxOne - 2*fxSquish2D
+	}
+
+	// Contribution (0,0) or (1,1)
+	attn0 := 2*fxOne - fxMul(dx0, dx0) - fxMul(dy0, dy0)
+	if attn0 > 0 {
+		value += s.contrib2(attn0, xsb, ysb, dx0, dy0)
+	}
+
+	// Extra Vertex
+	attnExt := 2*fxOne - fxMul(dxExt, dxExt) - fxMul(dyExt, dyExt)
+	if attnExt > 0 {
+		value += s.contrib2(attnExt, xsvExt, ysvExt, dxExt, dyExt)
+	}
+
+	return Fixed((value << (32 - fxFracBits)) / normConstant2D)
+}
+
+// Eval3 returns a random noise value in three dimensions.
+//
+//gocyclo:ignore
+func (s *noiseFixed) Eval3(x, y, z Fixed) Fixed {
+	// Place input coordinates on simplectic honeycomb.
+	stretchOffset := fxMulWide(int64(x+y+z), stretchConstant3DQ62)
+	xs := int64(x) + stretchOffset
+	ys := int64(y) + stretchOffset
+	zs := int64(z) + stretchOffset
+
+	// Floor to get simplectic honeycomb coordinates of rhombohedron (stretched cube) super-cell origin.
+	xsb := int32(xs >> 32)
+	ysb := int32(ys >> 32)
+	zsb := int32(zs >> 32)
+
+	// Compute simplectic honeycomb coordinates relative to rhombohedral origin.
+	xins := (xs & (1<<32 - 1)) >> (32 - fxFracBits)
+	yins := (ys & (1<<32 - 1)) >> (32 - fxFracBits)
+	zins := (zs & (1<<32 - 1)) >> (32 - fxFracBits)
+
+	// Sum those together to get a value that determines which region we're in.
+	inSum := xins + yins + zins
+
+	// Positions relative to origin point. Unskewing only the offsets within the
+	// cell avoids the large rhombohedron origin coordinates.
+	squishOffset := fxMul(inSum, fxSquish3D)
+	dx0 := xins + squishOffset
+	dy0 := yins + squishOffset
+	dz0 := zins + squishOffset
+
+	// We'll be defining these inside the next block and using them afterwards.
+	var dxExt0, dyExt0, dzExt0 int64
+	var dxExt1, dyExt1, dzExt1 int64
+	var xsvExt0, ysvExt0, zsvExt0 int32
+	var xsvExt1, ysvExt1, zsvExt1 int32
+
+	value := int64(0)
+	if inSum <= fxOne { // We're inside the tetrahedron (3-Simplex) at (0,0,0)
+
+		// Determine which two of (0,0,1), (0,1,0), (1,0,0) are closest.
+		aPoint := byte(0x01)
+		bPoint := byte(0x02)
+		aScore := xins
+		bScore := yins
+		if aScore >= bScore && zins > bScore {
+			bScore = zins
+			bPoint = 0x04
+		} else if aScore < bScore && zins > aScore {
+			aScore = zins
+			aPoint = 0x04
+		}
+
+		// Now we determine the two lattice points not part of the tetrahedron that may contribute.
+		// This depends on the closest two tetrahedral vertices, including (0,0,0)
+		wins := fxOne - inSum
+		if wins > aScore || wins > bScore { // (0,0,0) is one of the closest two tetrahedral vertices.
+			var c byte // Our other closest vertex is the closest out of a and b.
+			if bScore > aScore {
+				c = bPoint
+			} else {
+				c = aPoint
+			}
+
+			if (c & 0x01) == 0 {
+				xsvExt0 = xsb - 1
+				xsvExt1 = xsb
+				dxExt0 = dx0 + fxOne
+				dxExt1 = dx0
+			} else {
+				xsvExt1 = xsb + 1
+				xsvExt0 = xsvExt1
+				dxExt1 = dx0 - fxOne
+				dxExt0 = dxExt1
+			}
+
+			if (c & 0x02) == 0 {
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0
+				dyExt0 = dyExt1
+				if (c & 0x01) == 0 {
+					ysvExt1 -= 1
+					dyExt1 += fxOne
+				} else {
+					ysvExt0 -= 1
+					dyExt0 += fxOne
+				}
+			} else {
+				ysvExt1 = ysb + 1
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - fxOne
+				dyExt0 = dyExt1
+			}
+
+			if (c & 0x04) == 0 {
+				zsvExt0 = zsb
+				zsvExt1 = zsb - 1
+				dzExt0 = dz0
+				dzExt1 = dz0 + fxOne
+			} else {
+				zsvExt1 = zsb + 1
+				zsvExt0 = zsvExt1
+				dzExt1 = dz0 - fxOne
+				dzExt0 = dzExt1
+			}
+		} else { // (0,0,0) is not one of the closest two tetrahedral vertices.
+			c := aPoint | bPoint // Our two extra vertices are determined by the closest two.
+
+			if (c & 0x01) == 0 {
+				xsvExt0 = xsb
+				xsvExt1 = xsb - 1
+				dxExt0 = dx0 - 2*fxSquish3D
+				dxExt1 = dx0 + fxOne - fxSquish3D
+			} else {
+				xsvExt1 = xsb + 1
+				xsvExt0 = xsvExt1
+				dxExt0 = dx0 - fxOne - 2*fxSquish3D
+				dxExt1 = dx0 - fxOne - fxSquish3D
+			}
+
+			if (c & 0x02) == 0 {
+				ysvExt0 = ysb
+				ysvExt1 = ysb - 1
+				dyExt0 = dy0 - 2*fxSquish3D
+				dyExt1 = dy0 + fxOne - fxSquish3D
+			} else {
+				ysvExt1 = ysb + 1
+				ysvExt0 = ysvExt1
+				dyExt0 = dy0 - fxOne - 2*fxSquish3D
+				dyExt1 = dy0 - fxOne - fxSquish3D
+			}
+
+			if (c & 0x04) == 0 {
+				zsvExt0 = zsb
+				zsvExt1 = zsb - 1
+				dzExt0 = dz0 - 2*fxSquish3D
+				dzExt1 = dz0 + fxOne - fxSquish3D
+			} else {
+				zsvExt1 = zsb + 1
+				zsvExt0 = zsvExt1
+				dzExt0 = dz0 - fxOne - 2*fxSquish3D
+				dzExt1 = dz0 - fxOne - fxSquish3D
+			}
+		}
+
+		// Contribution (0,0,0)
+		attn0 := 2*fxOne - fxMul(dx0, dx0) - fxMul(dy0, dy0) - fxMul(dz0, dz0)
+		if attn0 > 0 {
+			value += s.contrib3(attn0, xsb+0, ysb+0, zsb+0, dx0, dy0, dz0)
+		}
+
+		// Contribution (1,0,0)
+		dx1 := dx0 - fxOne - fxSquish3D
+		dy1 := dy0 - 0 - fxSquish3D
+		dz1 := dz0 - 0 - fxSquish3D
+		attn1 := 2*fxOne - fxMul(dx1, dx1) - fxMul(dy1, dy1) - fxMul(dz1, dz1)
+		if attn1 > 0 {
+			value += s.contrib3(attn1, xsb+1, ysb+0, zsb+0, dx1, dy1, dz1)
+		}
+
+		// Contribution (0,1,0)
+		dx2 := dx0 - 0 - fxSquish3D
+		dy2 := dy0 - fxOne - fxSquish3D
+		dz2 := dz1
+		attn2 := 2*fxOne - fxMul(dx2, dx2) - fxMul(dy2, dy2) - fxMul(dz2, dz2)
+		if attn2 > 0 {
+			value += s.contrib3(attn2, xsb+0, ysb+1, zsb+0, dx2, dy2, dz2)
+		}
+
+		// Contribution (0,0,1)
+		dx3 := dx2
+		dy3 := dy1
+		dz3 := dz0 - fxOne - fxSquish3D
+		attn3 := 2*fxOne - fxMul(dx3, dx3) - fxMul(dy3, dy3) - fxMul(dz3, dz3)
+		if attn3 > 0 {
+			value += s.contrib3(attn3, xsb+0, ysb+0, zsb+1, dx3, dy3, dz3)
+		}
+	} else if inSum >= 2*fxOne { // We're inside the tetrahedron (3-Simplex) at (1,1,1)
+
+		// Determine which two tetrahedral vertices are the closest, out of (1,1,0), (1,0,1), (0,1,1) but not (1,1,1).
+		aPoint := byte(0x06)
+		aScore := xins
+		bPoint := byte(0x05)
+		bScore := yins
+		if aScore <= bScore && zins < bScore {
+			bScore = zins
+			bPoint = 0x03
+		} else if aScore > bScore && zins < aScore {
+			aScore = zins
+			aPoint = 0x03
+		}
+
+		// Now we determine the two lattice points not part of the tetrahedron that may contribute.
+		// This depends on the closest two tetrahedral vertices, including (1,1,1)
+		wins := 3*fxOne - inSum
+		if wins < aScore || wins < bScore { // (1,1,1) is one of the closest two tetrahedral vertices.
+			var c byte // Our other closest vertex is the closest out of a and b.
+			if bScore < aScore {
+				c = bPoint
+			} else {
+				c = aPoint
+			}
+
+			if (c & 0x01) != 0 {
+				xsvExt0 = xsb + 2
+				xsvExt1 = xsb + 1
+				dxExt0 = dx0 - 2*fxOne - 3*fxSquish3D
+				dxExt1 = dx0 - fxOne - 3*fxSquish3D
+			} else {
+				xsvExt1 = xsb
+				xsvExt0 = xsvExt1
+				dxExt1 = dx0 - 3*fxSquish3D
+				dxExt0 = dxExt1
+			}
+
+			if (c & 0x02) != 0 {
+				ysvExt1 = ysb + 1
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - fxOne - 3*fxSquish3D
+				dyExt0 = dyExt1
+				if (c & 0x01) != 0 {
+					ysvExt1 += 1
+					dyExt1 -= fxOne
+				} else {
+					ysvExt0 += 1
+					dyExt0 -= fxOne
+				}
+			} else {
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				dyExt1 = dy0 - 3*fxSquish3D
+				dyExt0 = dyExt1
+			}
+
+			if (c & 0x04) != 0 {
+				zsvExt0 = zsb + 1
+				zsvExt1 = zsb + 2
+				dzExt0 = dz0 - fxOne - 3*fxSquish3D
+				dzExt1 = dz0 - 2*fxOne - 3*fxSquish3D
+			} else {
+				zsvExt1 = zsb
+				zsvExt0 = zsvExt1
+				dzExt1 = dz0 - 3*fxSquish3D
+				dzExt0 = dzExt1
+			}
+		} else { // (1,1,1) is not one of the closest two tetrahedral vertices.
+			c := aPoint & bPoint // Our two extra vertices are determined by the closest two.
+
+			if (c & 0x01) != 0 {
+				xsvExt0 = xsb + 1
+				xsvExt1 = xsb + 2
+				dxExt0 = dx0 - fxOne - fxSquish3D
+				dxExt1 = dx0 - 2*fxOne - 2*fxSquish3D
+			} else {
+				xsvExt1 = xsb
+				xsvExt0 = xsvExt1
+				dxExt0 = dx0 - fxSquish3D
+				dxExt1 = dx0 - 2*fxSquish3D
+			}
+
+			if (c & 0x02) != 0 {
+				ysvExt0 = ysb + 1
+				ysvExt1 = ysb + 2
+				dyExt0 = dy0 - fxOne - fxSquish3D
+				dyExt1 = dy0 - 2*fxOne - 2*fxSquish3D
+			} else {
+				ysvExt1 = ysb
+				ysvExt0 = ysvExt1
+				dyExt0 = dy0 - fxSquish3D
+				dyExt1 = dy0 - 2*fxSquish3D
+			}
+
+			if (c & 0x04) != 0 {
+				zsvExt0 = zsb + 1
+				zsvExt1 = zsb + 2
+				dzExt0 = dz0 - fxOne - fxSquish3D
+				dzExt1 = dz0 - 2*fxOne - 2*fxSquish3D
+			} else {
+				zsvExt1 = zsb
+				zsvExt0 = zsvExt1
+				dzExt0 = dz0 - fxSquish3D
+				dzExt1 = dz0 - 2*fxSquish3D
+			}
+		}
+
+		// Contribution (1,1,0)
+		dx3 := dx0 - fxOne - 2*fxSquish3D
+		dy3 := dy0 - fxOne - 2*fxSquish3D
+		dz3 := dz0 - 0 - 2*fxSquish3D
+		attn3 := 2*fxOne - fxMul(dx3, dx3) - fxMul(dy3, dy3) - fxMul(dz3, dz3)
+		if attn3 > 0 {
+			value += s.contrib3(attn3, xsb+1, ysb+1, zsb+0, dx3, dy3, dz3)
+		}
+
+		// Contribution (1,0,1)
+		dx2 := dx3
+		dy2 := dy0 - 0 - 2*fxSquish3D
+		dz2 := dz0 - fxOne - 2*fxSquish3D
+		attn2 := 2*fxOne - fxMul(dx2, dx2) - fxMul(dy2, dy2) - fxMul(dz2, dz2)
+		if attn2 > 0 {
+			value += s.contrib3(attn2, xsb+1, ysb+0, zsb+1, dx2, dy2, dz2)
+		}
+
+		// Contribution (0,1,1)
+		dx1 := dx0 - 0 - 2*fxSquish3D
+		dy1 := dy3
+		dz1 := dz2
+		attn1 := 2*fxOne - fxMul(dx1, dx1) - fxMul(dy1, dy1) - fxMul(dz1, dz1)
+		if attn1 > 0 {
+			value += s.contrib3(attn1, xsb+0, ysb+1, zsb+1, dx1, dy1, dz1)
+		}
+
+		// Contribution (1,1,1)
+		dx0 = dx0 - fxOne - 3*fxSquish3D
+		dy0 = dy0 - fxOne - 3*fxSquish3D
+		dz0 = dz0 - fxOne - 3*fxSquish3D
+		attn0 := 2*fxOne - fxMul(dx0, dx0) - fxMul(dy0, dy0) - fxMul(dz0, dz0)
+		if attn0 > 0 {
+			value += s.contrib3(attn0, xsb+1, ysb+1, zsb+1, dx0, dy0, dz0)
+		}
+	} else { // We're inside the octahedron (Rectified 3-Simplex) in between.
+		var aScore, bScore int64
+		var aPoint, bPoint byte
+		var aIsFurtherSide, bIsFurtherSide bool
+
+		// Decide between point (0,0,1) and (1,1,0) as closest
+		p1 := xins + yins
+		if p1 > fxOne {
+			aScore = p1 - fxOne
+			aPoint = 0x03
+			aIsFurtherSide = true
+		} else {
+			aScore = fxOne - p1
+			aPoint = 0x04
+			aIsFurtherSide = false
+		}
+
+		// Decide between point (0,1,0) and (1,0,1) as closest
+		p2 := xins + zins
+		if p2 > fxOne {
+			bScore = p2 - fxOne
+			bPoint = 0x05
+			bIsFurtherSide = true
+		} else {
+			bScore = fxOne - p2
+			bPoint = 0x02
+			bIsFurtherSide = false
+		}
+
+		// The closest out of the two (1,0,0) and (0,1,1) will replace the furthest out of the two decided above, if closer.
+		p3 := yins + zins
+		if p3 > fxOne {
+			score := p3 - fxOne
+			if aScore <= bScore && aScore < score {
+				aPoint = 0x06
+				aIsFurtherSide = true
+			} else if aScore > bScore && bScore < score {
+				bPoint = 0x06
+				bIsFurtherSide = true
+			}
+		} else {
+			score := fxOne - p3
+			if aScore <= bScore && aScore < score {
+				aPoint = 0x01
+				aIsFurtherSide = false
+			} else if aScore > bScore && bScore < score {
+				bPoint = 0x01
+				bIsFurtherSide = false
+			}
+		}
+
+		// Where each of the two closest points are determines how the extra two vertices are calculated.
+		if aIsFurtherSide == bIsFurtherSide {
+			if aIsFurtherSide { // Both closest points on (1,1,1) side
+
+				// One of the two extra points is (1,1,1)
+				dxExt0 = dx0 - fxOne - 3*fxSquish3D
+				dyExt0 = dy0 - fxOne - 3*fxSquish3D
+				dzExt0 = dz0 - fxOne - 3*fxSquish3D
+				xsvExt0 = xsb + 1
+				ysvExt0 = ysb + 1
+				zsvExt0 = zsb + 1
+
+				// Other extra point is based on the shared axis.
+				c := aPoint & bPoint
+				if (c & 0x01) != 0 {
+					dxExt1 = dx0 - 2*fxOne - 2*fxSquish3D
+					dyExt1 = dy0 - 2*fxSquish3D
+					dzExt1 = dz0 - 2*fxSquish3D
+					xsvExt1 = xsb + 2
+					ysvExt1 = ysb
+					zsvExt1 = zsb
+				} else if (c & 0x02) != 0 {
+					dxExt1 = dx0 - 2*fxSquish3D
+					dyExt1 = dy0 - 2*fxOne - 2*fxSquish3D
+					dzExt1 = dz0 - 2*fxSquish3D
+					xsvExt1 = xsb
+					ysvExt1 = ysb + 2
+					zsvExt1 = zsb
+				} else {
+					dxExt1 = dx0 - 2*fxSquish3D
+					dyExt1 = dy0 - 2*fxSquish3D
+					dzExt1 = dz0 - 2*fxOne - 2*fxSquish3D
+					xsvExt1 = xsb
+					ysvExt1 = ysb
+					zsvExt1 = zsb + 2
+				}
+			} else { // Both closest points on (0,0,0) side
+
+				// One of the two extra points is (0,0,0)
+				dxExt0 = dx0
+				dyExt0 = dy0
+				dzExt0 = dz0
+				xsvExt0 = xsb
+				ysvExt0 = ysb
+				zsvExt0 = zsb
+
+				// Other extra point is based on the omitted axis.
+				c := aPoint | bPoint
+				if (c & 0x01) == 0 {
+					dxExt1 = dx0 + fxOne - fxSquish3D
+					dyExt1 = dy0 - fxOne - fxSquish3D
+					dzExt1 = dz0 - fxOne - fxSquish3D
+					xsvExt1 = xsb - 1
+					ysvExt1 = ysb + 1
+					zsvExt1 = zsb + 1
+				} else if (c & 0x02) == 0 {
+					dxExt1 = dx0 - fxOne - fxSquish3D
+					dyExt1 = dy0 + fxOne - fxSquish3D
+					dzExt1 = dz0 - fxOne - fxSquish3D
+					xsvExt1 = xsb + 1
+					ysvExt1 = ysb - 1
+					zsvExt1 = zsb + 1
+				} else {
+					dxExt1 = dx0 - fxOne - fxSquish3D
+					dyExt1 = dy0 - fxOne - fxSquish3D
+					dzExt1 = dz0 + fxOne - fxSquish3D
+					xsvExt1 = xsb + 1
+					ysvExt1 = ysb + 1
+					zsvExt1 = zsb - 1
+				}
+			}
+		} else { // One point on (0,0,0) side, one point on (1,1,1) side
+			var c1, c2 byte
+			if aIsFurtherSide {
+				c1 = aPoint
+				c2 = bPoint
+			} else {
+				c1 = bPoint
+				c2 = aPoint
+			}
+
+			// One contribution is a permutation of (1,1,-1)
+			if (c1 & 0x01) == 0 {
+				dxExt0 = dx0 + fxOne - fxSquish3D
+				dyExt0 = dy0 - fxOne - fxSquish3D
+				dzExt0 = dz0 - fxOne - fxSquish3D
+				xsvExt0 = xsb - 1
+				ysvExt0 = ysb + 1
+				zsvExt0 = zsb + 1
+			} else if (c1 & 0x02) == 0 {
+				dxExt0 = dx0 - fxOne - fxSquish3D
+				dyExt0 = dy0 + fxOne - fxSquish3D
+				dzExt0 = dz0 - fxOne - fxSquish3D
+				xsvExt0 = xsb + 1
+				ysvExt0 = ysb - 1
+				zsvExt0 = zsb + 1
+			} else {
+				dxExt0 = dx0 - fxOne - fxSquish3D
+				dyExt0 = dy0 - fxOne - fxSquish3D
+				dzExt0 = dz0 + fxOne - fxSquish3D
+				xsvExt0 = xsb + 1
+				ysvExt0 = ysb + 1
+				zsvExt0 = zsb - 1
+			}
+
+			// One contribution is a permutation of (0,0,2)
+			dxExt1 = dx0 - 2*fxSquish3D
+			dyExt1 = dy0 - 2*fxSquish3D
+			dzExt1 = dz0 - 2*fxSquish3D
+			xsvExt1 = xsb
+			ysvExt1 = ysb
+			zsvExt1 = zsb
+			if (c2 & 0x01) != 0 {
+				dxExt1 -= 2 * fxOne
+				xsvExt1 += 2
+			} else if (c2 & 0x02) != 0 {
+				dyExt1 -= 2 * fxOne
+				ysvExt1 += 2
+			} else {
+				dzExt1 -= 2 * fxOne
+				zsvExt1 += 2
+			}
+		}
+
+		// Contribution (1,0,0)
+		dx1 := dx0 - fxOne - fxSquish3D
+		dy1 := dy0 - 0 - fxSquish3D
+		dz1 := dz0 - 0 - fxSquish3D
+		attn1 := 2*fxOne - fxMul(dx1, dx1) - fxMul(dy1, dy1) - fxMul(dz1, dz1)
+		if attn1 > 0 {
+			value += s.contrib3(attn1, xsb+1, ysb+0, zsb+0, dx1, dy1, dz1)
+		}
+
+		// Contribution (0,1,0)
+		dx2 := dx0 - 0 - fxSquish3D
+		dy2 := dy0 - fxOne - fxSquish3D
+		dz2 := dz1
+		attn2 := 2*fxOne - fxMul(dx2, dx2) - fxMul(dy2, dy2) - fxMul(dz2, dz2)
+		if attn2 > 0 {
+			value += s.contrib3(attn2, xsb+0, ysb+1, zsb+0, dx2, dy2, dz2)
+		}
+
+		// Contribution (0,0,1)
+		dx3 := dx2
+		dy3 := dy1
+		dz3 := dz0 - fxOne - fxSquish3D
+		attn3 := 2*fxOne - fxMul(dx3, dx3) - fxMul(dy3, dy3) - fxMul(dz3, dz3)
+		if attn3 > 0 {
+			value += s.contrib3(attn3, xsb+0, ysb+0, zsb+1, dx3, dy3, dz3)
+		}
+
+		// Contribution (1,1,0)
+		dx4 := dx0 - fxOne - 2*fxSquish3D
+		dy4 := dy0 - fxOne - 2*fxSquish3D
+		dz4 := dz0 - 0 - 2*fxSquish3D
+		attn4 := 2*fxOne - fxMul(dx4, dx4) - fxMul(dy4, dy4) - fxMul(dz4, dz4)
+		if attn4 > 0 {
+			value += s.contrib3(attn4, xsb+1, ysb+1, zsb+0, dx4, dy4, dz4)
+		}
+
+		// Contribution (1,0,1)
+		dx5 := dx4
+		dy5 := dy0 - 0 - 2*fxSquish3D
+		dz5 := dz0 - fxOne - 2*fxSquish3D
+		attn5 := 2*fxOne - fxMul(dx5, dx5) - fxMul(dy5, dy5) - fxMul(dz5, dz5)
+		if attn5 > 0 {
+			value += s.contrib3(attn5, xsb+1, ysb+0, zsb+1, dx5, dy5, dz5)
+		}
+
+		// Contribution (0,1,1)
+		dx6 := dx0 - 0 - 2*fxSquish3D
+		dy6 := dy4
+		dz6 := dz5
+		attn6 := 2*fxOne - fxMul(dx6, dx6) - fxMul(dy6, dy6) - fxMul(dz6, dz6)
+		if attn6 > 0 {
+			value += s.contrib3(attn6, xsb+0, ysb+1, zsb+1, dx6, dy6, dz6)
+		}
+	}
+
+	// First extra vertex
+	attnExt0 := 2*fxOne - fxMul(dxExt0, dxExt0) - fxMul(dyExt0, dyExt0) - fxMul(dzExt0, dzExt0)
+	if attnExt0 > 0 {
+		value += s.contrib3(attnExt0, xsvExt0, ysvExt0, zsvExt0, dxExt0, dyExt0, dzExt0)
+	}
+
+	// Second extra vertex
+	attnExt1 := 2*fxOne - fxMul(dxExt1, dxExt1) - fxMul(dyExt1, dyExt1) - fxMul(dzExt1, dzExt1)
+	if attnExt1 > 0 {
+		value += s.contrib3(attnExt1, xsvExt1, ysvExt1, zsvExt1, dxExt1, dyExt1, dzExt1)
+	}
+
+	return Fixed((value << (32 - fxFracBits)) / normConstant3D)
+}
@@ -0,0 +1,48 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFixedConversions(t *testing.T) {
+	for _, c := range []struct {
+		f    float64
+		want Fixed
+	}{
+		{0, 0},
+		{1, FixedOne},
+		{-2.5, -5 << 31},
+		{1.0 / (1 << 32), 1},
+		{1 << 28, FixedFromInt(1 << 28)},
+	} {
+		if got := FixedFromFloat64(c.f); got != c.want {
+			t.Errorf("FixedFromFloat64(%v) = %v, want %v", c.f, got, c.want)
+		}
+		if got := c.want.Float64(); got != c.f {
+			t.Errorf("Fixed(%v).Float64() = %v, want %v", int64(c.want), got, c.f)
+		}
+	}
+}
+
+func TestFixedMatchesNew(t *testing.T) {
+	f, n := NewFixed(4), New(4)
+
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for _, scale := range []float64{1, 1e3, 1e6, 1 << 28} {
+		for i := 0; i < 20000; i++ {
+			x := FixedFromFloat64((r.Float64()*2 - 1) * scale)
+			y := FixedFromFloat64((r.Float64()*2 - 1) * scale)
+			z := FixedFromFloat64((r.Float64()*2 - 1) * scale)
+
+			if a, b := f.Eval2(x, y).Float64(), n.Eval2(x.Float64(), y.Float64()); math.Abs(a-b) > 2e-6 {
+				t.Fatalf("fixed 2D value %v differs from %v at %v, %v", a, b, x.Float64(), y.Float64())
+			}
+			if a, b := f.Eval3(x, y, z).Float64(), n.Eval3(x.Float64(), y.Float64(), z.Float64()); math.Abs(a-b) > 2e-6 {
+				t.Fatalf("fixed 3D value %v differs from %v at %v, %v, %v", a, b, x.Float64(), y.Float64(), z.Float64())
+			}
+		}
+	}
+}
@@ -1,19 +1,60 @@
 package opensimplex
 
-// Wraps a noise instance to work with float32 values
+import "math"
 
-type cast32Noise struct {
-	base Noise
+// Native float32 implementation. The methods of noise32 in
+// opensimplex_base32.go are generated from those of noise, using float32
+// arithmetic and constants, trading some precision for speed and memory.
+
+const (
+	stretchConstant2D32 float32 = -0.211324865405187 // (1/Math.sqrt(2+1)-1)/2
+	squishConstant2D32  float32 = 0.366025403784439  // (Math.sqrt(2+1)-1)/2
+	stretchConstant3D32 float32 = -1.0 / 6           // (1/Math.sqrt(3+1)-1)/3
+	squishConstant3D32  float32 = 1.0 / 3            // (Math.sqrt(3+1)-1)/3
+	stretchConstant4D32 float32 = -0.138196601125011 // (1/Math.sqrt(4+1)-1)/4
+	squishConstant4D32  float32 = 0.309016994374947  // (Math.sqrt(4+1)-1)/4
+)
+
+// A seeded Noise32 instance, sharing the permutation tables of noise.
+type noise32 struct {
+	seed int64
+
+	perm            [256]int16
+	permGradIndex3D [256]int16
+}
+
+// floor32 returns the lattice coordinate of x like floorLattice, converting in
+// float32 when x fits in an int32.
+func floor32(x float32) int32 {
+	if !(x >= math.MinInt32 && x < -math.MinInt32) {
+		return floorLattice(float64(x))
+	}
+	xi := int32(x)
+	if x < float32(xi) {
+		return xi - 1
+	}
+	return xi
+}
+
+// Widen adapts a 32-bit noise instance to Noise, so it can be wrapped by
+// NewRanged32 and the other functions that take a Noise. Its Eval methods round
+// the coordinates to float32, evaluate base and return the result as float64.
+func Widen(base Noise32) Noise {
+	return &cast64Noise{base: base}
+}
+
+type cast64Noise struct {
+	base Noise32
 }
 
-func (n *cast32Noise) Eval2(x, y float32) float32 {
-	return float32(n.base.Eval2(float64(x), float64(y)))
+func (n *cast64Noise) Eval2(x, y float64) float64 {
+	return float64(n.base.Eval2(float32(x), float32(y)))
 }
 
-func (n *cast32Noise) Eval3(x, y, z float32) float32 {
-	return float32(n.base.Eval3(float64(x), float64(y), float64(z)))
+func (n *cast64Noise) Eval3(x, y, z float64) float64 {
+	return float64(n.base.Eval3(float32(x), float32(y), float32(z)))
 }
 
-func (n *cast32Noise) Eval4(x, y, z, w float32) float32 {
-	return float32(n.base.Eval4(float64(x), float64(y), float64(z), float64(w)))
+func (n *cast64Noise) Eval4(x, y, z, w float64) float64 {
+	return float64(n.base.Eval4(float32(x), float32(y), float32(z), float32(w)))
 }
@@ -0,0 +1,258 @@
+package opensimplex
+
+import "math"
+
+// FBM sums several octaves of a base noise, each sampled at a higher frequency
+// and lower amplitude than the previous one (fractal Brownian motion).
+//
+// The sum is divided by the total amplitude of all octaves, so the output keeps
+// the range of the base noise: wrapping a NewNormalized instance still yields
+// values in [0, 1).
+type FBM struct {
+	base Noise
+
+	// Octaves is the number of base noise samples summed per evaluation.
+	// Fewer than one octave is treated as one.
+	Octaves int
+	// Lacunarity is the frequency multiplier between successive octaves.
+	Lacunarity float64
+	// Gain (or persistence) is the amplitude multiplier between successive octaves.
+	Gain float64
+	// Offsets holds a per-octave offset vector, whose x, y, z and w
+	// components are added to the coordinates after scaling by the octave
+	// frequency. They are not seeds: every octave samples the same base
+	// noise, and offsetting them only moves each octave to a different part
+	// of it, which keeps features of successive octaves from lining up at the
+	// origin or along the diagonal. Octaves past the end of the slice are not
+	// offset.
+	Offsets [][4]float64
+}
+
+// NewFBM wraps base into fractal Brownian motion with the given number of
+// octaves, lacunarity and gain. Typical values are a lacunarity of 2 and a gain
+// of 0.5. Fewer than one octave is treated as one, like the Octaves field.
+func NewFBM(base Noise, octaves int, lacunarity, gain float64) *FBM {
+	return &FBM{
+		base:       base,
+		Octaves:    octaves,
+		Lacunarity: lacunarity,
+		Gain:       gain,
+	}
+}
+
+// Normalization returns the factor that scales the raw octave sum back into the
+// range of the base noise, the reciprocal of the total amplitude of all octaves.
+func (f *FBM) Normalization() float64 {
+	total, amp := float64(0), float64(1)
+	for i := 0; i < octaves(f.Octaves); i++ {
+		total += amp
+		amp *= f.Gain
+	}
+
+	return 1 / total
+}
+
+// octaves returns n, or one if n is less than one, so that a zero or negative
+// Octaves field does not divide the sum by zero.
+func octaves(n int) int {
+	if n < 1 {
+		return 1
+	}
+
+	return n
+}
+
+func (f *FBM) offset(octave int) [4]float64 {
+	if octave < len(f.Offsets) {
+		return f.Offsets[octave]
+	}
+
+	return [4]float64{}
+}
+
+// sum adds up the octaves produced by sample, which evaluates the base noise at
+// the given frequency and offset vector. If shape is not nil it is applied to
+// every sample before weighting.
+func (f *FBM) sum(shape func(float64) float64, sample func(freq float64, o [4]float64) float64) float64 {
+	sum, total, amp, freq := float64(0), float64(0), float64(1), float64(1)
+	for i := 0; i < octaves(f.Octaves); i++ {
+		v := sample(freq, f.offset(i))
+		if shape != nil {
+			v = shape(v)
+		}
+
+		sum += amp * v
+		total += amp
+		amp *= f.Gain
+		freq *= f.Lacunarity
+	}
+
+	return sum / total
+}
+
+// Eval2 returns the fractal noise value in two dimensions.
+func (f *FBM) Eval2(x, y float64) float64 {
+	return f.sum(nil, func(freq float64, o [4]float64) float64 {
+		return f.base.Eval2(x*freq+o[0], y*freq+o[1])
+	})
+}
+
+// Eval3 returns the fractal noise value in three dimensions.
+func (f *FBM) Eval3(x, y, z float64) float64 {
+	return f.sum(nil, func(freq float64, o [4]float64) float64 {
+		return f.base.Eval3(x*freq+o[0], y*freq+o[1], z*freq+o[2])
+	})
+}
+
+// Eval4 returns the fractal noise value in four dimensions.
+func (f *FBM) Eval4(x, y, z, w float64) float64 {
+	return f.sum(nil, func(freq float64, o [4]float64) float64 {
+		return f.base.Eval4(x*freq+o[0], y*freq+o[1], z*freq+o[2], w*freq+o[3])
+	})
+}
+
+// FBM32 is fractal Brownian motion over a 32-bit noise instance. Octaves are
+// scaled and summed in float64, and only the result is rounded to float32.
+type FBM32 struct {
+	FBM
+}
+
+// NewFBM32 is like NewFBM but wraps a 32-bit noise instance.
+func NewFBM32(base Noise32, octaves int, lacunarity, gain float64) *FBM32 {
+	return &FBM32{FBM: *NewFBM(Widen(base), octaves, lacunarity, gain)}
+}
+
+// Eval2 returns the fractal noise value in two dimensions.
+func (f *FBM32) Eval2(x, y float32) float32 {
+	return float32(f.FBM.Eval2(float64(x), float64(y)))
+}
+
+// Eval3 returns the fractal noise value in three dimensions.
+func (f *FBM32) Eval3(x, y, z float32) float32 {
+	return float32(f.FBM.Eval3(float64(x), float64(y), float64(z)))
+}
+
+// Eval4 returns the fractal noise value in four dimensions.
+func (f *FBM32) Eval4(x, y, z, w float32) float32 {
+	return float32(f.FBM.Eval4(float64(x), float64(y), float64(z), float64(w)))
+}
+
+// Billow is fractal Brownian motion over the absolute value of the base noise,
+// giving puffy, cloud-like shapes. Each octave contributes 2|n|-1, so for a base
+// noise in [-1, 1], like New, the output is also in [-1, 1].
+type Billow struct {
+	FBM
+}
+
+// NewBillow wraps base into billow noise with the given number of octaves,
+// lacunarity and gain. See NewFBM for the meaning of the parameters.
+func NewBillow(base Noise, octaves int, lacunarity, gain float64) *Billow {
+	return &Billow{FBM: *NewFBM(base, octaves, lacunarity, gain)}
+}
+
+func billow(v float64) float64 {
+	return 2*math.Abs(v) - 1
+}
+
+// Eval2 returns the billow noise value in two dimensions.
+func (b *Billow) Eval2(x, y float64) float64 {
+	return b.sum(billow, func(freq float64, o [4]float64) float64 {
+		return b.base.Eval2(x*freq+o[0], y*freq+o[1])
+	})
+}
+
+// Eval3 returns the billow noise value in three dimensions.
+func (b *Billow) Eval3(x, y, z float64) float64 {
+	return b.sum(billow, func(freq float64, o [4]float64) float64 {
+		return b.base.Eval3(x*freq+o[0], y*freq+o[1], z*freq+o[2])
+	})
+}
+
+// Eval4 returns the billow noise value in four dimensions.
+func (b *Billow) Eval4(x, y, z, w float64) float64 {
+	return b.sum(billow, func(freq float64, o [4]float64) float64 {
+		return b.base.Eval4(x*freq+o[0], y*freq+o[1], z*freq+o[2], w*freq+o[3])
+	})
+}
+
+// Ridged is Musgrave's ridged multifractal: every octave is folded into a ridge
+// with (Offset - |n|)^2 and weighted by the previous octave's signal times Gain,
+// so detail accumulates along ridges and stays smooth in valleys. It takes its
+// Octaves, Lacunarity, Gain and Offsets from FBM, but the Gain of an octave
+// scales its signal into the weight of the next one rather than its amplitude.
+//
+// The sum is divided by its upper bound, so for a base noise in [-1, 1], like
+// New, the output is in [-1, 1].
+type Ridged struct {
+	FBM
+
+	// H is the fractal increment, octave i is weighted by frequency^-H.
+	H float64
+	// Offset raises the ridge signal, Offset - |n|, before squaring.
+	Offset float64
+}
+
+// NewRidged wraps base into ridged multifractal noise with the given number of
+// octaves and lacunarity, using Musgrave's defaults of H = 1, Offset = 1 and
+// Gain = 2. Fewer than one octave is treated as one, like the Octaves field.
+func NewRidged(base Noise, octaves int, lacunarity float64) *Ridged {
+	return &Ridged{FBM: *NewFBM(base, octaves, lacunarity, 2), H: 1, Offset: 1}
+}
+
+// Normalization returns the factor that scales the raw sum of the octaves into
+// [0, 1] before it is mapped onto [-1, 1]: the reciprocal of the largest sum
+// the octaves can produce.
+func (r *Ridged) Normalization() float64 {
+	total, freq := float64(0), float64(1)
+	for i := 0; i < octaves(r.Octaves); i++ {
+		total += r.peak() * math.Pow(freq, -r.H)
+		freq *= r.Lacunarity
+	}
+
+	return 1 / total
+}
+
+// peak returns the largest signal a single octave can produce, given |n| <= 1.
+func (r *Ridged) peak() float64 {
+	return math.Max(r.Offset*r.Offset, (r.Offset-1)*(r.Offset-1))
+}
+
+func (r *Ridged) sum(sample func(freq float64, o [4]float64) float64) float64 {
+	peak := r.peak()
+
+	sum, total, weight, freq := float64(0), float64(0), float64(1), float64(1)
+	for i := 0; i < octaves(r.Octaves); i++ {
+		signal := r.Offset - math.Abs(sample(freq, r.offset(i)))
+		signal *= signal * weight
+
+		amp := math.Pow(freq, -r.H)
+		sum += signal * amp
+		total += peak * amp
+
+		weight = math.Min(math.Max(signal*r.Gain, 0), 1)
+		freq *= r.Lacunarity
+	}
+
+	return 2*sum/total - 1
+}
+
+// Eval2 returns the ridged noise value in two dimensions.
+func (r *Ridged) Eval2(x, y float64) float64 {
+	return r.sum(func(freq float64, o [4]float64) float64 {
+		return r.base.Eval2(x*freq+o[0], y*freq+o[1])
+	})
+}
+
+// Eval3 returns the ridged noise value in three dimensions.
+func (r *Ridged) Eval3(x, y, z float64) float64 {
+	return r.sum(func(freq float64, o [4]float64) float64 {
+		return r.base.Eval3(x*freq+o[0], y*freq+o[1], z*freq+o[2])
+	})
+}
+
+// Eval4 returns the ridged noise value in four dimensions.
+func (r *Ridged) Eval4(x, y, z, w float64) float64 {
+	return r.sum(func(freq float64, o [4]float64) float64 {
+		return r.base.Eval4(x*freq+o[0], y*freq+o[1], z*freq+o[2], w*freq+o[3])
+	})
+}
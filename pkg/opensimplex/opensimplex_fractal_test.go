@@ -0,0 +1,133 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFBMSingleOctaveMatchesBase(t *testing.T) {
+	base := New(3)
+	f := NewFBM(base, 1, 2, 0.5)
+
+	for i := 0; i < 100; i++ {
+		x, y := float64(i)*0.37, float64(i)*-0.21
+		if f.Eval2(x, y) != base.Eval2(x, y) {
+			t.Fatalf("single octave FBM differs from base at %v, %v", x, y)
+		}
+	}
+}
+
+func TestFBM32MatchesFBM(t *testing.T) {
+	var f32 Noise32 = NewFBM32(New32(3), 4, 2, 0.5)
+	f := NewFBM(Widen(New32(3)), 4, 2, 0.5)
+
+	for i := 0; i < 100; i++ {
+		x, y, z := float32(i)*0.37, float32(i)*-0.21, float32(i)*0.13
+		if a, b := f32.Eval3(x, y, z), float32(f.Eval3(float64(x), float64(y), float64(z))); a != b {
+			t.Fatalf("FBM32 value %v differs from FBM value %v at %v, %v, %v", a, b, x, y, z)
+		}
+	}
+}
+
+func TestFractalWithoutOctaves(t *testing.T) {
+	base := New(3)
+	f, r := NewFBM(base, 1, 2, 0.5), NewRidged(base, 1, 2)
+	f0, r0 := NewFBM(base, 0, 2, 0.5), NewRidged(base, -2, 2)
+	if f0.Octaves != 0 || r0.Octaves != -2 {
+		t.Fatalf("constructors changed the octaves to %d and %d", f0.Octaves, r0.Octaves)
+	}
+
+	if n := f0.Normalization(); n != 1 {
+		t.Errorf("Normalization() without octaves = %v, want 1", n)
+	}
+	if n := r0.Normalization(); n != 1 {
+		t.Errorf("Ridged Normalization() without octaves = %v, want 1", n)
+	}
+	for i := 0; i < 100; i++ {
+		x, y := float64(i)*0.37, float64(i)*-0.21
+		if f0.Eval2(x, y) != f.Eval2(x, y) || r0.Eval2(x, y) != r.Eval2(x, y) {
+			t.Fatalf("fractal noise without octaves differs from a single octave at %v, %v", x, y)
+		}
+	}
+}
+
+func TestFBMKeepsNormalizedRange(t *testing.T) {
+	f := NewFBM(NewNormalized(3), 6, 2, 0.5)
+	f.Offsets = [][4]float64{{}, {17.3, -5.1, 91.7, 3.3}, {-42.9, 8.2, -13.6, 57.1}}
+
+	if got, want := f.Normalization(), 1/(1+0.5+0.25+0.125+0.0625+0.03125); math.Abs(got-want) > 1e-15 {
+		t.Fatalf("Normalization() = %v, want %v", got, want)
+	}
+
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, y, z, w := r.Float64()*100, r.Float64()*100, r.Float64()*100, r.Float64()*100
+		for _, v := range []float64{f.Eval2(x, y), f.Eval3(x, y, z), f.Eval4(x, y, z, w)} {
+			if v < 0 || v >= 1 {
+				t.Fatalf("normalized FBM value %v out of [0, 1)", v)
+			}
+		}
+	}
+}
+
+func TestRidgedAndBillowRange(t *testing.T) {
+	base := New(9)
+	noises := map[string]Noise{
+		"ridged": NewRidged(base, 6, 2),
+		"billow": NewBillow(base, 6, 2, 0.5),
+	}
+
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for name, n := range noises {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for i := 0; i < 10000; i++ {
+			x, y, z := r.Float64()*100, r.Float64()*100, r.Float64()*100
+			for _, v := range []float64{n.Eval2(x, y), n.Eval3(x, y, z)} {
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+
+		if lo < -1 || hi > 1 {
+			t.Fatalf("%s noise range [%v, %v] exceeds [-1, 1]", name, lo, hi)
+		}
+		if hi-lo < 0.5 {
+			t.Fatalf("%s noise range [%v, %v] is suspiciously narrow", name, lo, hi)
+		}
+	}
+}
+
+func TestFBMOffsetsPerAxis(t *testing.T) {
+	base := New(3)
+	f := NewFBM(base, 2, 2, 0.5)
+	f.Offsets = [][4]float64{{1.5, -2.25, 3.125, 7}, {-4, 5.5, 0.75, -6}}
+
+	x, y, z, w := 0.3, -1.7, 2.9, 0.4
+	want := (base.Eval4(x+1.5, y-2.25, z+3.125, w+7) + 0.5*base.Eval4(x*2-4, y*2+5.5, z*2+0.75, w*2-6)) / 1.5
+	if got := f.Eval4(x, y, z, w); math.Abs(got-want) > 1e-15 {
+		t.Errorf("FBM.Eval4 with offsets = %v, want %v", got, want)
+	}
+
+	// Ridged takes the offsets from its FBM.
+	r := NewRidged(base, 1, 2)
+	r.Offsets = f.Offsets[:1]
+	signal := 1 - math.Abs(base.Eval2(x+1.5, y-2.25))
+	if got, want := r.Eval2(x, y), 2*signal*signal-1; math.Abs(got-want) > 1e-15 {
+		t.Errorf("Ridged.Eval2 with offsets = %v, want %v", got, want)
+	}
+}
+
+func TestRidgedSingleOctave(t *testing.T) {
+	base := New(9)
+	r := NewRidged(base, 1, 2)
+
+	for i := 0; i < 100; i++ {
+		x, y := float64(i)*0.37, float64(i)*-0.21
+		signal := 1 - math.Abs(base.Eval2(x, y))
+		if want := 2*signal*signal - 1; math.Abs(r.Eval2(x, y)-want) > 1e-15 {
+			t.Fatalf("Ridged.Eval2(%v, %v) = %v, want %v", x, y, r.Eval2(x, y), want)
+		}
+	}
+}
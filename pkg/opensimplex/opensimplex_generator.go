@@ -0,0 +1,334 @@
+package opensimplex
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrIncompatible is returned when restoring a Generator that was saved in a
+// format version or for an algorithm this package does not support.
+var ErrIncompatible = errors.New("opensimplex: incompatible saved generator")
+
+// generatorVersion is the version of the saved Generator format. Bump it
+// whenever the meaning of the saved fields changes.
+const generatorVersion = 1
+
+// generatorAlgorithm names the noise algorithm in saved Generators.
+const generatorAlgorithm = "opensimplex"
+
+// generatorMagic starts every binary encoded Generator.
+const generatorMagic = "OSNG"
+
+// Generator is a seeded 64-bit noise instance that can be saved and restored
+// exactly, for example as part of a world save. It implements Noise,
+// NoiseWithDerivatives, NoiseAt and Noise1D.
+//
+// The saved form holds the permutation tables themselves rather than just the
+// seed, so a restored Generator produces the same noise even if the way tables
+// are derived from seeds changes in a later version of this package. The zero
+// Generator is ready to use and behaves like NewGenerator(0, HashPerm256).
+//
+// A Generator only refers to its tables, so it is cheap to copy, and all its
+// methods but UnmarshalBinary and UnmarshalJSON have value receivers.
+type Generator struct {
+	n *noise
+}
+
+// zeroGenerator is the noise of the zero Generator.
+var zeroGenerator = newNoiseWithHash(0, HashPerm256)
+
+// NewGenerator constructs a Generator with a 64-bit seed that hashes the
+// lattice with h. NewGenerator(seed, HashPerm256) produces the same noise as
+// New(seed).
+func NewGenerator(seed int64, h Hash) *Generator {
+	return &Generator{newNoiseWithHash(seed, h)}
+}
+
+// impl returns the noise g evaluates.
+func (g Generator) impl() *noise {
+	if g.n == nil {
+		return zeroGenerator
+	}
+	return g.n
+}
+
+// Seed returns the seed the Generator was constructed with.
+func (g Generator) Seed() int64 {
+	return g.impl().seed
+}
+
+// Hash returns the lattice hash of the Generator.
+func (g Generator) Hash() Hash {
+	return g.impl().hash
+}
+
+// Eval1 returns a random noise value in one dimension, see Noise1D.
+func (g Generator) Eval1(x float64) float64 {
+	return g.impl().Eval1(x)
+}
+
+// Eval2 returns a random noise value in two dimensions.
+func (g Generator) Eval2(x, y float64) float64 {
+	return g.impl().Eval2(x, y)
+}
+
+// Eval3 returns a random noise value in three dimensions.
+func (g Generator) Eval3(x, y, z float64) float64 {
+	return g.impl().Eval3(x, y, z)
+}
+
+// Eval4 returns a random noise value in four dimensions.
+func (g Generator) Eval4(x, y, z, w float64) float64 {
+	return g.impl().Eval4(x, y, z, w)
+}
+
+// Eval2Deriv returns a random noise value in two dimensions along with its
+// partial derivatives, see NoiseWithDerivatives.
+func (g Generator) Eval2Deriv(x, y float64) (v, dx, dy float64) {
+	return g.impl().Eval2Deriv(x, y)
+}
+
+// Eval3Deriv returns a random noise value in three dimensions along with its
+// partial derivatives, see NoiseWithDerivatives.
+func (g Generator) Eval3Deriv(x, y, z float64) (v, dx, dy, dz float64) {
+	return g.impl().Eval3Deriv(x, y, z)
+}
+
+// Eval4Deriv returns a random noise value in four dimensions along with its
+// partial derivatives, see NoiseWithDerivatives.
+func (g Generator) Eval4Deriv(x, y, z, w float64) (v, dx, dy, dz, dw float64) {
+	return g.impl().Eval4Deriv(x, y, z, w)
+}
+
+// Eval2At returns the 2D noise value at a point split into chunk and fraction,
+// see NoiseAt.
+func (g Generator) Eval2At(cx, cy int64, fx, fy float64) float64 {
+	return g.impl().Eval2At(cx, cy, fx, fy)
+}
+
+// Eval3At returns the 3D noise value at a point split into chunk and fraction,
+// see NoiseAt.
+func (g Generator) Eval3At(cx, cy, cz int64, fx, fy, fz float64) float64 {
+	return g.impl().Eval3At(cx, cy, cz, fx, fy, fz)
+}
+
+// Eval4At returns the 4D noise value at a point split into chunk and fraction,
+// see NoiseAt.
+func (g Generator) Eval4At(cx, cy, cz, cw int64, fx, fy, fz, fw float64) float64 {
+	return g.impl().Eval4At(cx, cy, cz, cw, fx, fy, fz, fw)
+}
+
+// Eval2Grid fills dst with a grid of 2D noise values, see GridNoise.
+func (g Generator) Eval2Grid(dst []float64, x0, y0, dx, dy float64, width, height int) {
+	g.impl().Eval2Grid(dst, x0, y0, dx, dy, width, height)
+}
+
+// Eval3Grid fills dst with a grid of 3D noise values, see GridNoise.
+func (g Generator) Eval3Grid(dst []float64, x0, y0, z0, dx, dy, dz float64, width, height, depth int) {
+	g.impl().Eval3Grid(dst, x0, y0, z0, dx, dy, dz, width, height, depth)
+}
+
+// Eval4Grid fills dst with a grid of 4D noise values, see GridNoise.
+func (g Generator) Eval4Grid(dst []float64, x0, y0, z, w, dx, dy float64, width, height int) {
+	g.impl().Eval4Grid(dst, x0, y0, z, w, dx, dy, width, height)
+}
+
+// Describe implements Describer.
+func (g Generator) Describe() Description {
+	return g.impl().Describe()
+}
+
+// savedGenerator is the content of a saved Generator, shared by the binary and
+// the JSON encodings. Perm and PermGradIndex3D are empty for HashStateless,
+// which saves HashSeed instead.
+type savedGenerator struct {
+	Version         int     `json:"version"`
+	Algorithm       string  `json:"algorithm"`
+	Hash            Hash    `json:"hash"`
+	Seed            int64   `json:"seed"`
+	Perm            []int16 `json:"perm,omitempty"`
+	PermGradIndex3D []int16 `json:"permGradIndex3D,omitempty"`
+	HashSeed        uint64  `json:"hashSeed,omitempty"`
+}
+
+func (g Generator) save() savedGenerator {
+	s := g.impl()
+	v := savedGenerator{
+		Version:   generatorVersion,
+		Algorithm: generatorAlgorithm,
+		Hash:      s.hash,
+		Seed:      s.seed,
+	}
+
+	switch s.hash {
+	case HashPerm256:
+		v.Perm, v.PermGradIndex3D = s.perm[:], s.permGradIndex3D[:]
+	case HashStateless:
+		v.HashSeed = s.lattice.(statelessHash).seed
+	default:
+		p := s.lattice.(*permHash)
+		v.Perm, v.PermGradIndex3D = p.perm, p.permGradIndex3D
+	}
+
+	return v
+}
+
+// tableSize returns the number of permutation table entries saved for h, or
+// zero for HashStateless.
+func tableSize(h Hash) int {
+	switch h {
+	case HashPerm256:
+		return 256
+	case HashPerm1024:
+		return 1024
+	case HashPerm4096:
+		return 4096
+	}
+	return 0
+}
+
+func (v *savedGenerator) restore() (*noise, error) {
+	if v.Version != generatorVersion {
+		return nil, fmt.Errorf("%w: version %d", ErrIncompatible, v.Version)
+	}
+	if v.Algorithm != generatorAlgorithm {
+		return nil, fmt.Errorf("%w: algorithm %q", ErrIncompatible, v.Algorithm)
+	}
+	if v.Hash < 0 || int(v.Hash) >= len(hashNames) {
+		return nil, fmt.Errorf("%w: hash %d", ErrIncompatible, int(v.Hash))
+	}
+
+	size := tableSize(v.Hash)
+	if len(v.Perm) != size || len(v.PermGradIndex3D) != size {
+		return nil, fmt.Errorf("opensimplex: saved %v generator has tables of %d and %d entries, want %d",
+			v.Hash, len(v.Perm), len(v.PermGradIndex3D), size)
+	}
+
+	seen := make([]bool, size)
+	for i, p := range v.Perm {
+		if p < 0 || int(p) >= size || seen[p] {
+			return nil, fmt.Errorf("opensimplex: saved permutation table is not a permutation at entry %d", i)
+		}
+		seen[p] = true
+	}
+
+	// The 3D gradient indices are derived from the permutation, so they must
+	// match it for the noise to be the one that was saved.
+	for i, p := range v.Perm {
+		if g := v.PermGradIndex3D[i]; g != (p%int16(len(gradients3D)/3))*3 {
+			return nil, fmt.Errorf("opensimplex: saved 3D gradient index %d at entry %d does not match the permutation", g, i)
+		}
+	}
+
+	s := &noise{seed: v.Seed, hash: v.Hash}
+	switch v.Hash {
+	case HashPerm256:
+		copy(s.perm[:], v.Perm)
+		copy(s.permGradIndex3D[:], v.PermGradIndex3D)
+	case HashStateless:
+		s.lattice = statelessHash{seed: v.HashSeed}
+	default:
+		s.lattice = &permHash{
+			perm:            append([]int16(nil), v.Perm...),
+			permGradIndex3D: append([]int16(nil), v.PermGradIndex3D...),
+			mask:            int32(size - 1),
+		}
+	}
+
+	return s, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding is the magic
+// "OSNG", the format version and hash as bytes, the seed, and then either the
+// permutation tables as big-endian int16 values or the stateless hash seed.
+func (g Generator) MarshalBinary() ([]byte, error) {
+	v := g.save()
+
+	b := make([]byte, 0, len(generatorMagic)+2+8+4*len(v.Perm))
+	b = append(b, generatorMagic...)
+	b = append(b, byte(v.Version), byte(v.Hash))
+	b = appendUint64(b, uint64(v.Seed))
+
+	if v.Hash == HashStateless {
+		return appendUint64(b, v.HashSeed), nil
+	}
+	for _, t := range [][]int16{v.Perm, v.PermGradIndex3D} {
+		for _, p := range t {
+			b = append(b, byte(uint16(p)>>8), byte(p))
+		}
+	}
+	return b, nil
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It returns an error
+// wrapping ErrIncompatible if data was saved in an unsupported format version.
+func (g *Generator) UnmarshalBinary(data []byte) error {
+	const header = len(generatorMagic) + 2 + 8
+	if len(data) < header || string(data[:len(generatorMagic)]) != generatorMagic {
+		return errors.New("opensimplex: data is not a saved generator")
+	}
+
+	v := savedGenerator{
+		Version:   int(data[4]),
+		Algorithm: generatorAlgorithm,
+		Hash:      Hash(data[5]),
+		Seed:      int64(binary.BigEndian.Uint64(data[6:])),
+	}
+	if v.Version != generatorVersion {
+		return fmt.Errorf("%w: version %d", ErrIncompatible, v.Version)
+	}
+	data = data[header:]
+
+	size := tableSize(v.Hash)
+	switch {
+	case v.Hash == HashStateless && len(data) == 8:
+		v.HashSeed = binary.BigEndian.Uint64(data)
+	case size != 0 && len(data) == 4*size:
+		v.Perm, v.PermGradIndex3D = make([]int16, size), make([]int16, size)
+		for i := range v.Perm {
+			v.Perm[i] = int16(binary.BigEndian.Uint16(data[2*i:]))
+			v.PermGradIndex3D[i] = int16(binary.BigEndian.Uint16(data[2*(size+i):]))
+		}
+	case size == 0 && v.Hash != HashStateless:
+		return fmt.Errorf("%w: hash %d", ErrIncompatible, int(v.Hash))
+	default:
+		return fmt.Errorf("opensimplex: saved %v generator has %d bytes of tables", v.Hash, len(data))
+	}
+
+	n, err := v.restore()
+	if err != nil {
+		return err
+	}
+	g.n = n
+	return nil
+}
+
+// MarshalJSON encodes the Generator as an object holding the format version,
+// algorithm, hash, seed and permutation tables.
+func (g Generator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.save())
+}
+
+// UnmarshalJSON decodes a Generator encoded by MarshalJSON. It returns an error
+// wrapping ErrIncompatible if data was saved in an unsupported format version.
+func (g *Generator) UnmarshalJSON(data []byte) error {
+	var v savedGenerator
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	n, err := v.restore()
+	if err != nil {
+		return err
+	}
+	g.n = n
+	return nil
+}
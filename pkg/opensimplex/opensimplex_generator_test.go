@@ -0,0 +1,148 @@
+package opensimplex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func sameNoise(t *testing.T, a, b Noise) {
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		x, y, z, w := float64(i)*0.37, float64(i)*-1.13, float64(i)*0.71, float64(i)*2.3
+		if a.Eval2(x, y) != b.Eval2(x, y) || a.Eval3(x, y, z) != b.Eval3(x, y, z) || a.Eval4(x, y, z, w) != b.Eval4(x, y, z, w) {
+			t.Fatalf("restored noise differs at %v, %v, %v, %v", x, y, z, w)
+		}
+	}
+}
+
+func TestGeneratorRoundTrip(t *testing.T) {
+	for _, h := range allHashes {
+		g := NewGenerator(-77, h)
+
+		bin, err := g.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromBin Generator
+		if err := fromBin.UnmarshalBinary(bin); err != nil {
+			t.Fatalf("%v: %v", h, err)
+		}
+
+		js, err := json.Marshal(struct{ Terrain Generator }{*g})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fromJSON struct{ Terrain Generator }
+		if err := json.Unmarshal(js, &fromJSON); err != nil {
+			t.Fatalf("%v: %v", h, err)
+		}
+
+		for _, r := range []Generator{fromBin, fromJSON.Terrain} {
+			if r.Seed() != -77 || r.Hash() != h {
+				t.Errorf("restored %v generator has seed %v and hash %v", h, r.Seed(), r.Hash())
+			}
+			sameNoise(t, NewWithHash(-77, h), r)
+		}
+	}
+}
+
+func TestGeneratorMatchesNew(t *testing.T) {
+	sameNoise(t, New(5), NewGenerator(5, HashPerm256))
+}
+
+func TestGeneratorRejectsIncompatible(t *testing.T) {
+	bin, err := NewGenerator(1, HashPerm256).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin[4] = generatorVersion + 1
+
+	var g Generator
+	if err := g.UnmarshalBinary(bin); !errors.Is(err, ErrIncompatible) {
+		t.Errorf("UnmarshalBinary of a newer version returned %v", err)
+	}
+
+	for _, js := range []string{
+		`{"version":2,"algorithm":"opensimplex","hash":"perm256","seed":1}`,
+		`{"version":1,"algorithm":"opensimplex2f","hash":"perm256","seed":1}`,
+	} {
+		if err := json.Unmarshal([]byte(js), &g); !errors.Is(err, ErrIncompatible) {
+			t.Errorf("UnmarshalJSON(%s) returned %v", js, err)
+		}
+	}
+}
+
+func TestGeneratorRejectsCorruptTables(t *testing.T) {
+	js, err := json.Marshal(NewGenerator(1, HashPerm1024))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v map[string]interface{}
+	if err := json.Unmarshal(js, &v); err != nil {
+		t.Fatal(err)
+	}
+	perm := v["perm"].([]interface{})
+	perm[0] = perm[1]
+	corrupt, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var g Generator
+	if err := json.Unmarshal(corrupt, &g); err == nil || !strings.Contains(err.Error(), "not a permutation") {
+		t.Errorf("UnmarshalJSON of a duplicated entry returned %v", err)
+	}
+
+	// A valid permutation whose 3D gradient indices were derived from another.
+	if err := json.Unmarshal(js, &v); err != nil {
+		t.Fatal(err)
+	}
+	grad := v["permGradIndex3D"].([]interface{})
+	grad[0], grad[1] = grad[1], grad[0]
+	if grad[0] == grad[1] {
+		t.Fatal("swapped equal 3D gradient indices")
+	}
+	if corrupt, err = json.Marshal(v); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(corrupt, &g); err == nil || !strings.Contains(err.Error(), "does not match the permutation") {
+		t.Errorf("UnmarshalJSON of swapped 3D gradient indices returned %v", err)
+	}
+
+	bin, err := NewGenerator(1, HashPerm256).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range [][]byte{nil, []byte("OSNG"), bin[:len(bin)-1]} {
+		if err := g.UnmarshalBinary(data); err == nil {
+			t.Errorf("UnmarshalBinary accepted %d bytes", len(data))
+		}
+	}
+}
+
+func TestZeroGenerator(t *testing.T) {
+	var g Generator
+	sameNoise(t, New(0), g)
+	if g.Seed() != 0 || g.Hash() != HashPerm256 {
+		t.Errorf("zero Generator has seed %v and hash %v, want 0 and %v", g.Seed(), g.Hash(), HashPerm256)
+	}
+	if v, dx, dy := g.Eval2Deriv(0.3, 0.7); v != g.Eval2(0.3, 0.7) || dx == 0 && dy == 0 {
+		t.Errorf("zero Generator Eval2Deriv = %v, %v, %v", v, dx, dy)
+	}
+
+	zero, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := NewGenerator(0, HashPerm256).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(zero, want) {
+		t.Error("zero Generator marshals differently from NewGenerator(0, HashPerm256)")
+	}
+}
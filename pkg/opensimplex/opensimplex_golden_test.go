@@ -0,0 +1,92 @@
+/**
+ * Golden samples for several seeds and every output path, written by
+ * internal/gensamples (run `go generate`). Each line is [seed, coords..., v].
+ *
+ * Unlike opensimplex_test_samples.json.gz these record the output of this
+ * package, not of the Java reference: they catch any refactor that changes the
+ * output for a seed that already shipped.
+ *
+ * The float64 paths keep their products out of fused multiply-adds, so their
+ * goldens must match exactly on every platform, and NewDeterministic promises
+ * so even for coordinates far outside the int32 lattice. These tests are also
+ * meant to be run cross-compiled, e.g. with GOARCH=386 on Linux or with
+ * GOARCH=arm64 under qemu-user:
+ *
+ *   GOARCH=386 go test -run Golden .
+ *   GOARCH=arm64 go test -exec qemu-aarch64 -run Golden .
+ *
+ * New32 has the same barriers in float32, as its code is generated from the
+ * float64 path, and NewNormalized32 rounds the float64 path, so their goldens
+ * are matched exactly too. The OpenSimplex2 samples are not, see
+ * opensimplex2_test.go.
+ */
+package opensimplex
+
+import (
+	"math"
+	"testing"
+)
+
+// float32Tolerance bounds the difference of float32 outputs between platforms
+// that do and do not fuse multiply-adds, per unit of the largest coordinate
+// magnitude, like TestSamplesMatch32 does for the float32 error itself.
+const float32Tolerance = 1e-6
+
+// sampleTolerance scales tol by the largest magnitude of coords, or by 1 if they
+// are all smaller.
+func sampleTolerance(tol float64, coords []float64) float64 {
+	magnitude := float64(1)
+	for _, c := range coords {
+		magnitude = math.Max(magnitude, math.Abs(c))
+	}
+	return tol * magnitude
+}
+
+func TestGoldenSamples(t *testing.T) {
+	for _, c := range []struct {
+		file  string
+		noise func(seed int64) Noise
+		tol   float64
+	}{
+		{"opensimplex_golden_raw.json.gz", New, 0},
+		{"opensimplex_golden_normalized.json.gz", NewNormalized, 0},
+		{"opensimplex_golden_32.json.gz", func(seed int64) Noise { return Widen(New32(seed)) }, 0},
+		{"opensimplex_golden_normalized32.json.gz", func(seed int64) Noise { return Widen(NewNormalized32(seed)) }, 0},
+		{"opensimplex_golden_deterministic.json.gz", NewDeterministic, 0},
+	} {
+		c := c
+		t.Run(c.file, func(t *testing.T) {
+			instances := make(map[int64]Noise)
+
+			for s := range loadSamplesFile(c.file) {
+				seed := int64(s[0])
+				n, ok := instances[seed]
+				if !ok {
+					n = c.noise(seed)
+					instances[seed] = n
+				}
+
+				var expected, actual float64
+				switch len(s) {
+				case 4:
+					expected, actual = s[3], n.Eval2(s[1], s[2])
+				case 5:
+					expected, actual = s[4], n.Eval3(s[1], s[2], s[3])
+				case 6:
+					expected, actual = s[5], n.Eval4(s[1], s[2], s[3], s[4])
+				default:
+					t.Fatalf("Unexpected size sample: %d", len(s))
+				}
+
+				if math.Abs(expected-actual) > sampleTolerance(c.tol, s[1:len(s)-1]) || (c.tol == 0 && expected != actual) {
+					t.Fatalf("Expected %v, got %v for seed %d %dD sample at %v",
+						expected, actual, seed, len(s)-2, s[1:len(s)-1])
+				}
+			}
+
+			if len(instances) < 2 {
+				t.Fatalf("%s covers %d seeds", c.file, len(instances))
+			}
+		})
+	}
+}
@@ -0,0 +1,371 @@
+package opensimplex
+
+import "math"
+
+// GridNoise is implemented by noise instances that can evaluate a whole regular
+// grid of samples in one call, faster than sample by sample when neighbouring
+// samples share lattice cells. Samples are stored row by row, x varying
+// fastest, so the sample at (i, j, k) is dst[(k*height+j)*width+i]. The grid
+// methods panic if a size is negative or dst is too small.
+type GridNoise interface {
+	// Eval2Grid fills dst with width*height samples of Eval2 starting at
+	// (x0, y0) and advancing by dx and dy.
+	Eval2Grid(dst []float64, x0, y0, dx, dy float64, width, height int)
+	// Eval3Grid fills dst with width*height*depth samples of Eval3 starting at
+	// (x0, y0, z0) and advancing by dx, dy and dz.
+	Eval3Grid(dst []float64, x0, y0, z0, dx, dy, dz float64, width, height, depth int)
+	// Eval4Grid fills dst with a width*height slice of Eval4 starting at
+	// (x0, y0) and advancing by dx and dy, with z and w pinned.
+	Eval4Grid(dst []float64, x0, y0, z, w, dx, dy float64, width, height int)
+}
+
+// GridSpec describes a regular grid of samples for FillGrid.
+type GridSpec struct {
+	// Dims selects Eval2, Eval3 or Eval4.
+	Dims int
+	// Origin is the coordinate of the first sample. For Eval4 grids the z and w
+	// axes are pinned to Origin[2] and Origin[3].
+	Origin [4]float64
+	// Step is the distance between neighbouring samples along x, y and z.
+	Step [3]float64
+	// Size is the number of samples along x, y and z. The z size is only used
+	// by Eval3 grids, where zero is treated as one. Sizes must not be
+	// negative.
+	Size [3]int
+}
+
+// Len returns the number of samples in the grid.
+func (g GridSpec) Len() int {
+	return g.Size[0] * g.Size[1] * g.depth()
+}
+
+func (g GridSpec) depth() int {
+	if g.Dims != 3 || g.Size[2] == 0 {
+		return 1
+	}
+
+	return g.Size[2]
+}
+
+// FillGrid evaluates n over the grid described by spec and stores the samples in
+// dst, in the layout documented by GridNoise. If n implements GridNoise its
+// batch methods are used, otherwise n is evaluated one sample at a time.
+func FillGrid(n Noise, dst []float64, spec GridSpec) {
+	width, height, depth := spec.Size[0], spec.Size[1], spec.depth()
+	o, st := spec.Origin, spec.Step
+	checkGrid(dst, width, height, depth)
+	if spec.Dims < 2 || spec.Dims > 4 {
+		panic("opensimplex: grid dimensions must be 2, 3 or 4")
+	}
+
+	if g, ok := n.(GridNoise); ok {
+		switch spec.Dims {
+		case 2:
+			g.Eval2Grid(dst, o[0], o[1], st[0], st[1], width, height)
+		case 3:
+			g.Eval3Grid(dst, o[0], o[1], o[2], st[0], st[1], st[2], width, height, depth)
+		default:
+			g.Eval4Grid(dst, o[0], o[1], o[2], o[3], st[0], st[1], width, height)
+		}
+		return
+	}
+
+	xs := gridColumns(o[0], st[0], width)
+	for k := 0; k < depth; k++ {
+		z := o[2]
+		if spec.Dims == 3 {
+			z += float64(float64(k) * st[2])
+		}
+		for j := 0; j < height; j++ {
+			y := o[1] + float64(float64(j)*st[1])
+			row := (k*height + j) * width
+			fillRow(n, dst[row:row+width], xs, spec.Dims, y, z, o[3])
+		}
+	}
+}
+
+func checkGrid(dst []float64, width, height, depth int) {
+	if width < 0 || height < 0 || depth < 0 {
+		panic("opensimplex: grid sizes must not be negative")
+	}
+	if len(dst) < width*height*depth {
+		panic("opensimplex: grid buffer too small")
+	}
+}
+
+// gridColumns returns the x coordinate of every column of a grid, so they are
+// computed once rather than once per row. Like the evaluation bodies, grid
+// coordinates are computed with a float64() barrier against FMA contraction,
+// so the same grid yields the same samples on every platform.
+func gridColumns(x0, dx float64, width int) []float64 {
+	xs := make([]float64, width)
+	for i := range xs {
+		xs[i] = x0 + float64(float64(i)*dx)
+	}
+
+	return xs
+}
+
+// fillRow stores the samples of n at the columns xs of one grid row in row. For
+// Eval4 grids z and w are the pinned coordinates, for Eval2 grids both are
+// ignored.
+func fillRow(n Noise, row, xs []float64, dims int, y, z, w float64) {
+	if s, ok := n.(*noise); ok {
+		if len(xs) > 1 && math.Abs(xs[1]-xs[0]) <= gridWalkStep[dims] {
+			var g gridCells
+			g.init(s, dims)
+			switch dims {
+			case 2:
+				g.row2(row, xs, y)
+			case 3:
+				g.row3(row, xs, y, z)
+			default:
+				g.row4(row, xs, y, z, w)
+			}
+			return
+		}
+
+		for i, x := range xs {
+			switch dims {
+			case 2:
+				row[i] = s.eval2(x, y, nil)
+			case 3:
+				row[i] = s.eval3(x, y, z, nil)
+			default:
+				row[i] = s.eval4(x, y, z, w, nil)
+			}
+		}
+		return
+	}
+
+	for i, x := range xs {
+		switch dims {
+		case 2:
+			row[i] = n.Eval2(x, y)
+		case 3:
+			row[i] = n.Eval3(x, y, z)
+		default:
+			row[i] = n.Eval4(x, y, z, w)
+		}
+	}
+}
+
+// gridWalkStep is the largest column step, by the number of dimensions, for
+// which fillRow walks a row with gridCells: about one sample in every 4^dims
+// steps falls in a new super-cell, so that hashing its 4^dims vertices costs
+// less than looking them up for every sample would.
+var gridWalkStep = [5]float64{2: 1.0 / 16, 3: 1.0 / 64, 4: 1.0 / 256}
+
+// gridCells walks the samples of a grid row for noise. Neighbouring samples of a
+// fine grid mostly fall in the same super-cell, so it hashes the lattice
+// vertices around the current super-cell, from one below to two above its
+// origin on each axis, when it moves there, and the samples in that cell share
+// the gradient indices and the floor of its origin. The row methods repeat the
+// set-up of eval2, eval3 and eval4 and call the copies of the cell functions in
+// opensimplex_gridcells.go, generated by genlattice, so a grid holds exactly the
+// values of the matching Eval method.
+type gridCells struct {
+	s    *noise
+	dims int
+	// base is the origin of the current super-cell.
+	base [4]int32
+	// index holds the gradient index of the vertex base + (i, j, k, l) - 1 at
+	// i + 4*j + 16*k + 64*l.
+	index [256]int16
+}
+
+func (g *gridCells) init(s *noise, dims int) {
+	g.s = s
+	g.dims = dims
+	g.hash()
+}
+
+// move makes the super-cell at base current.
+func (g *gridCells) move(base [4]int32) {
+	if base != g.base {
+		g.base = base
+		g.hash()
+	}
+}
+
+// hash fills index for the current super-cell.
+func (g *gridCells) hash() {
+	s, b := g.s, g.base
+	for i := range g.index[:1<<(2*g.dims)] {
+		xsv := b[0] + int32(i&3) - 1
+		ysv := b[1] + int32(i>>2&3) - 1
+		zsv := b[2] + int32(i>>4&3) - 1
+		wsv := b[3] + int32(i>>6&3) - 1
+		switch {
+		case g.dims == 2 && s.lattice != nil:
+			g.index[i] = s.lattice.gradIndex2(xsv, ysv)
+		case g.dims == 2:
+			g.index[i] = s.gradIndex2(xsv, ysv)
+		case g.dims == 3 && s.lattice != nil:
+			g.index[i] = s.lattice.gradIndex3(xsv, ysv, zsv)
+		case g.dims == 3:
+			g.index[i] = s.gradIndex3(xsv, ysv, zsv)
+		case s.lattice != nil:
+			g.index[i] = s.lattice.gradIndex4(xsv, ysv, zsv, wsv)
+		default:
+			g.index[i] = s.gradIndex4(xsv, ysv, zsv, wsv)
+		}
+	}
+}
+
+// The gradIndex methods return those of noise from the cache. The cell
+// functions only visit vertices from one below to two above the origin of their
+// super-cell on each axis, so the offsets are taken modulo 4 without checks.
+
+func (g *gridCells) gradIndex2(xsb, ysb int32) int16 {
+	return g.index[(xsb-g.base[0]+1)&3|(ysb-g.base[1]+1)&3<<2]
+}
+
+func (g *gridCells) gradIndex3(xsb, ysb, zsb int32) int16 {
+	return g.index[(xsb-g.base[0]+1)&3|(ysb-g.base[1]+1)&3<<2|(zsb-g.base[2]+1)&3<<4]
+}
+
+func (g *gridCells) gradIndex4(xsb, ysb, zsb, wsb int32) int16 {
+	return g.index[(xsb-g.base[0]+1)&3|(ysb-g.base[1]+1)&3<<2|(zsb-g.base[2]+1)&3<<4|(wsb-g.base[3]+1)&3<<6]
+}
+
+// floorNear returns floorLattice(x), skipping the rounding when x lies in the
+// current super-cell along the axis whose origin is base.
+func floorNear(x float64, base int32) int32 {
+	if x >= float64(base) && x < float64(base)+1 {
+		return base
+	}
+	return floorLattice(x)
+}
+
+// row2 stores the Eval2 samples at the columns xs of row y in row.
+func (g *gridCells) row2(row, xs []float64, y float64) {
+	for i, x := range xs {
+		stretchOffset := float64((x + y) * stretchConstant2D)
+		xs := x + stretchOffset
+		ys := y + stretchOffset
+
+		xsb := floorNear(xs, g.base[0])
+		ysb := floorNear(ys, g.base[1])
+		g.move([4]int32{xsb, ysb})
+
+		squishOffset := float64(float64(xsb+ysb) * squishConstant2D)
+		xb := float64(xsb) + squishOffset
+		yb := float64(ysb) + squishOffset
+
+		xins := xs - float64(xsb)
+		yins := ys - float64(ysb)
+
+		dx0 := x - xb
+		dy0 := y - yb
+
+		row[i] = g.gridEval2Cell(xsb, ysb, xins, yins, dx0, dy0)
+	}
+}
+
+// row3 stores the Eval3 samples at the columns xs of row y and layer z in row.
+func (g *gridCells) row3(row, xs []float64, y, z float64) {
+	for i, x := range xs {
+		stretchOffset := float64((x + y + z) * stretchConstant3D)
+		xs := x + stretchOffset
+		ys := y + stretchOffset
+		zs := z + stretchOffset
+
+		xsb := floorNear(xs, g.base[0])
+		ysb := floorNear(ys, g.base[1])
+		zsb := floorNear(zs, g.base[2])
+		g.move([4]int32{xsb, ysb, zsb})
+
+		squishOffset := float64(float64(xsb+ysb+zsb) * squishConstant3D)
+		xb := float64(xsb) + squishOffset
+		yb := float64(ysb) + squishOffset
+		zb := float64(zsb) + squishOffset
+
+		xins := xs - float64(xsb)
+		yins := ys - float64(ysb)
+		zins := zs - float64(zsb)
+
+		dx0 := x - xb
+		dy0 := y - yb
+		dz0 := z - zb
+
+		row[i] = g.gridEval3Cell(xsb, ysb, zsb, xins, yins, zins, dx0, dy0, dz0)
+	}
+}
+
+// row4 stores the Eval4 samples at the columns xs of row y in row, with z and w
+// pinned.
+func (g *gridCells) row4(row, xs []float64, y, z, w float64) {
+	for i, x := range xs {
+		stretchOffset := float64((x + y + z + w) * stretchConstant4D)
+		xs := x + stretchOffset
+		ys := y + stretchOffset
+		zs := z + stretchOffset
+		ws := w + stretchOffset
+
+		xsb := floorNear(xs, g.base[0])
+		ysb := floorNear(ys, g.base[1])
+		zsb := floorNear(zs, g.base[2])
+		wsb := floorNear(ws, g.base[3])
+		g.move([4]int32{xsb, ysb, zsb, wsb})
+
+		squishOffset := float64(float64(xsb+ysb+zsb+wsb) * squishConstant4D)
+		xb := float64(xsb) + squishOffset
+		yb := float64(ysb) + squishOffset
+		zb := float64(zsb) + squishOffset
+		wb := float64(wsb) + squishOffset
+
+		xins := xs - float64(xsb)
+		yins := ys - float64(ysb)
+		zins := zs - float64(zsb)
+		wins := ws - float64(wsb)
+
+		dx0 := x - xb
+		dy0 := y - yb
+		dz0 := z - zb
+		dw0 := w - wb
+
+		row[i] = g.gridEval4Cell(xsb, ysb, zsb, wsb, xins, yins, zins, wins, dx0, dy0, dz0, dw0)
+	}
+}
+
+// The grid methods of noise produce exactly the values of the matching Eval
+// method, sharing the lattice lookups of neighbouring samples through
+// gridCells.
+
+// Eval2Grid fills dst with width*height samples of Eval2, see GridNoise.
+func (s *noise) Eval2Grid(dst []float64, x0, y0, dx, dy float64, width, height int) {
+	checkGrid(dst, width, height, 1)
+	xs := gridColumns(x0, dx, width)
+
+	for j := 0; j < height; j++ {
+		y := y0 + float64(float64(j)*dy)
+		fillRow(s, dst[j*width:(j+1)*width], xs, 2, y, 0, 0)
+	}
+}
+
+// Eval3Grid fills dst with width*height*depth samples of Eval3, see GridNoise.
+func (s *noise) Eval3Grid(dst []float64, x0, y0, z0, dx, dy, dz float64, width, height, depth int) {
+	checkGrid(dst, width, height, depth)
+	xs := gridColumns(x0, dx, width)
+
+	for k := 0; k < depth; k++ {
+		z := z0 + float64(float64(k)*dz)
+		for j := 0; j < height; j++ {
+			y := y0 + float64(float64(j)*dy)
+			row := (k*height + j) * width
+			fillRow(s, dst[row:row+width], xs, 3, y, z, 0)
+		}
+	}
+}
+
+// Eval4Grid fills dst with a width*height slice of Eval4, see GridNoise.
+func (s *noise) Eval4Grid(dst []float64, x0, y0, z, w, dx, dy float64, width, height int) {
+	checkGrid(dst, width, height, 1)
+	xs := gridColumns(x0, dx, width)
+
+	for j := 0; j < height; j++ {
+		y := y0 + float64(float64(j)*dy)
+		fillRow(s, dst[j*width:(j+1)*width], xs, 4, y, z, w)
+	}
+}
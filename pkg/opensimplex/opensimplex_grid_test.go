@@ -0,0 +1,82 @@
+package opensimplex
+
+import "testing"
+
+func TestGridMatchesEval(t *testing.T) {
+	specs := []GridSpec{
+		{Dims: 2, Origin: [4]float64{-3.5, 2.25}, Step: [3]float64{0.1, 0.3}, Size: [3]int{17, 9}},
+		{Dims: 3, Origin: [4]float64{-3.5, 2.25, 7}, Step: [3]float64{0.1, 0.3, -0.7}, Size: [3]int{17, 9, 5}},
+		{Dims: 4, Origin: [4]float64{-3.5, 2.25, 3.8, 2.7}, Step: [3]float64{0.1, 0.3}, Size: [3]int{17, 9}},
+		// Steps fine enough for the noise grid methods to walk the super-cells.
+		{Dims: 2, Origin: [4]float64{-3.5, 2.25}, Step: [3]float64{0.01, 0.3}, Size: [3]int{700, 9}},
+		{Dims: 3, Origin: [4]float64{-3.5, 2.25, 7}, Step: [3]float64{-0.01, 0.3, -0.7}, Size: [3]int{700, 9, 5}},
+		{Dims: 4, Origin: [4]float64{-3.5, 2.25, 3.8, 2.7}, Step: [3]float64{0.003, 0.3}, Size: [3]int{700, 9}},
+	}
+
+	for _, n := range []Noise{New(5), NewWithHash(5, HashStateless)} {
+		for _, spec := range specs {
+			// FBM does not implement GridNoise, so this exercises the fallback path.
+			for _, impl := range []Noise{n, NewFBM(n, 1, 2, 0.5)} {
+				dst := make([]float64, spec.Len())
+				FillGrid(impl, dst, spec)
+
+				for k := 0; k < spec.depth(); k++ {
+					for j := 0; j < spec.Size[1]; j++ {
+						for i := 0; i < spec.Size[0]; i++ {
+							x := spec.Origin[0] + float64(float64(i)*spec.Step[0])
+							y := spec.Origin[1] + float64(float64(j)*spec.Step[1])
+							z := spec.Origin[2] + float64(float64(k)*spec.Step[2])
+
+							var expected float64
+							switch spec.Dims {
+							case 2:
+								expected = n.Eval2(x, y)
+							case 3:
+								expected = n.Eval3(x, y, z)
+							case 4:
+								expected = n.Eval4(x, y, spec.Origin[2], spec.Origin[3])
+							}
+
+							if actual := dst[(k*spec.Size[1]+j)*spec.Size[0]+i]; actual != expected {
+								t.Fatalf("%T %v %dD grid sample (%d, %d, %d) is %v, expected %v",
+									impl, describe(n).Hash, spec.Dims, i, j, k, actual, expected)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestGridPanicsOnNegativeSize(t *testing.T) {
+	n := New(5).(GridNoise)
+	dst := make([]float64, 16)
+	for name, fill := range map[string]func(){
+		"Eval2Grid": func() { n.Eval2Grid(dst, 0, 0, 1, 1, -2, -2) },
+		"Eval3Grid": func() { n.Eval3Grid(dst, 0, 0, 0, 1, 1, 1, 2, -2, -2) },
+		"Eval4Grid": func() { n.Eval4Grid(dst, 0, 0, 0, 0, 1, 1, -4, 1) },
+		"FillGrid":  func() { FillGrid(n.(Noise), dst, GridSpec{Dims: 3, Size: [3]int{2, 2, -1}}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s accepted a negative size", name)
+				}
+			}()
+			fill()
+		}()
+	}
+}
+
+func BenchmarkGenerateNoiseEval2Grid_10000x10000(b *testing.B) {
+	noise := New(1).(GridNoise)
+	w, h := 10000, 10000
+	heightmap := make([]float64, w*h)
+
+	b.ResetTimer()
+
+	for iter := 0; iter < b.N; iter++ {
+		noise.Eval2Grid(heightmap, 0, 0, 1/float64(w), 1/float64(h), w, h)
+	}
+}
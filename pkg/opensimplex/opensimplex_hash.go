@@ -0,0 +1,195 @@
+package opensimplex
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Hash selects how the lattice vertices are mapped to gradients.
+//
+// The gradient of a vertex is picked by chained lookups into a shuffled
+// permutation table, as in the Java reference. The table size bounds the
+// period of the noise: with 256 entries the pattern repeats every 256 lattice
+// cells along each axis, which can become visible on very large terrains or
+// textures. Larger tables push the period out at the cost of a larger cache
+// footprint, and the stateless hash removes the table altogether.
+//
+// Measured with BenchmarkHashEval3 on amd64:
+//
+//	HashPerm256    period 256      1 KiB of tables, fastest (the default)
+//	HashPerm1024   period 1024     4 KiB of tables, about 25% slower
+//	HashPerm4096   period 4096     16 KiB of tables, about 25% slower, more under cache pressure
+//	HashStateless  period 2^32     no tables, about 40% slower
+//
+// Only HashPerm256 matches the Java reference; the others produce different
+// (but equally distributed) noise for the same seed.
+type Hash int
+
+const (
+	// HashPerm256 uses the 256-entry permutation table of the Java reference.
+	HashPerm256 Hash = iota
+	// HashPerm1024 uses a 1024-entry permutation table.
+	HashPerm1024
+	// HashPerm4096 uses a 4096-entry permutation table.
+	HashPerm4096
+	// HashStateless mixes the seed and the lattice coordinates with an
+	// integer hash instead of looking them up in a table. The lattice only
+	// wraps around at the int32 boundary.
+	HashStateless
+)
+
+var hashNames = []string{"perm256", "perm1024", "perm4096", "stateless"}
+
+// String returns the name of the hash as used in JSON.
+func (h Hash) String() string {
+	if h < 0 || int(h) >= len(hashNames) {
+		return fmt.Sprintf("Hash(%d)", int(h))
+	}
+	return hashNames[h]
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (h Hash) MarshalText() ([]byte, error) {
+	if h < 0 || int(h) >= len(hashNames) {
+		return nil, fmt.Errorf("opensimplex: unknown hash %d", int(h))
+	}
+	return []byte(hashNames[h]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (h *Hash) UnmarshalText(text []byte) error {
+	for k, name := range hashNames {
+		if strings.EqualFold(string(text), name) {
+			*h = Hash(k)
+			return nil
+		}
+	}
+	return fmt.Errorf("opensimplex: unknown hash %q", text)
+}
+
+// NewWithHash constructs a Noise instance with a 64-bit seed that hashes the
+// lattice with h. NewWithHash(seed, HashPerm256) is equivalent to New(seed).
+// Like New, the returned value also implements NoiseWithDerivatives, NoiseAt
+// and Noise1D.
+func NewWithHash(seed int64, h Hash) Noise {
+	return newNoiseWithHash(seed, h)
+}
+
+func newNoiseWithHash(seed int64, h Hash) *noise {
+	s := newNoise(seed)
+	s.hash = h
+
+	switch h {
+	case HashPerm256:
+	case HashPerm1024:
+		s.lattice = newPermHash(seed, 1024)
+	case HashPerm4096:
+		s.lattice = newPermHash(seed, 4096)
+	case HashStateless:
+		s.lattice = statelessHash{seed: mix64(uint64(seed))}
+	default:
+		panic("opensimplex: unknown hash " + h.String())
+	}
+
+	return s
+}
+
+// permHash is a permutation table hash like the default one, but with a table
+// size other than 256.
+type permHash struct {
+	perm            []int16
+	permGradIndex3D []int16
+	mask            int32
+}
+
+// newPermHash shuffles a permutation table of size entries, size being a power
+// of two, the same way newNoise shuffles the 256-entry one.
+func newPermHash(seed int64, size int32) *permHash {
+	p := &permHash{
+		perm:            make([]int16, size),
+		permGradIndex3D: make([]int16, size),
+		mask:            size - 1,
+	}
+
+	source := make([]int16, size)
+	for i := range source {
+		source[i] = int16(i)
+	}
+
+	gradientLenOver3 := int16(len(gradients3D)) / 3
+
+	seed = seed*6364136223846793005 + 1442695040888963407
+	seed = seed*6364136223846793005 + 1442695040888963407
+	seed = seed*6364136223846793005 + 1442695040888963407
+	for i := size - 1; i >= 0; i-- {
+		seed = seed*6364136223846793005 + 1442695040888963407
+		r := int32((seed + 31) % int64(i+1))
+		if r < 0 {
+			r += i + 1
+		}
+
+		p.perm[i] = source[r]
+		p.permGradIndex3D[i] = (p.perm[i] % gradientLenOver3) * 3
+		source[r] = source[i]
+	}
+
+	return p
+}
+
+func (p *permHash) gradIndex1(xsb int32) int16 {
+	return p.perm[xsb&p.mask] & 0x0F
+}
+
+func (p *permHash) gradIndex2(xsb, ysb int32) int16 {
+	return p.perm[(int32(p.perm[xsb&p.mask])+ysb)&p.mask] & 0x0E
+}
+
+func (p *permHash) gradIndex3(xsb, ysb, zsb int32) int16 {
+	return p.permGradIndex3D[(int32(p.perm[(int32(p.perm[xsb&p.mask])+ysb)&p.mask])+zsb)&p.mask]
+}
+
+func (p *permHash) gradIndex4(xsb, ysb, zsb, wsb int32) int16 {
+	return p.perm[(int32(p.perm[(int32(p.perm[(int32(p.perm[xsb&p.mask])+ysb)&p.mask])+zsb)&p.mask])+wsb)&p.mask] & 0xFC
+}
+
+// statelessHash is HashStateless. seed is the avalanched seed of the noise.
+type statelessHash struct {
+	seed uint64
+}
+
+func (h statelessHash) gradIndex1(xsb int32) int16 {
+	return int16(h.sum(xsb, 0, 0, 0) & 0x0F)
+}
+
+func (h statelessHash) gradIndex2(xsb, ysb int32) int16 {
+	return int16(h.sum(xsb, ysb, 0, 0) & 0x0E)
+}
+
+func (h statelessHash) gradIndex3(xsb, ysb, zsb int32) int16 {
+	return int16(h.sum(xsb, ysb, zsb, 0)%24) * 3
+}
+
+func (h statelessHash) gradIndex4(xsb, ysb, zsb, wsb int32) int16 {
+	return int16(h.sum(xsb, ysb, zsb, wsb) & 0xFC)
+}
+
+// sum hashes a lattice vertex: each coordinate is folded in with an
+// xxHash-style multiply, and the result is avalanched by mix64.
+func (h statelessHash) sum(x, y, z, v int32) uint64 {
+	s := h.seed
+	s = (s ^ uint64(uint32(x))) * 0x9E3779B185EBCA87
+	s = (s ^ uint64(uint32(y))) * 0xC2B2AE3D27D4EB4F
+	s = (s ^ uint64(uint32(z))) * 0x165667B19E3779F9
+	s = (s ^ uint64(uint32(v))) * 0x27D4EB2F165667C5
+	return mix64(s)
+}
+
+// mix64 is the 64-bit finalizer of MurmurHash3.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xFF51AFD7ED558CCD
+	h ^= h >> 33
+	h *= 0xC4CEB9FE1A85EC53
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,170 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var allHashes = []Hash{HashPerm256, HashPerm1024, HashPerm4096, HashStateless}
+
+func TestHashPerm256MatchesNew(t *testing.T) {
+	n, h := New(42), NewWithHash(42, HashPerm256)
+
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, y, z, w := r.Float64()*1000, r.Float64()*1000, r.Float64()*1000, r.Float64()*1000
+		if n.Eval2(x, y) != h.Eval2(x, y) || n.Eval3(x, y, z) != h.Eval3(x, y, z) || n.Eval4(x, y, z, w) != h.Eval4(x, y, z, w) {
+			t.Fatalf("HashPerm256 differs from New at %v, %v, %v, %v", x, y, z, w)
+		}
+	}
+}
+
+// tableLattice hashes through the lattice interface with the tables of n, so
+// the lattice copies of the eval functions can be checked against the
+// originals.
+type tableLattice struct {
+	n *noise
+}
+
+func (l tableLattice) gradIndex1(xsb int32) int16 {
+	return l.n.gradIndex1(xsb)
+}
+
+func (l tableLattice) gradIndex2(xsb, ysb int32) int16 {
+	return l.n.gradIndex2(xsb, ysb)
+}
+
+func (l tableLattice) gradIndex3(xsb, ysb, zsb int32) int16 {
+	return l.n.gradIndex3(xsb, ysb, zsb)
+}
+
+func (l tableLattice) gradIndex4(xsb, ysb, zsb, wsb int32) int16 {
+	return l.n.gradIndex4(xsb, ysb, zsb, wsb)
+}
+
+func TestLatticeCopiesMatch(t *testing.T) {
+	n, l := newNoise(42), newNoise(42)
+	l.lattice = tableLattice{n: n}
+
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, y, z, w := r.Float64()*1000, r.Float64()*1000, r.Float64()*1000, r.Float64()*1000
+		if n.Eval1(x) != l.Eval1(x) {
+			t.Fatalf("lattice Eval1 differs at %v", x)
+		}
+
+		var d2, e2 [2]float64
+		var d3, e3 [3]float64
+		var d4, e4 [4]float64
+		if n.eval2(x, y, &d2) != l.eval2(x, y, &e2) || d2 != e2 {
+			t.Fatalf("lattice eval2 differs at %v, %v", x, y)
+		}
+		if n.eval3(x, y, z, &d3) != l.eval3(x, y, z, &e3) || d3 != e3 {
+			t.Fatalf("lattice eval3 differs at %v, %v, %v", x, y, z)
+		}
+		if n.eval4(x, y, z, w, &d4) != l.eval4(x, y, z, w, &e4) || d4 != e4 {
+			t.Fatalf("lattice eval4 differs at %v, %v, %v, %v", x, y, z, w)
+		}
+	}
+}
+
+// unskew2 returns the input coordinates of the lattice point (x, y).
+func unskew2(x, y float64) (float64, float64) {
+	squishOffset := (x + y) * squishConstant2D
+	return x + squishOffset, y + squishOffset
+}
+
+func TestHashPeriod(t *testing.T) {
+	for _, c := range []struct {
+		hash   Hash
+		period float64
+	}{
+		{HashPerm256, 256},
+		{HashPerm1024, 1024},
+		{HashPerm4096, 4096},
+	} {
+		n := NewWithHash(3, c.hash)
+
+		// #nosec: G404
+		r := rand.New(rand.NewSource(1))
+		shorter := 0
+		for i := 0; i < 1000; i++ {
+			lx, ly := r.Float64()*16, r.Float64()*16
+			v := n.Eval2(unskew2(lx, ly))
+
+			if w := n.Eval2(unskew2(lx+c.period, ly)); math.Abs(v-w) > 1e-9 {
+				t.Fatalf("%v does not repeat after %v cells at %v, %v: %v != %v", c.hash, c.period, lx, ly, v, w)
+			}
+			if w := n.Eval2(unskew2(lx+256, ly)); c.period > 256 && math.Abs(v-w) > 1e-9 {
+				shorter++
+			}
+		}
+		if c.period > 256 && shorter == 0 {
+			t.Errorf("%v repeats after 256 cells", c.hash)
+		}
+	}
+}
+
+func TestHashStatelessDoesNotRepeat(t *testing.T) {
+	n := NewWithHash(3, HashStateless)
+	for _, period := range []float64{256, 1024, 4096, 65536} {
+		same := 0
+		for i := 0; i < 100; i++ {
+			lx, ly := float64(i)*0.37, float64(i)*0.61
+			if n.Eval2(unskew2(lx, ly)) == n.Eval2(unskew2(lx+period, ly)) {
+				same++
+			}
+		}
+		if same > 10 {
+			t.Errorf("stateless hash repeats after %v cells for %v of 100 samples", period, same)
+		}
+	}
+}
+
+func TestHashRangeAndSeeds(t *testing.T) {
+	for _, h := range allHashes {
+		a, b := NewWithHash(1, h), NewWithHash(2, h)
+
+		// #nosec: G404
+		r := rand.New(rand.NewSource(1))
+		differ := false
+		for i := 0; i < 10000; i++ {
+			x, y, z, w := r.Float64()*100, r.Float64()*100, r.Float64()*100, r.Float64()*100
+			for _, v := range []float64{a.Eval2(x, y), a.Eval3(x, y, z), a.Eval4(x, y, z, w)} {
+				if math.IsNaN(v) || v < -1 || v > 1 {
+					t.Fatalf("%v value %v out of range at %v, %v, %v, %v", h, v, x, y, z, w)
+				}
+			}
+			if a.Eval3(x, y, z) != b.Eval3(x, y, z) {
+				differ = true
+			}
+		}
+		if !differ {
+			t.Errorf("%v ignores the seed", h)
+		}
+	}
+}
+
+func TestNewWithHashPanicsOnUnknownHash(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewWithHash accepted an unknown hash")
+		}
+	}()
+	NewWithHash(0, Hash(-1))
+}
+
+func BenchmarkHashEval3(b *testing.B) {
+	for _, h := range allHashes {
+		n := NewWithHash(0, h)
+		b.Run(h.String(), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				f := float64(i)
+				n.Eval3(f*0.37, f*0.11, f*0.07)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package opensimplex
+
+import (
+	"image"
+	"image/color"
+)
+
+// Image is a greyscale image.Image whose pixels are lazily computed from the
+// Eval2 of a noise instance, so it can be passed directly to png.Encode and
+// friends.
+//
+// Pixel (x, y) is evaluated at (x*Scale+OffsetX, y*Scale+OffsetY) and
+// normalized into [0, 1) like NewNormalized does, so the base noise should be
+// one created by New. Values of other base noises are clamped into range.
+type Image struct {
+	norm Noise
+
+	// Rect is the image's bounds.
+	Rect image.Rectangle
+	// Scale is the size of a pixel in noise space.
+	Scale float64
+	// OffsetX and OffsetY are the noise space coordinates of pixel (0, 0).
+	OffsetX, OffsetY float64
+}
+
+// NewImage returns an Image of base with the given bounds and pixel scale.
+func NewImage(base Noise, r image.Rectangle, scale float64) *Image {
+	return &Image{
+		norm:  NewRanged(base, 0, 1, RangeClamp),
+		Rect:  r,
+		Scale: scale,
+	}
+}
+
+// ColorModel returns color.Gray16Model.
+func (m *Image) ColorModel() color.Model {
+	return color.Gray16Model
+}
+
+// Bounds returns the image's bounds.
+func (m *Image) Bounds() image.Rectangle {
+	return m.Rect
+}
+
+// Opaque reports that the image is fully opaque, like image.Gray16 does.
+func (m *Image) Opaque() bool {
+	return true
+}
+
+// At returns the color of the pixel at (x, y).
+func (m *Image) At(x, y int) color.Color {
+	return m.Gray16At(x, y)
+}
+
+// Gray16At returns the color of the pixel at (x, y), like image.Gray16 does.
+// Pixels outside of the image's bounds are black.
+func (m *Image) Gray16At(x, y int) color.Gray16 {
+	if !(image.Point{X: x, Y: y}.In(m.Rect)) {
+		return color.Gray16{}
+	}
+
+	return color.Gray16{Y: uint16(m.value(x, y) * 0x10000)}
+}
+
+// value returns the normalized noise value of the pixel at (x, y).
+func (m *Image) value(x, y int) float64 {
+	return m.norm.Eval2(float64(float64(x)*m.Scale)+m.OffsetX, float64(float64(y)*m.Scale)+m.OffsetY)
+}
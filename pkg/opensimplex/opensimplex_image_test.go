@@ -0,0 +1,52 @@
+package opensimplex
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestImageMatchesNormalized(t *testing.T) {
+	m := NewImage(New(0), image.Rect(-8, -8, 56, 40), 1.0/24)
+	m.OffsetX = 3.8
+	norm := NewNormalized(0)
+
+	for y := m.Rect.Min.Y; y < m.Rect.Max.Y; y++ {
+		for x := m.Rect.Min.X; x < m.Rect.Max.X; x++ {
+			want := uint16(norm.Eval2(float64(x)/24+3.8, float64(y)/24) * 0x10000)
+			if got := m.Gray16At(x, y).Y; got != want {
+				t.Fatalf("pixel %v, %v = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+
+	if got := m.Gray16At(56, 0); got != (color.Gray16{}) {
+		t.Fatalf("pixel outside of bounds = %v, want black", got)
+	}
+}
+
+func TestImageEncodesAsPNG(t *testing.T) {
+	m := NewImage(New(0), image.Rect(0, 0, 32, 32), 1.0/24)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, m); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Bounds().Eq(m.Bounds()) {
+		t.Fatalf("decoded bounds %v, want %v", decoded.Bounds(), m.Bounds())
+	}
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			if got, want := color.Gray16Model.Convert(decoded.At(x, y)), m.At(x, y); got != want {
+				t.Fatalf("decoded pixel %v, %v = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
@@ -1,5 +1,7 @@
 package opensimplex
 
+import "math"
+
 const (
 	stretchConstant2D = -0.211324865405187 // (1/Math.sqrt(2+1)-1)/2
 	squishConstant2D  = 0.366025403784439  // (Math.sqrt(2+1)-1)/2
@@ -8,24 +10,116 @@ const (
 	stretchConstant4D = -0.138196601125011 // (1/Math.sqrt(4+1)-1)/4
 	squishConstant4D  = 0.309016994374947  // (Math.sqrt(4+1)-1)/4
 
+	// The stretch constants in Q2.62 fixed point, exactly equal to the float64
+	// values above, for skewing integer coordinates without rounding.
+	stretchConstant2DQ62 int64 = -974563927135150464
+	stretchConstant3DQ62 int64 = -768614336404564608
+	stretchConstant4DQ62 int64 = -637319333202399872
+
 	normConstant2D = 47
 	normConstant3D = 103
 	normConstant4D = 30
 )
 
-func (s *noise) extrapolate2(xsb, ysb int32, dx, dy float64) float64 {
-	index := s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF] & 0x0E
-	return float64(gradients2D[index])*dx + float64(gradients2D[index+1])*dy
+// floorLattice returns the lattice coordinate of x, int32(math.Floor(x)). The
+// conversion of a float that does not fit in an int32 is implementation
+// specific in Go, so such values are instead wrapped modulo 2^32, and NaN and
+// infinities map to 0. Either way the noise is meaningless that far out, but it
+// stays the same on every platform.
+func floorLattice(x float64) int32 {
+	f := math.Floor(x)
+	if f >= math.MinInt32 && f <= math.MaxInt32 {
+		return int32(f)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	return int32(int64(math.Mod(f, 1<<32)))
+}
+
+// lattice hashes lattice vertices to gradient indices in place of the 256-entry
+// permutation table of noise.
+type lattice interface {
+	gradIndex1(xsb int32) int16
+	gradIndex2(xsb, ysb int32) int16
+	gradIndex3(xsb, ysb, zsb int32) int16
+	gradIndex4(xsb, ysb, zsb, wsb int32) int16
+}
+
+// The gradIndex functions look a lattice vertex up in the 256-entry
+// permutation tables, as the Java reference does. The copies of the noise
+// functions in opensimplex_lattice.go use s.lattice instead.
+
+func (s *noise) gradIndex1(xsb int32) int16 {
+	return s.perm[xsb&0xFF] & 0x0F
 }
 
-func (s *noise) extrapolate3(xsb, ysb, zsb int32, dx, dy, dz float64) float64 {
-	index := s.permGradIndex3D[(int32(s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF])+zsb)&0xFF]
-	return float64(gradients3D[index])*dx + float64(gradients3D[index+1])*dy + float64(gradients3D[index+2])*dz
+func (s *noise) gradIndex2(xsb, ysb int32) int16 {
+	return s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF] & 0x0E
 }
 
-func (s *noise) extrapolate4(xsb, ysb, zsb, wsb int32, dx, dy, dz, dw float64) float64 {
-	index := s.perm[(int32(s.perm[(int32(s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF])+zsb)&0xFF])+wsb)&0xFF] & 0xFC
-	return float64(gradients4D[index])*dx + float64(gradients4D[index+1])*dy + float64(gradients4D[index+2])*dz + float64(gradients4D[index+3])*dw
+func (s *noise) gradIndex3(xsb, ysb, zsb int32) int16 {
+	return s.permGradIndex3D[(int32(s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF])+zsb)&0xFF]
+}
+
+func (s *noise) gradIndex4(xsb, ysb, zsb, wsb int32) int16 {
+	return s.perm[(int32(s.perm[(int32(s.perm[(int32(s.perm[xsb&0xFF])+ysb)&0xFF])+zsb)&0xFF])+wsb)&0xFF] & 0xFC
+}
+
+// The contrib functions return the contribution attn^4 * extrapolation of the
+// lattice vertex (xsb, ysb, ...) for a positive attn = 2 - |d|^2. When d is not
+// nil the partial derivatives of the contribution, 4 * attn^3 * -2d * ext + attn^4 * grad,
+// are added to it.
+
+func (s *noise) contrib2(d *[2]float64, attn float64, xsb, ysb int32, dx, dy float64) float64 {
+	index := s.gradIndex2(xsb, ysb)
+	gx := float64(gradients2D[index])
+	gy := float64(gradients2D[index+1])
+	ext := float64(gx*dx) + float64(gy*dy)
+
+	attn2 := attn * attn
+	if d != nil {
+		t := -8 * attn2 * attn * ext
+		d[0] += float64(t*dx) + float64(attn2*attn2*gx)
+		d[1] += float64(t*dy) + float64(attn2*attn2*gy)
+	}
+	return float64(attn2 * attn2 * ext)
+}
+
+func (s *noise) contrib3(d *[3]float64, attn float64, xsb, ysb, zsb int32, dx, dy, dz float64) float64 {
+	index := s.gradIndex3(xsb, ysb, zsb)
+	gx := float64(gradients3D[index])
+	gy := float64(gradients3D[index+1])
+	gz := float64(gradients3D[index+2])
+	ext := float64(gx*dx) + float64(gy*dy) + float64(gz*dz)
+
+	attn2 := attn * attn
+	if d != nil {
+		t := -8 * attn2 * attn * ext
+		d[0] += float64(t*dx) + float64(attn2*attn2*gx)
+		d[1] += float64(t*dy) + float64(attn2*attn2*gy)
+		d[2] += float64(t*dz) + float64(attn2*attn2*gz)
+	}
+	return float64(attn2 * attn2 * ext)
+}
+
+func (s *noise) contrib4(d *[4]float64, attn float64, xsb, ysb, zsb, wsb int32, dx, dy, dz, dw float64) float64 {
+	index := s.gradIndex4(xsb, ysb, zsb, wsb)
+	gx := float64(gradients4D[index])
+	gy := float64(gradients4D[index+1])
+	gz := float64(gradients4D[index+2])
+	gw := float64(gradients4D[index+3])
+	ext := float64(gx*dx) + float64(gy*dy) + float64(gz*dz) + float64(gw*dw)
+
+	attn2 := attn * attn
+	if d != nil {
+		t := -8 * attn2 * attn * ext
+		d[0] += float64(t*dx) + float64(attn2*attn2*gx)
+		d[1] += float64(t*dy) + float64(attn2*attn2*gy)
+		d[2] += float64(t*dz) + float64(attn2*attn2*gz)
+		d[3] += float64(t*dw) + float64(attn2*attn2*gw)
+	}
+	return float64(attn2 * attn2 * ext)
 }
 
 // Gradients for 2D. They approximate the directions to the
@@ -0,0 +1,102 @@
+package opensimplex
+
+import (
+	"math"
+	"math/bits"
+)
+
+// NoiseAt is a seeded 64-bit noise instance that can also be evaluated at
+// coordinates too large for float64, given as an integer chunk origin plus a
+// local offset.
+//
+// Eval2At(cx, cy, fx, fy) is the noise at (cx+fx, cy+fy). The skew onto the
+// lattice is computed in exact integer arithmetic for the chunk origin, so the
+// result is as precise as Eval2 near the origin as long as the offsets stay
+// small, wherever the chunk is. The sum of the chunk coordinates must fit in an
+// int64.
+type NoiseAt interface {
+	Noise
+	Eval2At(cx, cy int64, fx, fy float64) float64
+	Eval3At(cx, cy, cz int64, fx, fy, fz float64) float64
+	Eval4At(cx, cy, cz, cw int64, fx, fy, fz, fw float64) float64
+}
+
+// skewChunk returns sum*stretch, with stretch in Q2.62, split into its floor and
+// the remaining fraction in [0, 1).
+func skewChunk(sum, stretch int64) (int64, float64) {
+	neg := (sum < 0) != (stretch < 0)
+	if sum < 0 {
+		sum = -sum
+	}
+	if stretch < 0 {
+		stretch = -stretch
+	}
+
+	hi, lo := bits.Mul64(uint64(sum), uint64(stretch))
+	q := int64(hi<<2 | lo>>62)
+	r := lo & (1<<62 - 1)
+	if neg {
+		q = -q
+		if r != 0 {
+			q--
+			r = 1<<62 - r
+		}
+	}
+
+	return q, float64(r) / (1 << 62)
+}
+
+// placeAt splits the skewed coordinate c+q+local of a point into the lattice
+// coordinate of its super-cell and the position within the cell.
+func placeAt(c, q int64, local float64) (int32, float64) {
+	fl := math.Floor(local)
+	return int32(c + q + int64(fl)), local - fl
+}
+
+// Eval2At returns the 2D noise value at (cx+fx, cy+fy), see NoiseAt.
+func (s *noise) Eval2At(cx, cy int64, fx, fy float64) float64 {
+	q, frac := skewChunk(cx+cy, stretchConstant2DQ62)
+	stretchOffset := frac + float64((fx+fy)*stretchConstant2D)
+
+	// The super-cell coordinates wrap modulo 2^32 like those of floorLattice.
+	// The permutation tables repeat every 256, 1024 or 4096 cells, which divide
+	// 2^32, so the wrap-around changes nothing for them. The stateless hash
+	// repeats every 2^32 cells instead of never.
+	xsb, xins := placeAt(cx, q, fx+stretchOffset)
+	ysb, yins := placeAt(cy, q, fy+stretchOffset)
+
+	// Unskew the position within the cell to get the position relative to its
+	// origin, instead of unskewing the huge origin itself.
+	squishOffset := float64((xins + yins) * squishConstant2D)
+	return s.eval2Cell(xsb, ysb, xins, yins, xins+squishOffset, yins+squishOffset, nil)
+}
+
+// Eval3At returns the 3D noise value at (cx+fx, cy+fy, cz+fz), see NoiseAt.
+func (s *noise) Eval3At(cx, cy, cz int64, fx, fy, fz float64) float64 {
+	q, frac := skewChunk(cx+cy+cz, stretchConstant3DQ62)
+	stretchOffset := frac + float64((fx+fy+fz)*stretchConstant3D)
+
+	xsb, xins := placeAt(cx, q, fx+stretchOffset)
+	ysb, yins := placeAt(cy, q, fy+stretchOffset)
+	zsb, zins := placeAt(cz, q, fz+stretchOffset)
+
+	squishOffset := float64((xins + yins + zins) * squishConstant3D)
+	return s.eval3Cell(xsb, ysb, zsb, xins, yins, zins,
+		xins+squishOffset, yins+squishOffset, zins+squishOffset, nil)
+}
+
+// Eval4At returns the 4D noise value at (cx+fx, cy+fy, cz+fz, cw+fw), see
+// NoiseAt.
+func (s *noise) Eval4At(cx, cy, cz, cw int64, fx, fy, fz, fw float64) float64 {
+	q, frac := skewChunk(cx+cy+cz+cw, stretchConstant4DQ62)
+	stretchOffset := frac + float64((fx+fy+fz+fw)*stretchConstant4D)
+
+	xsb, xins := placeAt(cx, q, fx+stretchOffset)
+	ysb, yins := placeAt(cy, q, fy+stretchOffset)
+	zsb, zins := placeAt(cz, q, fz+stretchOffset)
+	wsb, wins := placeAt(cw, q, fw+stretchOffset)
+
+	squishOffset := float64((xins + yins + zins + wins) * squishConstant4D)
+	return s.eval4Cell(xsb, ysb, zsb, wsb, xins, yins, zins, wins,
+		xins+squishOffset, yins+squishOffset, zins+squishOffset, wins+squishOffset, nil)
+}
@@ -0,0 +1,60 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestEvalAtMatchesEval(t *testing.T) {
+	for _, h := range allHashes {
+		testEvalAtMatchesEval(t, NewWithHash(6, h).(NoiseAt))
+	}
+}
+
+func testEvalAtMatchesEval(t *testing.T, n NoiseAt) {
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		cx, cy, cz, cw := r.Int63n(2000)-1000, r.Int63n(2000)-1000, r.Int63n(2000)-1000, r.Int63n(2000)-1000
+		fx, fy, fz, fw := r.Float64(), r.Float64(), r.Float64(), r.Float64()
+		x, y, z, w := float64(cx)+fx, float64(cy)+fy, float64(cz)+fz, float64(cw)+fw
+
+		for _, c := range []struct{ at, eval float64 }{
+			{n.Eval2At(cx, cy, fx, fy), n.Eval2(x, y)},
+			{n.Eval3At(cx, cy, cz, fx, fy, fz), n.Eval3(x, y, z)},
+			{n.Eval4At(cx, cy, cz, cw, fx, fy, fz, fw), n.Eval4(x, y, z, w)},
+		} {
+			if math.Abs(c.at-c.eval) > 1e-9 {
+				t.Fatalf("%v: EvalAt value %v differs from Eval value %v at %v, %v, %v, %v", describe(n).Hash, c.at, c.eval, x, y, z, w)
+			}
+		}
+	}
+}
+
+func TestEvalAtLargeChunks(t *testing.T) {
+	for _, h := range allHashes {
+		testEvalAtLargeChunks(t, NewWithHash(6, h).(NoiseAt))
+	}
+}
+
+func testEvalAtLargeChunks(t *testing.T, n NoiseAt) {
+	// Far beyond the range of int32 and of float64 precision, moving a unit
+	// from the offset into the chunk origin must not change the value.
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		cx, cy, cz, cw := r.Int63()>>3, -r.Int63()>>3, r.Int63()>>3, -r.Int63()>>3
+		fx, fy, fz, fw := r.Float64(), r.Float64(), r.Float64(), r.Float64()
+
+		for _, c := range []struct{ a, b float64 }{
+			{n.Eval2At(cx, cy, fx, fy), n.Eval2At(cx+1, cy-1, fx-1, fy+1)},
+			{n.Eval3At(cx, cy, cz, fx, fy, fz), n.Eval3At(cx-1, cy+1, cz+1, fx+1, fy-1, fz-1)},
+			{n.Eval4At(cx, cy, cz, cw, fx, fy, fz, fw), n.Eval4At(cx+1, cy, cz-1, cw+1, fx-1, fy, fz+1, fw-1)},
+		} {
+			if math.Abs(c.a-c.b) > 1e-9 {
+				t.Fatalf("%v: EvalAt values %v and %v differ for chunk %v, %v, %v, %v", describe(n).Hash, c.a, c.b, cx, cy, cz, cw)
+			}
+		}
+	}
+}
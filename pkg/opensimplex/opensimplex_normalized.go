@@ -1,97 +1,171 @@
 package opensimplex
 
+import (
+	"fmt"
+	"math"
+)
+
 const (
 	// The normMin and normScale constants are used
 	// in the formula for normalizing the raw output
-	// of the OpenSimplex algorithm. They were
-	// derived from empirical observations of the
-	// range of raw values. Different constants are
-	// required for each of Eval2, Eval3, and Eval4.
-	normMin2   = 0.8659203878240322
-	normScale2 = 0.577420288914181
-
-	normMin3   = 0.9871048542519545
-	normScale3 = 0.506595297177236
-
-	normMin4   = 1.0040848236330158
-	normScale4 = 0.5007450643319374
+	// of the OpenSimplex algorithm. Each normMin is
+	// a proven upper bound of |Eval| over all seeds
+	// and inputs, derived by deriveBound in
+	// opensimplex_bounds_test.go, and each normScale maps
+	// [-normMin, normMin] onto [0, 1]. Different
+	// constants are required for each of Eval2,
+	// Eval3, and Eval4.
+	normMin2   = 0.8659203899
+	normScale2 = 1 / (2 * normMin2)
+
+	normMin3   = 0.9871465433
+	normScale3 = 1 / (2 * normMin3)
+
+	normMin4   = 1.0342209423
+	normScale4 = 1 / (2 * normMin4)
+)
+
+// RangeMode selects how NewRanged maps the raw output of a noise onto the
+// requested range.
+type RangeMode int
+
+const (
+	// RangeLinear maps the proven output range of the OpenSimplex noise
+	// linearly onto [lo, hi). The range only holds for base noises that stay
+	// within it: New, NewWithHash, NewDeterministic, NewGenerator, and FBM
+	// wrappers of those. Billow and Ridged reach down to -1 regardless, so
+	// use RangeClamp or RangeSmoothstep for them.
+	RangeLinear RangeMode = iota
+	// RangeClamp maps values like RangeLinear, but clamps them into [lo, hi),
+	// so the range holds for any base noise.
+	RangeClamp
+	// RangeSmoothstep maps values like RangeClamp, then eases them with
+	// 3t^2 - 2t^3, flattening the output near lo and hi.
+	RangeSmoothstep
 )
 
-type normNoise struct {
-	base Noise
+var rangeModeNames = []string{"linear", "clamp", "smoothstep"}
+
+// String returns the name of the mode.
+func (mode RangeMode) String() string {
+	if mode < 0 || int(mode) >= len(rangeModeNames) {
+		return fmt.Sprintf("RangeMode(%d)", int(mode))
+	}
+	return rangeModeNames[mode]
+}
+
+// NewRanged wraps base so its Eval methods return values in [lo, hi), mapped
+// according to mode. NewRanged(New(seed), 0, 1, RangeLinear) is equivalent to
+// NewNormalized(seed).
+func NewRanged(base Noise, lo, hi float64, mode RangeMode) Noise {
+	checkRange(lo < hi, mode)
+	return &rangedNoise{base: base, lo: lo, hi: hi, mode: mode}
+}
+
+// NewRanged32 is like NewRanged, but returns float32 values in [lo, hi). Values
+// are mapped in float64 and only rounded at the end, so they keep the
+// precision of a 64-bit base noise. To range a 32-bit noise instance, wrap it
+// with Widen.
+func NewRanged32(base Noise, lo, hi float32, mode RangeMode) Noise32 {
+	checkRange(lo < hi, mode)
+	return &rangedNoise32{base: base, lo: lo, hi: hi, mode: mode}
+}
+
+func checkRange(ordered bool, mode RangeMode) {
+	if !ordered {
+		panic("opensimplex: range requires lo < hi")
+	}
+	if mode < RangeLinear || mode > RangeSmoothstep {
+		panic("opensimplex: unknown range mode")
+	}
+}
+
+// shape maps a raw value r of a noise bounded by normMin into [0, 1] according
+// to mode.
+func (mode RangeMode) shape(r, normMin, normScale float64) float64 {
+	t := (r + normMin) * normScale
+	if mode == RangeLinear {
+		return t
+	}
+
+	t = math.Min(math.Max(t, 0), 1)
+	if mode == RangeSmoothstep {
+		t = t * t * (3 - 2*t)
+	}
+	return t
+}
+
+type rangedNoise struct {
+	base   Noise
+	lo, hi float64
+	mode   RangeMode
 }
 
 // Eval2 returns a random noise value in two dimensions
-// in the range [0, 1).
-func (s *normNoise) Eval2(x, y float64) float64 {
-	// return norm2_64(s.base.Eval2(x, y))
+// in the range [lo, hi).
+func (s *rangedNoise) Eval2(x, y float64) float64 {
 	r := s.base.Eval2(x, y)
-	return (r + normMin2) * normScale2
+	return s.scale(s.mode.shape(r, normMin2, normScale2))
 }
 
 // Eval3 returns a random noise value in three dimensions
-// in the range [0, 1).
-func (s *normNoise) Eval3(x, y, z float64) float64 {
+// in the range [lo, hi).
+func (s *rangedNoise) Eval3(x, y, z float64) float64 {
 	r := s.base.Eval3(x, y, z)
-	return (r + normMin3) * normScale3
+	return s.scale(s.mode.shape(r, normMin3, normScale3))
 }
 
 // Eval4 returns a random noise value in four dimensions
-// in the range [0, 1).
-func (s *normNoise) Eval4(x, y, z, t float64) float64 {
+// in the range [lo, hi).
+func (s *rangedNoise) Eval4(x, y, z, t float64) float64 {
 	r := s.base.Eval4(x, y, z, t)
-	return (r + normMin4) * normScale4
+	return s.scale(s.mode.shape(r, normMin4, normScale4))
+}
+
+// scale maps t from [0, 1] onto [lo, hi). A t of 1, or one that rounds up to
+// hi, maps to the largest value below hi. The product is kept out of an FMA,
+// like those of the noise itself.
+func (s *rangedNoise) scale(t float64) float64 {
+	if v := s.lo + float64(t*(s.hi-s.lo)); v < s.hi {
+		return v
+	}
+	return math.Nextafter(s.hi, s.lo)
 }
 
-type normNoise32 struct {
-	base Noise
+type rangedNoise32 struct {
+	base   Noise
+	lo, hi float32
+	mode   RangeMode
 }
 
 // Eval2 returns a random noise value in two dimensions
-// in the range [0, 1).
-func (s *normNoise32) Eval2(x, y float32) float32 {
+// in the range [lo, hi).
+func (s *rangedNoise32) Eval2(x, y float32) float32 {
 	r := s.base.Eval2(float64(x), float64(y))
-	norm64 := (r + normMin2) * normScale2
-	norm32 := float32(norm64)
-
-	// Empirical testing shows that a simple float32 cast
-	// from the normalized float64, as above, will sometimes
-	// produce a value of 1.0.
-	if norm32 >= 1.0 {
-		return float32(0.999999)
-	} else {
-		return norm32
-	}
+	return s.scale(s.mode.shape(r, normMin2, normScale2))
 }
 
 // Eval3 returns a random noise value in three dimensions
-// in the range [0, 1).
-func (s *normNoise32) Eval3(x, y, z float32) float32 {
+// in the range [lo, hi).
+func (s *rangedNoise32) Eval3(x, y, z float32) float32 {
 	r := s.base.Eval3(float64(x), float64(y), float64(z))
-	norm64 := (r + normMin3) * normScale3
-	norm32 := float32(norm64)
-
-	// Unlike Eval2, have not actually tested whether a
-	// simple float32 cast will produce 1.0, but it seems likely.
-	if norm32 >= 1.0 {
-		return float32(0.999999)
-	} else {
-		return norm32
-	}
+	return s.scale(s.mode.shape(r, normMin3, normScale3))
 }
 
 // Eval4 returns a random noise value in four dimensions
-// in the range [0, 1).
-func (s *normNoise32) Eval4(x, y, z, t float32) float32 {
+// in the range [lo, hi).
+func (s *rangedNoise32) Eval4(x, y, z, t float32) float32 {
 	r := s.base.Eval4(float64(x), float64(y), float64(z), float64(t))
-	norm64 := (r + normMin4) * normScale4
-	norm32 := float32(norm64)
-
-	// Unlike Eval2, have not actually tested whether a
-	// simple float32 cast will produce 1.0, but it seems likely.
-	if norm32 >= 1.0 {
-		return float32(0.999999)
-	} else {
-		return norm32
+	return s.scale(s.mode.shape(r, normMin4, normScale4))
+}
+
+// scale maps t from [0, 1] onto [lo, hi) in float64, then rounds to float32.
+// Rounding may land on hi even when the float64 value is below it, so such
+// values map to the largest float32 below hi.
+func (s *rangedNoise32) scale(t float64) float32 {
+	lo, hi := float64(s.lo), float64(s.hi)
+	if v := float32(lo + float64(t*(hi-lo))); v < s.hi {
+		return v
 	}
+	return math.Nextafter32(s.hi, s.lo)
 }
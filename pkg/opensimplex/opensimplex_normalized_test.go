@@ -0,0 +1,164 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var allRangeModes = []RangeMode{RangeLinear, RangeClamp, RangeSmoothstep}
+
+// constNoise returns the same value from every Eval method.
+type constNoise float64
+
+func (c constNoise) Eval2(x, y float64) float64       { return float64(c) }
+func (c constNoise) Eval3(x, y, z float64) float64    { return float64(c) }
+func (c constNoise) Eval4(x, y, z, w float64) float64 { return float64(c) }
+
+func TestRangedMatchesNormalized(t *testing.T) {
+	n, r := NewNormalized(42), NewRanged(New(42), 0, 1, RangeLinear)
+	n32, r32 := NewNormalized32(42), NewRanged32(New(42), 0, 1, RangeLinear)
+
+	// #nosec: G404
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, y, z, w := rnd.Float64()*100, rnd.Float64()*100, rnd.Float64()*100, rnd.Float64()*100
+		if n.Eval2(x, y) != r.Eval2(x, y) || n.Eval3(x, y, z) != r.Eval3(x, y, z) || n.Eval4(x, y, z, w) != r.Eval4(x, y, z, w) {
+			t.Fatalf("NewRanged differs from NewNormalized at %v, %v, %v, %v", x, y, z, w)
+		}
+
+		x32, y32, z32, w32 := float32(x), float32(y), float32(z), float32(w)
+		if n32.Eval2(x32, y32) != r32.Eval2(x32, y32) || n32.Eval3(x32, y32, z32) != r32.Eval3(x32, y32, z32) ||
+			n32.Eval4(x32, y32, z32, w32) != r32.Eval4(x32, y32, z32, w32) {
+			t.Fatalf("NewRanged32 differs from NewNormalized32 at %v, %v, %v, %v", x, y, z, w)
+		}
+	}
+}
+
+func TestRanged32OfNoise32(t *testing.T) {
+	n32 := New32(7)
+	r := NewRanged32(Widen(n32), -1, 1, RangeClamp)
+
+	// #nosec: G404
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y, z := rnd.Float32()*100, rnd.Float32()*100, rnd.Float32()*100
+		want := float32(RangeClamp.shape(float64(n32.Eval3(x, y, z)), normMin3, normScale3)*2 - 1)
+		if v := r.Eval3(x, y, z); v != want {
+			t.Fatalf("NewRanged32 of New32 at %v, %v, %v is %v, want %v", x, y, z, v, want)
+		}
+	}
+
+	if d := describe(r); d.Precision != PrecisionFloat32 || d.Seed != 7 || d.Lo != -1 || d.Mode != RangeClamp {
+		t.Errorf("NewRanged32 of New32 describes as %+v", d)
+	}
+}
+
+func TestRangedEnvelope(t *testing.T) {
+	for _, rng := range [][2]float64{{-100, 2500}, {0, 255}, {-1, 1}} {
+		lo, hi := rng[0], rng[1]
+		for _, mode := range allRangeModes {
+			for _, sign := range []float64{1, -1} {
+				env := newEnvelope(sign)
+				n, n32 := NewRanged(env, lo, hi, mode), NewRanged32(env, float32(lo), float32(hi), mode)
+
+				cellGrid(2, 41, func(p [4]float64) {
+					v, v32 := n.Eval2(p[0], p[1]), n32.Eval2(float32(p[0]), float32(p[1]))
+					if v < lo || v >= hi || float64(v32) < lo || float64(v32) >= hi {
+						t.Fatalf("mode %d: envelope at %v is %v (float32 %v), outside [%v, %v)", mode, p[:2], v, v32, lo, hi)
+					}
+				})
+				cellGrid(3, 13, func(p [4]float64) {
+					v, v32 := n.Eval3(p[0], p[1], p[2]), n32.Eval3(float32(p[0]), float32(p[1]), float32(p[2]))
+					if v < lo || v >= hi || float64(v32) < lo || float64(v32) >= hi {
+						t.Fatalf("mode %d: envelope at %v is %v (float32 %v), outside [%v, %v)", mode, p[:3], v, v32, lo, hi)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestRangedClamp(t *testing.T) {
+	for _, mode := range []RangeMode{RangeClamp, RangeSmoothstep} {
+		for _, c := range []struct {
+			raw    constNoise
+			want   float64
+			want32 float32
+		}{
+			{-5, -100, -100},
+			{5, math.Nextafter(2500, 0), math.Nextafter32(2500, 0)},
+			{0, 1200, 1200},
+		} {
+			n := NewRanged(c.raw, -100, 2500, mode)
+			if v := n.Eval2(0, 0); v != c.want {
+				t.Errorf("mode %d: Eval2 of %v = %v, want %v", mode, c.raw, v, c.want)
+			}
+			if v := n.Eval4(0, 0, 0, 0); v != c.want {
+				t.Errorf("mode %d: Eval4 of %v = %v, want %v", mode, c.raw, v, c.want)
+			}
+			if v := NewRanged32(c.raw, -100, 2500, mode).Eval3(0, 0, 0); v != c.want32 {
+				t.Errorf("mode %d: float32 Eval3 of %v = %v, want %v", mode, c.raw, v, c.want32)
+			}
+		}
+	}
+
+	// Linear does not clamp.
+	if v := NewRanged(constNoise(-5), 0, 1, RangeLinear).Eval2(0, 0); v >= 0 {
+		t.Errorf("RangeLinear clamped %v", v)
+	}
+}
+
+func TestRangedBillow(t *testing.T) {
+	// Billow is -1 wherever every octave is zero, as at the origin, which is
+	// outside the range RangeLinear assumes.
+	b := NewBillow(New(1), 3, 2, 0.5)
+	if v := NewRanged(b, 0, 1, RangeLinear).Eval2(0, 0); v >= 0 {
+		t.Errorf("linear billow at the origin is %v, expected below 0", v)
+	}
+	if v := NewRanged(b, 0, 1, RangeClamp).Eval2(0, 0); v != 0 {
+		t.Errorf("clamped billow at the origin is %v, want 0", v)
+	}
+}
+
+func TestRangedSmoothstep(t *testing.T) {
+	lin, smooth := NewRanged(constNoise(normMin2/2), 0, 1, RangeClamp), NewRanged(constNoise(normMin2/2), 0, 1, RangeSmoothstep)
+	if l, s := lin.Eval2(0, 0), smooth.Eval2(0, 0); !(s > l) || math.Abs(s-l*l*(3-2*l)) > 1e-12 {
+		t.Errorf("smoothstep of %v = %v", l, s)
+	}
+}
+
+func TestRangedRoundsBelowHi(t *testing.T) {
+	n := &rangedNoise{lo: -100, hi: 2500}
+	if v := n.scale(1); v >= 2500 {
+		t.Errorf("scale(1) = %v, not below 2500", v)
+	}
+
+	n32 := &rangedNoise32{lo: 0, hi: 1}
+	for _, v := range []float64{1 - 1e-10, 1 - 1.0/(1<<25), 1} {
+		if got, want := n32.scale(v), math.Nextafter32(1, 0); got != want {
+			t.Errorf("scale(%v) = %v, want %v", v, got, want)
+		}
+	}
+}
+
+func TestNewRangedPanics(t *testing.T) {
+	for _, c := range []struct {
+		lo, hi float64
+		mode   RangeMode
+	}{
+		{1, 1, RangeLinear},
+		{2, 1, RangeClamp},
+		{math.NaN(), 1, RangeClamp},
+		{0, 1, RangeMode(3)},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewRanged accepted [%v, %v) with mode %d", c.lo, c.hi, c.mode)
+				}
+			}()
+			NewRanged(New(0), c.lo, c.hi, c.mode)
+		}()
+	}
+}
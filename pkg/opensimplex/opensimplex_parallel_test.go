@@ -0,0 +1,57 @@
+package opensimplex
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFillParallelMatchesFillGrid(t *testing.T) {
+	n := New(11)
+	specs := []GridSpec{
+		{Dims: 2, Origin: [4]float64{-3.5, 2.25}, Step: [3]float64{0.1, 0.3}, Size: [3]int{64, 48}},
+		{Dims: 3, Origin: [4]float64{-3.5, 2.25, 7}, Step: [3]float64{0.1, 0.3, -0.7}, Size: [3]int{32, 16, 8}},
+		{Dims: 4, Origin: [4]float64{-3.5, 2.25, 3.8, 2.7}, Step: [3]float64{0.1, 0.3}, Size: [3]int{64, 48}},
+	}
+
+	// The same instances are shared by all workers, run with -race to check that
+	// concurrent evaluation is safe.
+	for _, impl := range []Noise{n, NewNormalized(11), NewFBM(n, 4, 2, 0.5)} {
+		for _, spec := range specs {
+			expected := make([]float64, spec.Len())
+			FillGrid(impl, expected, spec)
+
+			actual := make([]float64, spec.Len())
+			if err := FillParallel(context.Background(), impl, actual, spec, 4); err != nil {
+				t.Fatalf("FillParallel failed: %v", err)
+			}
+
+			for i := range expected {
+				if actual[i] != expected[i] {
+					t.Fatalf("%T %dD sample %d is %v, expected %v", impl, spec.Dims, i, actual[i], expected[i])
+				}
+			}
+		}
+	}
+}
+
+func TestFillParallelCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	spec := GridSpec{Dims: 2, Step: [3]float64{0.1, 0.1}, Size: [3]int{16, 16}}
+	dst := make([]float64, spec.Len())
+	for i := range dst {
+		dst[i] = -2
+	}
+
+	if err := FillParallel(ctx, New(1), dst, spec, 2); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+
+	for i, v := range dst {
+		if v != -2 {
+			t.Fatalf("sample %d was written after cancellation", i)
+		}
+	}
+}
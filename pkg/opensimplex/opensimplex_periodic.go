@@ -0,0 +1,83 @@
+package opensimplex
+
+import "math"
+
+// Periodic evaluates 2D and 3D noise that repeats exactly every px, py and pz
+// units along the x, y and z axes.
+//
+// Unlike Tileable2D, no extra dimension is involved: the lattice vertices are
+// wrapped modulo the periods before their gradients are hashed. The lattice of
+// OpenSimplex is skewed relative to the input space, so Periodic takes its
+// coordinates in lattice space and unskews them itself. As a result, features
+// are sheared along the main diagonal compared to those of New.
+type Periodic struct {
+	n *noise
+
+	px, py, pz float64
+}
+
+// NewPeriodic constructs a Periodic instance with a 64-bit seed and the given
+// lattice periods. Eval2 ignores pz. All periods must be positive.
+func NewPeriodic(seed int64, px, py, pz int) *Periodic {
+	if px < 1 || py < 1 || pz < 1 || px > math.MaxInt32 || py > math.MaxInt32 || pz > math.MaxInt32 {
+		panic("opensimplex: periodic noise requires positive int32 periods")
+	}
+
+	n := newNoise(seed)
+	n.lattice = &periodicLattice{n: n, period: [3]int32{int32(px), int32(py), int32(pz)}}
+
+	return &Periodic{n: n, px: float64(px), py: float64(py), pz: float64(pz)}
+}
+
+// Eval2 returns a random noise value in two dimensions. It satisfies
+// Eval2(x+px, y) == Eval2(x, y+py) == Eval2(x, y) up to rounding.
+func (p *Periodic) Eval2(x, y float64) float64 {
+	// Reduce first, so that the precision does not depend on how many periods
+	// away from the origin the point is.
+	x, y = math.Mod(x, p.px), math.Mod(y, p.py)
+
+	squishOffset := (x + y) * squishConstant2D
+	return p.n.eval2(x+squishOffset, y+squishOffset, nil)
+}
+
+// Eval3 returns a random noise value in three dimensions. It satisfies
+// Eval3(x+px, y, z) == Eval3(x, y+py, z) == Eval3(x, y, z+pz) == Eval3(x, y, z)
+// up to rounding.
+func (p *Periodic) Eval3(x, y, z float64) float64 {
+	x, y, z = math.Mod(x, p.px), math.Mod(y, p.py), math.Mod(z, p.pz)
+
+	squishOffset := (x + y + z) * squishConstant3D
+	return p.n.eval3(x+squishOffset, y+squishOffset, z+squishOffset, nil)
+}
+
+// periodicLattice wraps the x, y and z lattice coordinates modulo the periods
+// before looking them up in the tables of n.
+type periodicLattice struct {
+	n      *noise
+	period [3]int32
+}
+
+func (p *periodicLattice) gradIndex1(xsb int32) int16 {
+	return p.n.gradIndex1(wrapLattice(xsb, p.period[0]))
+}
+
+func (p *periodicLattice) gradIndex2(xsb, ysb int32) int16 {
+	return p.n.gradIndex2(wrapLattice(xsb, p.period[0]), wrapLattice(ysb, p.period[1]))
+}
+
+func (p *periodicLattice) gradIndex3(xsb, ysb, zsb int32) int16 {
+	return p.n.gradIndex3(wrapLattice(xsb, p.period[0]), wrapLattice(ysb, p.period[1]), wrapLattice(zsb, p.period[2]))
+}
+
+func (p *periodicLattice) gradIndex4(xsb, ysb, zsb, wsb int32) int16 {
+	return p.n.gradIndex4(wrapLattice(xsb, p.period[0]), wrapLattice(ysb, p.period[1]), wrapLattice(zsb, p.period[2]), wsb)
+}
+
+// wrapLattice reduces the lattice coordinate v into [0, period).
+func wrapLattice(v, period int32) int32 {
+	v %= period
+	if v < 0 {
+		v += period
+	}
+	return v
+}
@@ -0,0 +1,64 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPeriodicRepeats(t *testing.T) {
+	p := NewPeriodic(9, 5, 7, 3)
+
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, y, z := r.Float64()*5, r.Float64()*7, r.Float64()*3
+
+		v2 := p.Eval2(x, y)
+		for _, w := range []float64{p.Eval2(x+5, y), p.Eval2(x, y-7), p.Eval2(x+500, y+700)} {
+			if math.Abs(v2-w) > 1e-9 {
+				t.Fatalf("periodic 2D value at %v, %v does not repeat: %v != %v", x, y, v2, w)
+			}
+		}
+
+		v3 := p.Eval3(x, y, z)
+		for _, w := range []float64{p.Eval3(x-5, y, z), p.Eval3(x, y+7, z), p.Eval3(x, y, z+3), p.Eval3(x+50, y-70, z+30)} {
+			if math.Abs(v3-w) > 1e-9 {
+				t.Fatalf("periodic 3D value at %v, %v, %v does not repeat: %v != %v", x, y, z, v3, w)
+			}
+		}
+	}
+}
+
+func TestPeriodicRepeatsBeyondInt32(t *testing.T) {
+	p := NewPeriodic(9, 5, 7, 3)
+
+	// Shifts by whole periods past the int32 range of the lattice are exact in
+	// float64 for these coordinates, so only the rounding of the reduced
+	// coordinates may differ.
+	const far = 1 << 33
+	for i := 0; i < 1000; i++ {
+		x, y, z := float64(i%40)/8, float64(i%56)/8, float64(i%24)/8
+		if a, b := p.Eval2(x, y), p.Eval2(x+5*far, y-7*far); math.Abs(a-b) > 1e-9 {
+			t.Fatalf("periodic 2D value at %v, %v does not repeat far away: %v != %v", x, y, a, b)
+		}
+		if a, b := p.Eval3(x, y, z), p.Eval3(x-5*far, y+7*far, z+3*far); math.Abs(a-b) > 1e-9 {
+			t.Fatalf("periodic 3D value at %v, %v, %v does not repeat far away: %v != %v", x, y, z, a, b)
+		}
+	}
+}
+
+func TestPeriodicMatchesNewInsideFirstPeriod(t *testing.T) {
+	// Wrapping a period of 256 is a no-op for the 256-entry permutation table,
+	// so the periodic noise is New sampled at unskewed coordinates.
+	p := NewPeriodic(9, 256, 256, 256)
+	n := New(9)
+
+	for i := 0; i < 100; i++ {
+		x, y := float64(i)*0.73, float64(i)*1.31
+		s := (x + y) * squishConstant2D
+		if a, b := p.Eval2(x, y), n.Eval2(x+s, y+s); math.Abs(a-b) > 1e-12 {
+			t.Fatalf("periodic value %v differs from New value %v", a, b)
+		}
+	}
+}
@@ -20,15 +20,20 @@ import (
 	"compress/gzip"
 	"encoding/json"
 	"io"
+	"math"
 	"os"
 	"path"
 	"testing"
 )
 
 func loadSamples() <-chan []float64 {
+	return loadSamplesFile("opensimplex_test_samples.json.gz")
+}
+
+func loadSamplesFile(name string) <-chan []float64 {
 	c := make(chan []float64)
 	go func() {
-		f, err := os.Open(path.Join("opensimplex_test_samples.json.gz"))
+		f, err := os.Open(path.Join(name))
 		if err != nil {
 			panic(err.Error())
 		}
@@ -58,8 +63,14 @@ func loadSamples() <-chan []float64 {
 }
 
 func TestSamplesMatch(t *testing.T) {
-	samples := loadSamples()
-	n := New(0)
+	matchSamples(t, "opensimplex_test_samples.json.gz", New(0), 0)
+}
+
+// matchSamples checks that n reproduces every sample in the named file within
+// tol times the largest coordinate magnitude, or exactly when tol is zero.
+func matchSamples(t *testing.T, name string, n Noise, tol float64) {
+	t.Helper()
+	samples := loadSamplesFile(name)
 
 	for s := range samples {
 		var expected, actual float64
@@ -77,13 +88,47 @@ func TestSamplesMatch(t *testing.T) {
 			t.Fatalf("Unexpected size sample: %d", len(s))
 		}
 
-		if expected != actual {
+		if math.Abs(expected-actual) > sampleTolerance(tol, s[:len(s)-1]) || (tol == 0 && expected != actual) {
 			t.Fatalf("Expected %v, got %v for %dD sample at %v",
 				expected, actual, len(s)-1, s[:len(s)-1])
 		}
 	}
 }
 
+func TestSamplesMatch32(t *testing.T) {
+	samples := loadSamples()
+	n := New32(0)
+
+	for s := range samples {
+		var expected, actual float64
+		switch len(s) {
+		case 3:
+			expected = s[2]
+			actual = float64(n.Eval2(float32(s[0]), float32(s[1])))
+		case 4:
+			expected = s[3]
+			actual = float64(n.Eval3(float32(s[0]), float32(s[1]), float32(s[2])))
+		case 5:
+			expected = s[4]
+			actual = float64(n.Eval4(float32(s[0]), float32(s[1]), float32(s[2]), float32(s[3])))
+		default:
+			t.Fatalf("Unexpected size sample: %d", len(s))
+		}
+
+		// float32 loses absolute precision as the coordinates grow, so the error
+		// is bounded relative to the largest input coordinate.
+		magnitude := float64(1)
+		for _, c := range s[:len(s)-1] {
+			magnitude = math.Max(magnitude, math.Abs(c))
+		}
+
+		if math.Abs(expected-actual) > 1e-6*magnitude {
+			t.Fatalf("Expected %v, got %v for 32-bit %dD sample at %v",
+				expected, actual, len(s)-1, s[:len(s)-1])
+		}
+	}
+}
+
 func BenchmarkSamples(b *testing.B) {
 	samples := loadSamples()
 	n := New(0)
@@ -127,3 +172,19 @@ func BenchmarkGenerateNoiseEval2_10000x10000(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkGenerateNoise32Eval2_10000x10000(b *testing.B) {
+	noise := New32(1)
+	w, h := 10000, 10000
+	heightmap := make([]float32, w*h)
+
+	b.ResetTimer()
+
+	for iter := 0; iter < b.N; iter++ {
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				heightmap[(y*w)+x] = noise.Eval2(float32(x)/float32(w), float32(y)/float32(h))
+			}
+		}
+	}
+}
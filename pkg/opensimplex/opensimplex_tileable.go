@@ -0,0 +1,54 @@
+package opensimplex
+
+import "math"
+
+// Tileable2D evaluates 2D noise that wraps seamlessly every width units along x
+// and every height units along y.
+//
+// Each axis is mapped onto a circle, and the resulting torus is sampled with
+// the Eval4 of the base noise. The circles have a circumference of width and
+// height respectively, so the output has the same feature size as Eval2 of the
+// base noise would have.
+type Tileable2D struct {
+	base Noise
+
+	width, height float64
+	rx, ry        float64
+}
+
+// NewTileable2D wraps base into a 2D evaluator that repeats with a period of
+// width along x and height along y.
+func NewTileable2D(base Noise, width, height float64) *Tileable2D {
+	if !(width > 0) || !(height > 0) {
+		panic("opensimplex: tileable noise requires a positive width and height")
+	}
+
+	return &Tileable2D{
+		base:   base,
+		width:  width,
+		height: height,
+		rx:     width / (2 * math.Pi),
+		ry:     height / (2 * math.Pi),
+	}
+}
+
+// Eval2 returns the value of the base noise's Eval4 on the torus point for x, y.
+func (t *Tileable2D) Eval2(x, y float64) float64 {
+	// Reduce before scaling, so that the angle stays precise far from the
+	// origin and the period is exact.
+	sx, cx := math.Sincos(2 * math.Pi * math.Mod(x, t.width) / t.width)
+	sy, cy := math.Sincos(2 * math.Pi * math.Mod(y, t.height) / t.height)
+
+	return t.base.Eval4(cx*t.rx, sx*t.rx, cy*t.ry, sy*t.ry)
+}
+
+// Eval2Normalized is like Eval2, but maps the raw output of a base noise
+// created by New into the range [0, 1).
+//
+// The torus is a slice of 4D space, so its values follow the distribution of
+// Eval4 rather than Eval2, and the Eval4 normalization constants apply. Do not
+// use it with a base that is already normalized.
+func (t *Tileable2D) Eval2Normalized(x, y float64) float64 {
+	r := t.Eval2(x, y)
+	return (r + normMin4) * normScale4
+}
@@ -0,0 +1,36 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTileable2DWraps(t *testing.T) {
+	tile := NewTileable2D(New(5), 64, 32)
+
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		x, y := r.Float64()*64, r.Float64()*32
+		v := tile.Eval2(x, y)
+		for _, w := range []float64{tile.Eval2(x+64, y), tile.Eval2(x, y-32), tile.Eval2(x+640, y+320)} {
+			if math.Abs(v-w) > 1e-9 {
+				t.Fatalf("tileable value at %v, %v does not wrap: %v != %v", x, y, v, w)
+			}
+		}
+	}
+}
+
+func TestTileable2DNormalizedRange(t *testing.T) {
+	tile := NewTileable2D(New(5), 16, 16)
+
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		v := tile.Eval2Normalized(r.Float64()*16, r.Float64()*16)
+		if v < 0 || v >= 1 {
+			t.Fatalf("normalized tileable value %v out of [0, 1)", v)
+		}
+	}
+}
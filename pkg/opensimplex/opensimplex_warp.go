@@ -0,0 +1,86 @@
+package opensimplex
+
+// Warped offsets the coordinates of a base noise by a vector sampled from a
+// warp noise before evaluating it (domain warping, as popularized by Inigo
+// Quilez): f(p + Amplitude*h(p)). With more iterations the warp is applied to
+// its own output, f(p + Amplitude*h(p + Amplitude*h(p))) for two.
+//
+// Each component of the offset vector samples the warp noise at a different,
+// fixed offset of the point, so the components are decorrelated from each
+// other, and from the base noise when warp and base are the same instance. The
+// warp noise should be centered on zero, like New.
+type Warped struct {
+	base, warp Noise
+
+	// Amplitude scales the offset vector sampled from the warp noise.
+	Amplitude float64
+	// Iterations is the number of times the warp is applied.
+	Iterations int
+}
+
+// NewWarped wraps base into a Noise whose coordinates are offset by warp, scaled
+// by amplitude, applied iterations times.
+func NewWarped(base, warp Noise, amplitude float64, iterations int) *Warped {
+	if iterations < 1 {
+		panic("opensimplex: Warped requires at least one iteration")
+	}
+
+	return &Warped{
+		base:       base,
+		warp:       warp,
+		Amplitude:  amplitude,
+		Iterations: iterations,
+	}
+}
+
+// warpOffsets holds, for each component of the offset vector, the point the
+// warp noise is sampled at relative to the warped point. They are arbitrary,
+// but far enough apart for the samples to be uncorrelated.
+var warpOffsets = [4][4]float64{
+	{1.7, 9.2, 8.3, 2.8},
+	{5.2, 1.3, 7.1, 2.9},
+	{8.3, 2.8, 4.6, 6.5},
+	{3.4, 6.1, 1.9, 9.7},
+}
+
+// Eval2 returns the warped noise value in two dimensions.
+func (w *Warped) Eval2(x, y float64) float64 {
+	o := &warpOffsets
+	qx, qy := x, y
+	for i := 0; i < w.Iterations; i++ {
+		hx := w.warp.Eval2(qx+o[0][0], qy+o[0][1])
+		hy := w.warp.Eval2(qx+o[1][0], qy+o[1][1])
+		qx, qy = x+w.Amplitude*hx, y+w.Amplitude*hy
+	}
+
+	return w.base.Eval2(qx, qy)
+}
+
+// Eval3 returns the warped noise value in three dimensions.
+func (w *Warped) Eval3(x, y, z float64) float64 {
+	o := &warpOffsets
+	qx, qy, qz := x, y, z
+	for i := 0; i < w.Iterations; i++ {
+		hx := w.warp.Eval3(qx+o[0][0], qy+o[0][1], qz+o[0][2])
+		hy := w.warp.Eval3(qx+o[1][0], qy+o[1][1], qz+o[1][2])
+		hz := w.warp.Eval3(qx+o[2][0], qy+o[2][1], qz+o[2][2])
+		qx, qy, qz = x+w.Amplitude*hx, y+w.Amplitude*hy, z+w.Amplitude*hz
+	}
+
+	return w.base.Eval3(qx, qy, qz)
+}
+
+// Eval4 returns the warped noise value in four dimensions.
+func (w *Warped) Eval4(x, y, z, t float64) float64 {
+	o := &warpOffsets
+	qx, qy, qz, qt := x, y, z, t
+	for i := 0; i < w.Iterations; i++ {
+		hx := w.warp.Eval4(qx+o[0][0], qy+o[0][1], qz+o[0][2], qt+o[0][3])
+		hy := w.warp.Eval4(qx+o[1][0], qy+o[1][1], qz+o[1][2], qt+o[1][3])
+		hz := w.warp.Eval4(qx+o[2][0], qy+o[2][1], qz+o[2][2], qt+o[2][3])
+		ht := w.warp.Eval4(qx+o[3][0], qy+o[3][1], qz+o[3][2], qt+o[3][3])
+		qx, qy, qz, qt = x+w.Amplitude*hx, y+w.Amplitude*hy, z+w.Amplitude*hz, t+w.Amplitude*ht
+	}
+
+	return w.base.Eval4(qx, qy, qz, qt)
+}
@@ -0,0 +1,111 @@
+package opensimplex
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// recordNoise records the points it is evaluated at and returns a value
+// derived from them.
+type recordNoise struct {
+	points [][4]float64
+}
+
+func (r *recordNoise) record(p [4]float64) float64 {
+	r.points = append(r.points, p)
+	return math.Sin(p[0] + 2*p[1] + 3*p[2] + 4*p[3])
+}
+
+func (r *recordNoise) Eval2(x, y float64) float64       { return r.record([4]float64{x, y}) }
+func (r *recordNoise) Eval3(x, y, z float64) float64    { return r.record([4]float64{x, y, z}) }
+func (r *recordNoise) Eval4(x, y, z, w float64) float64 { return r.record([4]float64{x, y, z, w}) }
+
+func TestWarpedZeroAmplitude(t *testing.T) {
+	base := New(1)
+	w := NewWarped(base, New(2), 0, 3)
+	// #nosec: G404
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		x, y, z, u := r.Float64()*100, r.Float64()*100, r.Float64()*100, r.Float64()*100
+		if w.Eval2(x, y) != base.Eval2(x, y) || w.Eval3(x, y, z) != base.Eval3(x, y, z) || w.Eval4(x, y, z, u) != base.Eval4(x, y, z, u) {
+			t.Fatalf("zero amplitude warp changes the noise at %v, %v, %v, %v", x, y, z, u)
+		}
+	}
+}
+
+func TestWarpedIterations(t *testing.T) {
+	base, warp := New(1), New(2)
+	x, y, a := 3.7, -1.2, 0.8
+
+	h := func(qx, qy float64) (float64, float64) {
+		return warp.Eval2(qx+warpOffsets[0][0], qy+warpOffsets[0][1]), warp.Eval2(qx+warpOffsets[1][0], qy+warpOffsets[1][1])
+	}
+	hx, hy := h(x, y)
+	if got, want := NewWarped(base, warp, a, 1).Eval2(x, y), base.Eval2(x+a*hx, y+a*hy); got != want {
+		t.Errorf("one iteration: got %v, want %v", got, want)
+	}
+	hx, hy = h(x+a*hx, y+a*hy)
+	if got, want := NewWarped(base, warp, a, 2).Eval2(x, y), base.Eval2(x+a*hx, y+a*hy); got != want {
+		t.Errorf("two iterations: got %v, want %v", got, want)
+	}
+}
+
+func TestWarpedDecorrelatesComponents(t *testing.T) {
+	for dims := 2; dims <= 4; dims++ {
+		rec := &recordNoise{}
+		w := NewWarped(rec, rec, 1, 1)
+		switch dims {
+		case 2:
+			w.Eval2(0.5, 0.25)
+		case 3:
+			w.Eval3(0.5, 0.25, 0.125)
+		case 4:
+			w.Eval4(0.5, 0.25, 0.125, 0.0625)
+		}
+
+		// One sample per component plus the base evaluation, all at distinct
+		// points.
+		if len(rec.points) != dims+1 {
+			t.Fatalf("%dD: warp evaluated %d times, want %d", dims, len(rec.points), dims+1)
+		}
+		for i := range rec.points {
+			for j := i + 1; j < len(rec.points); j++ {
+				if rec.points[i] == rec.points[j] {
+					t.Errorf("%dD: samples %d and %d share the point %v", dims, i, j, rec.points[i][:dims])
+				}
+			}
+		}
+	}
+
+	// The offset components of a real noise should be uncorrelated.
+	warp := New(9)
+	// #nosec: G404
+	r := rand.New(rand.NewSource(2))
+	var sxy, sxx, syy float64
+	for i := 0; i < 10000; i++ {
+		x, y, z := r.Float64()*1000, r.Float64()*1000, r.Float64()*1000
+		hx := warp.Eval3(x+warpOffsets[0][0], y+warpOffsets[0][1], z+warpOffsets[0][2])
+		hy := warp.Eval3(x+warpOffsets[1][0], y+warpOffsets[1][1], z+warpOffsets[1][2])
+		sxy, sxx, syy = sxy+hx*hy, sxx+hx*hx, syy+hy*hy
+	}
+	if c := sxy / math.Sqrt(sxx*syy); math.Abs(c) > 0.05 {
+		t.Errorf("offset components are correlated: %v", c)
+	}
+}
+
+func TestNewWarpedPanicsWithoutIterations(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewWarped accepted zero iterations")
+		}
+	}()
+	NewWarped(New(0), New(1), 1, 0)
+}
+
+func BenchmarkWarpedEval2(b *testing.B) {
+	w := NewWarped(New(0), NewFBM(New(1), 4, 2, 0.5), 4, 2)
+	for i := 0; i < b.N; i++ {
+		w.Eval2(float64(i)*0.01, 0.5)
+	}
+}